@@ -1,22 +1,56 @@
 package main
 
 import (
-  "flag"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam"
 )
 
 func main() {
-  var debug bool
+	var debug, export bool
+	var exportOpts phpipam.ExportOptions
+	config := phpipam.DefaultConfig()
 
-  flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
-  flag.Parse()
+	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
+	flag.BoolVar(&export, "export", false, "set to true to write Terraform configuration and import blocks for the objects of an existing PHPIPAM instead of running the provider")
+	flag.StringVar(&exportOpts.Dir, "export-dir", ".", "the directory that -export writes .tf files to")
+	flag.StringVar(&exportOpts.Section, "export-section", "", "the name of the section that -export is limited to")
+	flag.StringVar(&exportOpts.Subnet, "export-subnet", "", "the CIDR or ID of the subnet whose tree -export is limited to")
+	flag.BoolVar(&config.Insecure, "insecure", false, "set to true to skip the validation of the PHPIPAM TLS certificate with -export")
+	flag.IntVar(&config.Retry.MaxAttempts, "retry-max-attempts", config.Retry.MaxAttempts, "the number of attempts for requests of -export that fail with transient errors")
+	flag.DurationVar(&config.Retry.MinBackoff, "retry-min-backoff", config.Retry.MinBackoff, "the delay before the first retry of a request of -export")
+	flag.DurationVar(&config.Retry.MaxBackoff, "retry-max-backoff", config.Retry.MaxBackoff, "the maximum delay between retries of a request of -export")
+	flag.Parse()
 
-  opts := &plugin.ServeOpts{
-    Debug:        debug,
-		ProviderFunc: phpipam.Provider,
-  }
+	if export {
+		// The connection settings are read from the PHPIPAM_APP_ID,
+		// PHPIPAM_ENDPOINT_ADDR, PHPIPAM_PASSWORD and PHPIPAM_USER_NAME
+		// environment variables.
+		if err := phpipam.Export(config, exportOpts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// The provider is served through terraform-plugin-mux while it is moved
+	// to terraform-plugin-framework. Only phpipam_vrf is implemented with the
+	// framework so far.
+	server, err := phpipam.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := &plugin.ServeOpts{
+		Debug:            debug,
+		ProviderAddr:     "registry.terraform.io/lord-kyron/phpipam",
+		GRPCProviderFunc: server,
+	}
 
 	plugin.Serve(opts)
 }
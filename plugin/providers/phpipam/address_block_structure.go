@@ -0,0 +1,144 @@
+package phpipam
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+)
+
+// resourceAddressBlockOptionalFields represents all the fields that are
+// optional in the phpipam_address_block resource. These fields get flagged as
+// Optional, with zero value defaults (the field is not set), in addition to
+// being marked as Computed. Any field not listed here cannot be supplied by
+// the resource and is solely computed.
+var resourceAddressBlockOptionalFields = linearSearchSlice{
+	"description",
+	"owner",
+	"note",
+}
+
+// bareAddressBlockSchema returns a map[string]*schema.Schema with the schema
+// used to represent a block of PHPIPAM addresses.
+func bareAddressBlockSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"subnet_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"address_count": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"contiguous": &schema.Schema{
+			Type: schema.TypeBool,
+		},
+		"description": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"owner": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"note": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"ip_addresses": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+		"address_ids": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeInt},
+		},
+	}
+}
+
+// resourceAddressBlockSchema returns the schema for the phpipam_address_block
+// resource. It sets the required and optional fields, the latter defined in
+// resourceAddressBlockOptionalFields, and ensures that all optional and
+// non-configurable fields are computed as well.
+func resourceAddressBlockSchema() map[string]*schema.Schema {
+	s := bareAddressBlockSchema()
+	for k, v := range s {
+		switch {
+		// The subnet and the number of addresses are ForceNew
+		case k == "subnet_id" || k == "address_count":
+			v.Required = true
+			v.ForceNew = true
+		// Whether the addresses are contiguous only matters when they are
+		// allocated, so changing it forces a new block, see
+		// resourcePHPIPAMAddressBlockCustomizeDiff.
+		case k == "contiguous":
+			v.Optional = true
+			v.Default = false
+		case resourceAddressBlockOptionalFields.Has(k):
+			v.Optional = true
+			v.Computed = true
+		default:
+			v.Computed = true
+		}
+	}
+	s["address_count"].ValidateFunc = validation.IntAtLeast(1)
+	s["configured_fields"] = configuredFieldsSchema()
+	return s
+}
+
+// resourceAddressBlockClearableFields are the fields of the
+// phpipam_address_block resource that are cleared on every address of the
+// block when they are removed from the configuration, see clearableFields.
+var resourceAddressBlockClearableFields = clearableFields{
+	"description": "description",
+	"owner":       "owner",
+	"note":        "note",
+}
+
+// expandAddressBlock returns the addresses.Address structure that every
+// address of a phpipam_address_block resource is created from, or updated
+// with.
+func expandAddressBlock(d *schema.ResourceData) addresses.Address {
+	a := addresses.Address{
+		SubnetID:    d.Get("subnet_id").(int),
+		Description: d.Get("description").(string),
+		Owner:       d.Get("owner").(string),
+		Note:        d.Get("note").(string),
+	}
+
+	return a
+}
+
+// flattenAddressBlock sets fields in a *schema.ResourceData with the
+// addresses of a block. The shared fields are read from the first address.
+func flattenAddressBlock(block []addresses.Address, d *schema.ResourceData) {
+	ids := make([]string, 0, len(block))
+	addressIDs := make([]int, 0, len(block))
+	ips := make([]string, 0, len(block))
+	for _, v := range block {
+		ids = append(ids, strconv.Itoa(v.ID))
+		addressIDs = append(addressIDs, v.ID)
+		ips = append(ips, v.IPAddress)
+	}
+
+	d.SetId(strings.Join(ids, ","))
+	d.Set("subnet_id", block[0].SubnetID)
+	d.Set("address_count", len(block))
+	d.Set("description", block[0].Description)
+	d.Set("owner", block[0].Owner)
+	d.Set("note", block[0].Note)
+	d.Set("ip_addresses", ips)
+	d.Set("address_ids", addressIDs)
+}
+
+// addressBlockIDs returns the address IDs of a phpipam_address_block
+// resource, as stored in its ID.
+func addressBlockIDs(id string) ([]int, error) {
+	var ids []int
+	for _, v := range strings.Split(id, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("Invalid address block ID %q, expected a comma-separated list of address IDs", id)
+		}
+		ids = append(ids, n)
+	}
+	return ids, nil
+}
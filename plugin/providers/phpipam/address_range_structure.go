@@ -0,0 +1,298 @@
+package phpipam
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+)
+
+// maxAddressRangeSize is the maximum number of addresses in a
+// phpipam_address_range resource, as every address in the range is created
+// with its own request.
+const maxAddressRangeSize = 4096
+
+// resourceAddressRangeOptionalFields represents all the fields that are
+// optional in the phpipam_address_range resource. These fields get flagged as
+// Optional, with zero value defaults (the field is not set), in addition to
+// being marked as Computed. Any field not listed here cannot be supplied by
+// the resource and is solely computed.
+var resourceAddressRangeOptionalFields = linearSearchSlice{
+	"description",
+	"owner",
+	"note",
+}
+
+// bareAddressRangeSchema returns a map[string]*schema.Schema with the schema
+// used to represent a range of PHPIPAM addresses.
+func bareAddressRangeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"subnet_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"start": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"end": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"state_tag": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"state_tag_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"description": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"hostname_template": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"owner": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"note": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"address_count": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"ip_addresses": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+		"address_ids": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeInt},
+		},
+		"hostnames": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+// resourceAddressRangeSchema returns the schema for the phpipam_address_range
+// resource. It sets the required and optional fields, the latter defined in
+// resourceAddressRangeOptionalFields, and ensures that all optional and
+// non-configurable fields are computed as well.
+func resourceAddressRangeSchema() map[string]*schema.Schema {
+	s := bareAddressRangeSchema()
+	for k, v := range s {
+		switch {
+		// The subnet and the bounds of the range are ForceNew
+		case k == "subnet_id" || k == "start" || k == "end":
+			v.Required = true
+			v.ForceNew = true
+		// Address ranges are mostly used for DHCP scopes, so they are
+		// reserved unless another tag is supplied.
+		case k == "state_tag":
+			v.Optional = true
+			v.Default = "Reserved"
+		// The host name template is not stored in PHPIPAM, and cannot be
+		// read back.
+		case k == "hostname_template":
+			v.Optional = true
+		case resourceAddressRangeOptionalFields.Has(k):
+			v.Optional = true
+			v.Computed = true
+		default:
+			v.Computed = true
+		}
+	}
+	s["start"].ValidateFunc = validation.IsIPAddress
+	s["end"].ValidateFunc = validation.IsIPAddress
+	s["configured_fields"] = configuredFieldsSchema()
+	return s
+}
+
+// resourceAddressRangeClearableFields are the fields of the
+// phpipam_address_range resource that are cleared on every address of the
+// range when they are removed from the configuration, see clearableFields.
+var resourceAddressRangeClearableFields = clearableFields{
+	"description": "description",
+	"owner":       "owner",
+	"note":        "note",
+}
+
+// expandAddressRange returns the addresses.Address structure that every
+// address of a phpipam_address_range resource is created from, or updated
+// with. The state tag ID must have been resolved from its name already.
+func expandAddressRange(d *schema.ResourceData) addresses.Address {
+	a := addresses.Address{
+		SubnetID:    d.Get("subnet_id").(int),
+		Description: d.Get("description").(string),
+		Owner:       d.Get("owner").(string),
+		Note:        d.Get("note").(string),
+		Tag:         d.Get("state_tag_id").(int),
+	}
+
+	return a
+}
+
+// flattenAddressRange sets fields in a *schema.ResourceData with the
+// addresses of a range, which must be sorted by IP address. The shared fields
+// are read from the first address.
+func flattenAddressRange(subnetID int, start, end string, list []addresses.Address, d *schema.ResourceData) {
+	ids := make([]int, 0, len(list))
+	ips := make([]string, 0, len(list))
+	hostnames := make([]string, 0, len(list))
+	for _, v := range list {
+		ids = append(ids, v.ID)
+		ips = append(ips, v.IPAddress)
+		hostnames = append(hostnames, v.Hostname)
+	}
+
+	d.SetId(addressRangeID(subnetID, start, end))
+	d.Set("subnet_id", subnetID)
+	d.Set("start", start)
+	d.Set("end", end)
+	d.Set("state_tag_id", list[0].Tag)
+	d.Set("description", list[0].Description)
+	d.Set("owner", list[0].Owner)
+	d.Set("note", list[0].Note)
+	d.Set("address_count", len(list))
+	d.Set("ip_addresses", ips)
+	d.Set("address_ids", ids)
+	d.Set("hostnames", hostnames)
+}
+
+// addressRangeID returns the ID of a phpipam_address_range resource, which is
+// also the format used to import a range: subnetID:start-end.
+func addressRangeID(subnetID int, start, end string) string {
+	return fmt.Sprintf("%d:%s-%s", subnetID, start, end)
+}
+
+// parseAddressRangeID returns the subnet ID and the bounds of a
+// phpipam_address_range resource, as stored in its ID.
+func parseAddressRangeID(id string) (int, string, string, error) {
+	// IPv6 addresses contain colons, so the subnet ID is split off at the
+	// first one. Neither contains dashes.
+	subnet, bounds, ok := strings.Cut(id, ":")
+	start, end, hasEnd := strings.Cut(bounds, "-")
+	subnetID, err := strconv.Atoi(subnet)
+	if !ok || !hasEnd || err != nil {
+		return 0, "", "", fmt.Errorf("Invalid address range ID %q, expected subnetID:start-end", id)
+	}
+	if _, err := addressRange(start, end); err != nil {
+		return 0, "", "", fmt.Errorf("Invalid address range ID %q: %s", id, err)
+	}
+	return subnetID, start, end, nil
+}
+
+// addressRange returns the IP addresses from start to end, inclusive.
+func addressRange(start, end string) ([]netip.Addr, error) {
+	first, err := netip.ParseAddr(start)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid start address %q: %s", start, err)
+	}
+	last, err := netip.ParseAddr(end)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid end address %q: %s", end, err)
+	}
+	switch {
+	case first.Is4() != last.Is4():
+		return nil, fmt.Errorf("Address range %s-%s mixes IPv4 and IPv6 addresses", start, end)
+	case first.Compare(last) > 0:
+		return nil, fmt.Errorf("Address range %s-%s ends before it starts", start, end)
+	}
+
+	var out []netip.Addr
+	for ip := first; ip.IsValid() && ip.Compare(last) <= 0; ip = ip.Next() {
+		if len(out) == maxAddressRangeSize {
+			return nil, fmt.Errorf("Address range %s-%s has more than %d addresses", start, end, maxAddressRangeSize)
+		}
+		out = append(out, ip)
+	}
+	return out, nil
+}
+
+// addressRangeInSubnet returns the IP addresses from start to end, checking
+// that they are all host addresses of the subnet cidr. As in PHPIPAM, the
+// network and broadcast addresses of IPv4 subnets larger than a /31 are not
+// host addresses.
+func addressRangeInSubnet(cidr, start, end string) ([]string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid subnet %q: %s", cidr, err)
+	}
+	prefix = prefix.Masked()
+	ips, err := addressRange(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := prefix.Addr(), prefix.Addr()
+	for i := prefix.Bits(); i < last.BitLen(); i++ {
+		last = setBit(last, i)
+	}
+	if prefix.Addr().Is4() && prefix.Bits() < 31 {
+		first, last = first.Next(), last.Prev()
+	}
+
+	out := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if !prefix.Contains(ip) || ip.Compare(first) < 0 || ip.Compare(last) > 0 {
+			return nil, fmt.Errorf("Address range %s-%s is not within the host addresses of subnet %s", start, end, cidr)
+		}
+		out = append(out, ip.String())
+	}
+	return out, nil
+}
+
+// addressesInRange returns the addresses of list that are within the range
+// from start to end, sorted by IP address.
+func addressesInRange(list []addresses.Address, start, end string) []addresses.Address {
+	first, _ := netip.ParseAddr(start)
+	last, _ := netip.ParseAddr(end)
+	out := make([]addresses.Address, 0)
+	for _, v := range list {
+		ip, err := netip.ParseAddr(v.IPAddress)
+		if err == nil && ip.Compare(first) >= 0 && ip.Compare(last) <= 0 {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, _ := netip.ParseAddr(out[i].IPAddress)
+		b, _ := netip.ParseAddr(out[j].IPAddress)
+		return a.Less(b)
+	})
+	return out
+}
+
+// addressesWithIDs returns the addresses of list whose IDs are in ids, a list
+// of address IDs from the state.
+func addressesWithIDs(list []addresses.Address, ids []interface{}) []addresses.Address {
+	wanted := make(map[int]bool)
+	for _, v := range ids {
+		wanted[v.(int)] = true
+	}
+	out := make([]addresses.Address, 0, len(list))
+	for _, v := range list {
+		if wanted[v.ID] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// addressRangeIDs returns ids, a list of address IDs from the state, with the
+// IDs of the addresses in created appended.
+func addressRangeIDs(ids []interface{}, created []addresses.Address) []interface{} {
+	out := append([]interface{}{}, ids...)
+	for _, v := range created {
+		out = append(out, v.ID)
+	}
+	return out
+}
+
+// addressRangeHostname returns the host name of the nth address of a range,
+// counting from 1, from template, in which {n} is replaced with n.
+func addressRangeHostname(template string, n int) string {
+	return strings.ReplaceAll(template, "{n}", strconv.Itoa(n))
+}
@@ -1,10 +1,14 @@
 package phpipam
 
 import (
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
 	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
 )
@@ -21,6 +25,7 @@ var resourceAddressOptionalFields = linearSearchSlice{
 	"mac_address",
 	"owner",
 	"state_tag_id",
+	"state_tag",
 	"skip_ptr_record",
 	"ptr_record_id",
 	"device_id",
@@ -29,6 +34,22 @@ var resourceAddressOptionalFields = linearSearchSlice{
 	"exclude_ping",
 }
 
+// resourceAddressClearableFields are the optional fields of the
+// phpipam_address resource that are cleared when removed from the
+// configuration, by their names in the API. See clearableFields.
+var resourceAddressClearableFields = clearableFields{
+	"is_gateway":        "is_gateway",
+	"description":       "description",
+	"hostname":          "hostname",
+	"mac_address":       "mac",
+	"owner":             "owner",
+	"skip_ptr_record":   "PTRIgnore",
+	"device_id":         "deviceId",
+	"switch_port_label": "port",
+	"note":              "note",
+	"exclude_ping":      "excludePing",
+}
+
 // bareAddressSchema returns a map[string]*schema.Schema with the schema used
 // to represent a PHPIPAM address resource. This output should then be modified
 // so that required and computed fields are set properly for both the data
@@ -62,6 +83,9 @@ func bareAddressSchema() map[string]*schema.Schema {
 		"state_tag_id": &schema.Schema{
 			Type: schema.TypeInt,
 		},
+		"state_tag": &schema.Schema{
+			Type: schema.TypeString,
+		},
 		"skip_ptr_record": &schema.Schema{
 			Type: schema.TypeBool,
 		},
@@ -117,6 +141,9 @@ func resourceAddressSchema() map[string]*schema.Schema {
 			v.Computed = true
 		}
 	}
+	// The state tag can be supplied either by ID or by name, but not both.
+	s["state_tag_id"].ConflictsWith = []string{"state_tag"}
+	s["state_tag"].ConflictsWith = []string{"state_tag_id"}
 	// Add the remove_dns_on_delete item to the schema. This is a meta-parameter
 	// that is not part of the API resource and exists to instruct PHPIPAM to
 	// gracefully remove the address from its DNS integrations as well when it is
@@ -126,9 +153,41 @@ func resourceAddressSchema() map[string]*schema.Schema {
 		Optional: true,
 		Default:  true,
 	}
+	// Add the allocation_key item to the schema. This is a meta-parameter that
+	// makes allocations deterministic: when no IP address is supplied, an
+	// existing address in the subnet with the same value of this field is
+	// reused before a free address is allocated.
+	s["allocation_key"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateAddressAllocationKey,
+	}
+	// Add the keep_on_destroy item to the schema. This is a meta-parameter that
+	// keeps the address in PHPIPAM when the resource is destroyed, so that it
+	// is reused through its allocation_key when the resource is created again.
+	s["keep_on_destroy"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+	s["adopt_existing"] = adoptExistingSchema()
+	s["configured_fields"] = configuredFieldsSchema()
 	return s
 }
 
+// addressAllocationKeys are the fields that the allocation_key of the
+// phpipam_address resource can be set to, besides custom fields.
+var addressAllocationKeys = []string{"hostname", "mac_address", "description", "owner"}
+
+// validateAddressAllocationKey validates the allocation_key of the
+// phpipam_address resource, which must be one of addressAllocationKeys or the
+// name of a custom field.
+func validateAddressAllocationKey(v interface{}, k string) ([]string, []error) {
+	if strings.HasPrefix(v.(string), "custom_") {
+		return nil, nil
+	}
+	return validation.StringInSlice(addressAllocationKeys, false)(v, k)
+}
+
 // dataSourceAddressSchema returns the schema for the phpipam_address data
 // source. It sets the searchable fields and sets up the attribute conflicts
 // between IP address and address ID. It also ensures that all fields are
@@ -140,34 +199,57 @@ func dataSourceAddressSchema() map[string]*schema.Schema {
 		case "address_id":
 			v.Optional = true
 			v.Computed = true
-			v.ConflictsWith = []string{"ip_address", "subnet_id", "description", "hostname", "custom_field_filter"}
+			v.ConflictsWith = []string{"ip_address", "subnet_id", "section_id", "description", "hostname", "mac_address", "custom_field_filter"}
 		case "ip_address":
 			v.Optional = true
 			v.Computed = true
-			v.ConflictsWith = []string{"address_id", "subnet_id", "description", "hostname", "custom_field_filter"}
+			v.ConflictsWith = []string{"address_id", "subnet_id", "section_id", "description", "hostname", "mac_address", "custom_field_filter"}
 		case "subnet_id":
 			v.Optional = true
 			v.Computed = true
-			v.ConflictsWith = []string{"ip_address", "address_id"}
+			v.ConflictsWith = []string{"ip_address", "address_id", "section_id"}
 		case "description":
 			v.Optional = true
 			v.Computed = true
-			v.ConflictsWith = []string{"ip_address", "address_id", "hostname", "custom_field_filter"}
+			v.ConflictsWith = []string{"ip_address", "address_id", "hostname", "mac_address", "custom_field_filter"}
 		case "hostname":
 			v.Optional = true
 			v.Computed = true
-			v.ConflictsWith = []string{"ip_address", "address_id", "description", "custom_field_filter"}
+			v.ConflictsWith = []string{"ip_address", "address_id", "description", "mac_address", "custom_field_filter"}
+		case "mac_address":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"ip_address", "address_id", "description", "hostname", "custom_field_filter"}
 		default:
 			v.Computed = true
 		}
 	}
+	// section_id limits host name and MAC address searches to the subnets of
+	// a section. It is not an attribute of addresses, so it is not part of
+	// the bare schema.
+	s["section_id"] = &schema.Schema{
+		Type:          schema.TypeInt,
+		Optional:      true,
+		ConflictsWith: []string{"ip_address", "address_id", "subnet_id"},
+	}
 	// Add the custom_field_filter item to the schema. This is a meta-parameter
 	// that allows searching for a custom field value in the data source.
-	s["custom_field_filter"] = customFieldFilterSchema([]string{"ip_address", "address_id", "hostname", "description"})
+	s["custom_field_filter"] = customFieldFilterSchema([]string{"ip_address", "address_id", "hostname", "description", "mac_address"})
 
 	return s
 }
 
+// dataSourceAddressesSchema returns the sub-schema for the addresses list of
+// the phpipam_addresses data source. All this function does is set all fields
+// as computed.
+func dataSourceAddressesSchema() map[string]*schema.Schema {
+	schema := bareAddressSchema()
+	for _, v := range schema {
+		v.Computed = true
+	}
+	return schema
+}
+
 // expandAddress returns the addresses.Address structure for a
 // phpipam_address resource or data source. Depending on if we are dealing with
 // the resource or data source, extra considerations may need to be taken.
@@ -217,6 +299,32 @@ func flattenAddress(a addresses.Address, d *schema.ResourceData) {
 	d.Set("edit_date", a.EditDate)
 }
 
+// flattenAddressMap returns the fields supplied by the input
+// addresses.Address as a map, in the form used by the addresses list of the
+// phpipam_addresses data source. Custom fields and the state tag name are not
+// part of addresses.Address, and are left to the caller.
+func flattenAddressMap(a addresses.Address) map[string]interface{} {
+	return map[string]interface{}{
+		"address_id":        a.ID,
+		"subnet_id":         a.SubnetID,
+		"ip_address":        a.IPAddress,
+		"is_gateway":        bool(a.IsGateway),
+		"description":       a.Description,
+		"hostname":          a.Hostname,
+		"mac_address":       a.MACAddress,
+		"owner":             a.Owner,
+		"state_tag_id":      a.Tag,
+		"skip_ptr_record":   bool(a.PTRIgnore),
+		"ptr_record_id":     a.PTRRecordID,
+		"device_id":         a.DeviceID,
+		"switch_port_label": a.Port,
+		"note":              a.Note,
+		"last_seen":         a.LastSeen,
+		"exclude_ping":      bool(a.ExcludePing),
+		"edit_date":         a.EditDate,
+	}
+}
+
 // addressSearchInSubnet provides the address search functionality for both the
 // phpipam_address and phpipam_addresses data sources, returning an
 // []addresses.Address to the particular data source that is calling the
@@ -260,3 +368,164 @@ func addressSearchInSubnet(d *schema.ResourceData, meta interface{}) ([]addresse
 	}
 	return result, nil
 }
+
+// normalizeMACAddress returns mac in the lower case, colon separated notation,
+// such as 00:1a:2b:3c:4d:5e. mac can be in colon (00:1A:2B:3C:4D:5E), dash
+// (00-1A-2B-3C-4D-5E), Cisco dot (001a.2b3c.4d5e) or unseparated notation.
+func normalizeMACAddress(mac string) (string, error) {
+	digits := strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(mac))
+	if len(digits) != 12 {
+		return "", fmt.Errorf("Invalid MAC address %q", mac)
+	}
+	if _, err := hex.DecodeString(digits); err != nil {
+		return "", fmt.Errorf("Invalid MAC address %q", mac)
+	}
+	parts := make([]string, 0, 6)
+	for i := 0; i < 12; i += 2 {
+		parts = append(parts, digits[i:i+2])
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// addressSearchAcrossSubnets provides the host name and MAC address lookups of
+// the phpipam_address data source. Unlike addressSearchInSubnet, these search
+// all subnets, using the search endpoints of the PHPIPAM API, and the results
+// are then limited to subnet_id or section_id when either is set.
+func addressSearchAcrossSubnets(d *schema.ResourceData, meta interface{}) ([]addresses.Address, error) {
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	var list []addresses.Address
+	var err error
+	mac := d.Get("mac_address").(string)
+	if mac != "" {
+		if mac, err = normalizeMACAddress(mac); err != nil {
+			return nil, err
+		}
+		list, err = c.SearchAddressesByMAC(mac)
+	} else {
+		list, err = c.SearchAddressesByHostname(d.Get("hostname").(string))
+	}
+	if err != nil && apiErrorCode(err) != 404 {
+		return nil, err
+	}
+
+	var subnetIDs map[int]bool
+	switch {
+	case d.Get("subnet_id").(int) != 0:
+		subnetIDs = map[int]bool{d.Get("subnet_id").(int): true}
+	case d.Get("section_id").(int) != 0:
+		id := d.Get("section_id").(int)
+		v, err := meta.(*ProviderPHPIPAMClient).sectionsController.GetSubnetsInSection(id)
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, fmt.Errorf("Could not read subnets of section ID %d: %s", id, err)
+		}
+		subnetIDs = make(map[int]bool)
+		for _, s := range v {
+			subnetIDs[s.ID] = true
+		}
+	}
+
+	result := make([]addresses.Address, 0)
+	for _, r := range list {
+		if subnetIDs != nil && !subnetIDs[r.SubnetID] {
+			continue
+		}
+		// The search endpoint already matches MAC addresses in any notation,
+		// but double-check the result, as older PHPIPAM versions match MAC
+		// addresses as they are stored.
+		if mac != "" {
+			if v, err := normalizeMACAddress(r.MACAddress); err != nil || v != mac {
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// addressSearchError returns the error of a phpipam_address data source
+// lookup by query, which must match exactly one of the addresses in list. When
+// more than one address matches, the error lists them, so that the search can
+// be narrowed down.
+func addressSearchError(query string, list []addresses.Address) error {
+	if len(list) == 0 {
+		return fmt.Errorf("No address found with %s", query)
+	}
+	matches := make([]string, 0, len(list))
+	for _, v := range list {
+		matches = append(matches, fmt.Sprintf("%s (address ID %d, subnet ID %d)", v.IPAddress, v.ID, v.SubnetID))
+	}
+	return fmt.Errorf("%d addresses found with %s: %s. Set subnet_id or section_id to narrow down the search", len(list), query, strings.Join(matches, ", "))
+}
+
+// addressByAllocationKey looks up the address in the subnet of a
+// phpipam_address resource that has the same value of the field named by
+// allocation_key as the resource, so that it can be reused instead of
+// allocating a new one. It returns nil if there is no such address, and an
+// error if there are several.
+func addressByAllocationKey(d *schema.ResourceData, meta interface{}) (*addresses.Address, error) {
+	client := meta.(*ProviderPHPIPAMClient)
+	key := d.Get("allocation_key").(string)
+	custom := strings.HasPrefix(key, "custom_")
+	var value string
+	if custom {
+		if v, ok := d.Get("custom_fields").(map[string]interface{})[key]; ok {
+			value = fmt.Sprint(v)
+		}
+	} else {
+		value = d.Get(key).(string)
+	}
+	if value == "" {
+		return nil, fmt.Errorf("allocation_key is %s, but %s is not set", key, key)
+	}
+	if key == "mac_address" {
+		var err error
+		if value, err = normalizeMACAddress(value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Custom fields are only part of the listed addresses if they are
+	// nested, otherwise PHPIPAM filters the addresses on them.
+	var list []addresses.Address
+	var err error
+	filtered := custom && !client.NestCustomFields
+	if filtered {
+		list, err = client.subnetsController.GetAddressesInSubnetByField(d.Get("subnet_id").(int), key, value)
+	} else {
+		list, err = client.subnetsController.GetAddressesInSubnet(d.Get("subnet_id").(int))
+	}
+	if err != nil && apiErrorCode(err) != 404 {
+		return nil, err
+	}
+	var matches []addresses.Address
+	for _, v := range list {
+		var field string
+		switch {
+		case filtered:
+			field = value
+		case custom:
+			if v.CustomFields[key] != nil {
+				field = fmt.Sprint(v.CustomFields[key])
+			}
+		case key == "hostname":
+			field = v.Hostname
+		case key == "mac_address":
+			field, _ = normalizeMACAddress(v.MACAddress)
+		case key == "description":
+			field = v.Description
+		case key == "owner":
+			field = v.Owner
+		}
+		if field == value {
+			matches = append(matches, v)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &matches[0], nil
+	}
+	return nil, addressSearchError(fmt.Sprintf("%s %q in subnet ID %d", key, value, d.Get("subnet_id").(int)), matches)
+}
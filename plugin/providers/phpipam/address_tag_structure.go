@@ -0,0 +1,139 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/tags"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+)
+
+// resourceAddressTagOptionalFields represents all the fields that are optional
+// in the phpipam_address_tag resource. These fields get flagged as Optional,
+// with zero value defaults (the field is not set), in addition to being marked
+// as Computed. Any field not listed here cannot be supplied by the resource and
+// is solely computed.
+var resourceAddressTagOptionalFields = linearSearchSlice{
+	"show_tag",
+	"background_color",
+	"foreground_color",
+	"compress",
+	"update_on_scan",
+}
+
+// bareAddressTagSchema returns a map[string]*schema.Schema with the schema
+// used to represent a PHPIPAM IP address tag. This output should then be
+// modified so that required and computed fields are set properly for both the
+// data source and the resource.
+func bareAddressTagSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"tag_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"name": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"show_tag": &schema.Schema{
+			Type: schema.TypeBool,
+		},
+		"background_color": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"foreground_color": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"compress": &schema.Schema{
+			Type: schema.TypeBool,
+		},
+		"update_on_scan": &schema.Schema{
+			Type: schema.TypeBool,
+		},
+		"locked": &schema.Schema{
+			Type: schema.TypeBool,
+		},
+	}
+}
+
+// resourceAddressTagSchema returns the schema for the phpipam_address_tag
+// resource. It sets the required and optional fields, the latter defined in
+// resourceAddressTagOptionalFields, and ensures that all optional and
+// non-configurable fields are computed as well.
+func resourceAddressTagSchema() map[string]*schema.Schema {
+	schema := bareAddressTagSchema()
+	for k, v := range schema {
+		switch {
+		// Tag name is required
+		case k == "name":
+			v.Required = true
+		case resourceAddressTagOptionalFields.Has(k):
+			v.Optional = true
+			v.Computed = true
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// dataSourceAddressTagSchema returns the schema for the phpipam_address_tag
+// data source. It sets the searchable fields and sets up the attribute
+// conflicts between tag ID and name. It also ensures that all fields are
+// computed as well.
+func dataSourceAddressTagSchema() map[string]*schema.Schema {
+	schema := bareAddressTagSchema()
+	for k, v := range schema {
+		switch k {
+		case "tag_id":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"name"}
+		case "name":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"tag_id"}
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// expandAddressTag returns the tags.Tag structure for a phpipam_address_tag
+// resource or data source. Depending on if we are dealing with the resource
+// or data source, extra considerations may need to be taken.
+func expandAddressTag(d *schema.ResourceData) tags.Tag {
+	t := tags.Tag{
+		ID:        d.Get("tag_id").(int),
+		Type:      d.Get("name").(string),
+		ShowTag:   phpipam.BoolIntString(d.Get("show_tag").(bool)),
+		BGColor:   d.Get("background_color").(string),
+		FGColor:   d.Get("foreground_color").(string),
+		Compress:  yesNoString(d.Get("compress").(bool)),
+		UpdateTag: phpipam.BoolIntString(d.Get("update_on_scan").(bool)),
+	}
+
+	return t
+}
+
+// flattenAddressTag sets fields in a *schema.ResourceData with fields supplied
+// by the input tags.Tag. This is used in read operations.
+func flattenAddressTag(t tags.Tag, d *schema.ResourceData) {
+	d.SetId(strconv.Itoa(t.ID))
+	d.Set("tag_id", t.ID)
+	d.Set("name", t.Type)
+	d.Set("show_tag", t.ShowTag)
+	d.Set("background_color", t.BGColor)
+	d.Set("foreground_color", t.FGColor)
+	d.Set("compress", t.Compress == "Yes")
+	d.Set("update_on_scan", t.UpdateTag)
+	d.Set("locked", t.Locked == "Yes")
+}
+
+// yesNoString returns the "Yes" or "No" string that PHPIPAM uses for some of
+// its boolean fields.
+func yesNoString(b bool) string {
+	if b {
+		return "Yes"
+	}
+	return "No"
+}
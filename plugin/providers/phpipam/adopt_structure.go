@@ -0,0 +1,26 @@
+package phpipam
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// adoptExistingSchema returns the schema for the adopt_existing field of the
+// resources that can adopt existing objects. This is a meta-parameter: when
+// set, the resource looks up an object with the same natural key, such as the
+// name of a section, before creating one, and takes ownership of it instead,
+// updating it to match the configuration.
+func adoptExistingSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+}
+
+// adoptExistingError returns the error for a resource with adopt_existing set
+// when its natural key matches n objects of kind, so that none can be adopted.
+func adoptExistingError(kind, key string, n int) error {
+	return fmt.Errorf("Cannot adopt existing %s: %d objects found with %s", kind, n, key)
+}
@@ -0,0 +1,467 @@
+package phpipam
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	mrand "math/rand"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+// AllocationConfig controls how free IP addresses and subnets are allocated.
+//
+// By default, allocations are only serialized within the provider, which
+// does not protect against other Terraform runs allocating from the same
+// subnet at the same time. When Verify is set, every allocation is reserved
+// first, with a unique token as its description, and then verified against
+// the other objects in the subnet: if an object allocated concurrently
+// conflicts with the reservation and was created first (has a lower ID), the
+// reservation is deleted and the allocation is made again.
+type AllocationConfig struct {
+	// Whether allocations are reserved and verified, so that they are safe
+	// across processes.
+	Verify bool
+
+	// The maximum number of allocation attempts, including the first one,
+	// when allocations conflict. Only used when Verify is set.
+	MaxAttempts int
+
+	// The maximum random delay before an allocation is attempted again, so
+	// that clients that conflicted do not keep allocating in lockstep.
+	MaxBackoff time.Duration
+}
+
+// errAllocationConflict is returned when an allocation conflicts with an
+// allocation made concurrently by another client.
+var errAllocationConflict = errors.New("Allocation conflicts with a concurrent allocation")
+
+// allocationTokenPrefix is the description prefix of reserved allocations.
+// Allocations left with such a description were interrupted before being
+// verified, and can safely be deleted.
+const allocationTokenPrefix = "Reserved by Terraform allocation "
+
+// newAllocationToken returns a new unique allocation token.
+func newAllocationToken() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return allocationTokenPrefix + hex.EncodeToString(b)
+}
+
+// isAllocationConflict returns whether err means that an allocation
+// conflicted with another one. PHPIPAM rejects addresses and subnets that
+// conflict with existing ones with a 409; a concurrent insert may also trip
+// over a unique index in the database instead.
+func isAllocationConflict(err error) bool {
+	return err == errAllocationConflict ||
+		apiErrorCode(err) == 409 ||
+		strings.Contains(err.Error(), "Duplicate entry")
+}
+
+// allocate calls f, which must make and verify a single allocation, and
+// calls it again if it conflicts with a concurrent allocation, until it
+// succeeds or MaxAttempts is reached.
+func (c AllocationConfig) allocate(f func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || !isAllocationConflict(err) || attempt >= c.MaxAttempts {
+			return err
+		}
+		var d time.Duration
+		if c.MaxBackoff > 0 {
+			d = time.Duration(mrand.Int63n(int64(c.MaxBackoff)))
+		}
+		log.Printf("[DEBUG] Allocating again in %s after attempt %d of %d conflicted: %s", d, attempt, c.MaxAttempts, err)
+		time.Sleep(d)
+	}
+}
+
+// allocateAddress allocates an IP address in the supplied subnet by calling
+// create, which must create in with a free address and return the address.
+//
+// Allocations are serialized within the provider, and reserved and verified
+// according to the allocation settings.
+func (c *ProviderPHPIPAMClient) allocateAddress(subnetID int, in addresses.Address, create func(addresses.Address) (string, error)) (ip string, err error) {
+	c.addressAllocationLock.Lock()
+	defer c.addressAllocationLock.Unlock()
+
+	if !c.allocation.Verify {
+		return create(in)
+	}
+
+	description := in.Description
+	err = c.allocation.allocate(func() error {
+		in.Description = newAllocationToken()
+		if ip, err = create(in); err != nil {
+			return err
+		}
+		return c.verifyAddress(subnetID, ip, in.Description, description)
+	})
+	return
+}
+
+// verifyAddress verifies the address reserved with token. If an address with
+// the same IP was created before it, the reservation is deleted and
+// errAllocationConflict is returned. Otherwise the reservation's description
+// is set to description.
+func (c *ProviderPHPIPAMClient) verifyAddress(subnetID int, ip, token, description string) error {
+	addrs, err := c.subnetsController.GetAddressesInSubnet(subnetID)
+	if err != nil {
+		return fmt.Errorf("Could not verify allocation of IP address %s: %s", ip, err)
+	}
+
+	var reserved *addresses.Address
+	for i, v := range addrs {
+		if v.IPAddress == ip && v.Description == token {
+			reserved = &addrs[i]
+		}
+	}
+	if reserved == nil {
+		return fmt.Errorf("Could not find allocated IP address %s when verifying it", ip)
+	}
+
+	for _, v := range addrs {
+		if v.IPAddress == ip && v.ID < reserved.ID {
+			log.Printf("[DEBUG] IP address %s was also allocated as address ID %d, releasing address ID %d", ip, v.ID, reserved.ID)
+			if _, err := c.addressesController.DeleteAddress(reserved.ID, false); err != nil {
+				return fmt.Errorf("Could not release conflicting IP address %s: %s", ip, err)
+			}
+			return errAllocationConflict
+		}
+	}
+
+	return c.addressesController.setDescription(reserved.ID, description)
+}
+
+// allocateAddressBlock allocates n free IP addresses in the supplied subnet,
+// creating them from in. If contiguous is set, the addresses are consecutive.
+// If any address cannot be created, the addresses created so far are deleted.
+//
+// The free addresses are worked out from the subnet's existing addresses,
+// so that the whole block takes a single lookup. Allocations are serialized
+// within the provider, and reserved and verified according to the allocation
+// settings, as a whole: a conflict on any address releases the whole block.
+func (c *ProviderPHPIPAMClient) allocateAddressBlock(subnetID, n int, contiguous bool, in addresses.Address) (out []addresses.Address, err error) {
+	c.addressAllocationLock.Lock()
+	defer c.addressAllocationLock.Unlock()
+
+	description := in.Description
+	err = c.allocation.allocate(func() error {
+		subnet, err := c.subnetsController.GetSubnetByID(subnetID)
+		if err != nil {
+			return err
+		}
+		existing, err := c.subnetsController.GetAddressesInSubnet(subnetID)
+		if err != nil && apiErrorCode(err) != 404 {
+			return err
+		}
+		ips, err := freeAddresses(fmt.Sprintf("%s/%d", subnet.SubnetAddress, subnet.Mask), existing, n, contiguous)
+		if err != nil {
+			return err
+		}
+
+		if c.allocation.Verify {
+			in.Description = newAllocationToken()
+		}
+		var created []string
+		for _, ip := range ips {
+			in.IPAddress = ip
+			if _, err = c.addressesController.CreateAddress(in); err != nil {
+				break
+			}
+			created = append(created, ip)
+		}
+
+		out, err = c.findAddressBlock(subnetID, created, in.Description, err)
+		if err != nil {
+			return err
+		}
+		if !c.allocation.Verify {
+			return nil
+		}
+		for _, v := range out {
+			if err := c.addressesController.setDescription(v.ID, description); err != nil {
+				c.releaseAddressBlock(out)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Description = description
+	}
+	return out, nil
+}
+
+// findAddressBlock looks up the addresses of a block after they have been
+// created, reserved with token in verify mode, and returns them in the order
+// of ips. If createErr is not nil, or an address conflicts with one created
+// before it, the whole block is deleted, and createErr or
+// errAllocationConflict is returned.
+func (c *ProviderPHPIPAMClient) findAddressBlock(subnetID int, ips []string, token string, createErr error) ([]addresses.Address, error) {
+	if len(ips) == 0 {
+		return nil, createErr
+	}
+	existing, err := c.subnetsController.GetAddressesInSubnet(subnetID)
+	if err != nil {
+		if createErr != nil {
+			return nil, fmt.Errorf("%s (could not look up the addresses created so far to delete them: %s)", createErr, err)
+		}
+		return nil, fmt.Errorf("Could not read address block after creating: %s", err)
+	}
+
+	out := make([]addresses.Address, 0, len(ips))
+	conflict := false
+	for _, ip := range ips {
+		var found *addresses.Address
+		for i, v := range existing {
+			// Without a token, the latest address with the IP is the one
+			// that was just created.
+			if v.IPAddress == ip && (!c.allocation.Verify || v.Description == token) && (found == nil || v.ID > found.ID) {
+				found = &existing[i]
+			}
+		}
+		if found == nil {
+			c.releaseAddressBlock(out)
+			return nil, fmt.Errorf("Could not find IP address %s after creating it", ip)
+		}
+		out = append(out, *found)
+		if !c.allocation.Verify {
+			continue
+		}
+		for _, v := range existing {
+			if v.IPAddress == ip && v.ID < found.ID {
+				log.Printf("[DEBUG] IP address %s was also allocated as address ID %d, releasing address block", ip, v.ID)
+				conflict = true
+			}
+		}
+	}
+
+	switch {
+	case createErr != nil:
+		c.releaseAddressBlock(out)
+		return nil, createErr
+	case conflict:
+		c.releaseAddressBlock(out)
+		return nil, errAllocationConflict
+	}
+	return out, nil
+}
+
+// releaseAddressBlock deletes the addresses of a block that could not be
+// allocated in full. Failures are logged, as the allocation has failed
+// already.
+func (c *ProviderPHPIPAMClient) releaseAddressBlock(block []addresses.Address) {
+	for _, v := range block {
+		if _, err := c.addressesController.DeleteAddress(v.ID, false); err != nil && apiErrorCode(err) != 404 {
+			log.Printf("[WARN] Could not delete IP address %s, ID %d, of partially allocated address block: %s", v.IPAddress, v.ID, err)
+		}
+	}
+}
+
+// createAddressRange creates the addresses ips in the supplied subnet from in,
+// with the host names in hostnames, after checking that none of them exist
+// yet. If any address cannot be created, the addresses created so far are
+// deleted.
+//
+// Unlike blocks, ranges are not allocated from free addresses, so a conflict
+// with addresses created concurrently fails the range instead of retrying it.
+// The addresses are still reserved and verified according to the allocation
+// settings, so that the conflict is detected.
+func (c *ProviderPHPIPAMClient) createAddressRange(subnetID int, ips, hostnames []string, in addresses.Address) ([]addresses.Address, error) {
+	c.addressAllocationLock.Lock()
+	defer c.addressAllocationLock.Unlock()
+
+	existing, err := c.subnetsController.GetAddressesInSubnet(subnetID)
+	if err != nil && apiErrorCode(err) != 404 {
+		return nil, err
+	}
+	wanted := make(map[string]bool)
+	for _, ip := range ips {
+		wanted[ip] = true
+	}
+	var conflicts []string
+	for _, v := range existing {
+		if wanted[v.IPAddress] {
+			conflicts = append(conflicts, fmt.Sprintf("%s (address ID %d)", v.IPAddress, v.ID))
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("Address range conflicts with existing IP addresses: %s", strings.Join(conflicts, ", "))
+	}
+
+	description := in.Description
+	if c.allocation.Verify {
+		in.Description = newAllocationToken()
+	}
+	var created []string
+	for i, ip := range ips {
+		in.IPAddress = ip
+		in.Hostname = hostnames[i]
+		if _, err = c.addressesController.CreateAddress(in); err != nil {
+			break
+		}
+		created = append(created, ip)
+	}
+
+	out, err := c.findAddressBlock(subnetID, created, in.Description, err)
+	switch {
+	case err == errAllocationConflict:
+		return nil, errors.New("Address range conflicts with IP addresses created concurrently")
+	case err != nil:
+		return nil, err
+	case !c.allocation.Verify:
+		return out, nil
+	}
+	for i, v := range out {
+		if err := c.addressesController.setDescription(v.ID, description); err != nil {
+			c.releaseAddressBlock(out)
+			return nil, err
+		}
+		out[i].Description = description
+	}
+	return out, nil
+}
+
+// freeAddresses returns the first n free host addresses of the subnet cidr,
+// given its existing addresses. If contiguous is set, the addresses returned
+// are consecutive. As in PHPIPAM, the network and broadcast addresses of
+// IPv4 subnets larger than a /31 are not free.
+func freeAddresses(cidr string, existing []addresses.Address, n int, contiguous bool) ([]string, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid subnet %q: %s", cidr, err)
+	}
+	prefix = prefix.Masked()
+	used := make(map[netip.Addr]bool)
+	for _, v := range existing {
+		if ip, err := netip.ParseAddr(v.IPAddress); err == nil {
+			used[ip] = true
+		}
+	}
+
+	ip, last := prefix.Addr(), prefix.Addr()
+	for i := prefix.Bits(); i < last.BitLen(); i++ {
+		last = setBit(last, i)
+	}
+	if prefix.Addr().Is4() && prefix.Bits() < 31 {
+		ip, last = ip.Next(), last.Prev()
+	}
+
+	var out []string
+	for ; len(out) < n && ip.IsValid() && ip.Compare(last) <= 0; ip = ip.Next() {
+		if used[ip] {
+			if contiguous {
+				out = out[:0]
+			}
+			continue
+		}
+		out = append(out, ip.String())
+	}
+	if len(out) < n {
+		if contiguous {
+			return nil, fmt.Errorf("Subnet %s does not have %d contiguous free IP addresses", cidr, n)
+		}
+		return nil, fmt.Errorf("Subnet %s does not have %d free IP addresses", cidr, n)
+	}
+	return out, nil
+}
+
+// setBit returns ip with bit i, counted from the most significant bit, set.
+func setBit(ip netip.Addr, i int) netip.Addr {
+	b := ip.AsSlice()
+	b[i/8] |= 0x80 >> (i % 8)
+	out, _ := netip.AddrFromSlice(b)
+	return out
+}
+
+// allocateSubnet allocates a subnet in the supplied parent subnet by calling
+// create, which must create in as a free child subnet and return its CIDR.
+//
+// Allocations are serialized within the provider, and reserved and verified
+// according to the allocation settings.
+func (c *ProviderPHPIPAMClient) allocateSubnet(parentID int, in subnets.Subnet, create func(subnets.Subnet) (string, error)) (cidr string, err error) {
+	c.subnetAllocationLock.Lock()
+	defer c.subnetAllocationLock.Unlock()
+
+	if !c.allocation.Verify {
+		return create(in)
+	}
+
+	description := in.Description
+	err = c.allocation.allocate(func() error {
+		in.Description = newAllocationToken()
+		if cidr, err = create(in); err != nil {
+			return err
+		}
+		return c.verifySubnet(parentID, cidr, in.Description, description)
+	})
+	return
+}
+
+// verifySubnet verifies the subnet reserved with token. If a subnet that
+// overlaps with it was created in the same parent before it, the reservation
+// is deleted and errAllocationConflict is returned. Otherwise the
+// reservation's description is set to description.
+func (c *ProviderPHPIPAMClient) verifySubnet(parentID int, cidr, token, description string) error {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("Invalid subnet %q allocated: %s", cidr, err)
+	}
+
+	children, err := c.subnetsController.GetSubnetsInSubnet(parentID)
+	if err != nil {
+		return fmt.Errorf("Could not verify allocation of subnet %s: %s", cidr, err)
+	}
+
+	var reserved *subnets.Subnet
+	for i, v := range children {
+		if fmt.Sprintf("%s/%d", v.SubnetAddress, v.Mask) == cidr && v.Description == token {
+			reserved = &children[i]
+		}
+	}
+	if reserved == nil {
+		return fmt.Errorf("Could not find allocated subnet %s when verifying it", cidr)
+	}
+
+	for _, v := range children {
+		p, err := netip.ParsePrefix(fmt.Sprintf("%s/%d", v.SubnetAddress, v.Mask))
+		if err != nil || v.ID >= reserved.ID || !p.Overlaps(prefix) {
+			continue
+		}
+		log.Printf("[DEBUG] Subnet %s overlaps with subnet %s, ID %d, releasing subnet ID %d", cidr, p, v.ID, reserved.ID)
+		if _, err := c.subnetsController.DeleteSubnet(reserved.ID); err != nil {
+			return fmt.Errorf("Could not release conflicting subnet %s: %s", cidr, err)
+		}
+		return errAllocationConflict
+	}
+
+	return c.subnetsController.setDescription(reserved.ID, description)
+}
+
+// setDescription sets the description of an address. Unlike UpdateAddress,
+// this also clears the description when it is empty.
+func (c *addressesController) setDescription(id int, description string) error {
+	return c.retry.retry(func() error {
+		var message string
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/addresses/%d/", id), &map[string]string{"description": description}, &message)
+	})
+}
+
+// setDescription sets the description of a subnet. Unlike UpdateSubnet, this
+// also clears the description when it is empty.
+func (c *subnetsController) setDescription(id int, description string) error {
+	return c.retry.retry(func() error {
+		var message string
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/subnets/%d/", id), &map[string]string{"description": description}, &message)
+	})
+}
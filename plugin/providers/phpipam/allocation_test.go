@@ -0,0 +1,164 @@
+package phpipam
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/fakephpipam"
+)
+
+var testAllocationVerify = map[string]interface{}{
+	"allocation": []interface{}{
+		map[string]interface{}{
+			"mode":        "verify",
+			"max_backoff": "1ms",
+		},
+	},
+}
+
+// testFakeRaceOnce makes the fake server call f before the first request with
+// the supplied method whose path ends with suffix, to simulate another client
+// racing the provider.
+func testFakeRaceOnce(s *fakephpipam.Server, method, suffix string, f func()) {
+	var once sync.Once
+	s.OnRequest(func(r *http.Request) {
+		if r.Method == method && strings.HasSuffix(r.URL.Path, suffix) {
+			once.Do(f)
+		}
+	})
+}
+
+func TestAllocationConflictLocal(t *testing.T) {
+	meta, s := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	testFakeRaceOnce(s, "POST", "/addresses/", func() {
+		s.Insert("addresses", map[string]interface{}{"ip": "10.10.1.1", "subnetId": subnetID})
+	})
+	_, err := testFakeApplyE(meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id": subnetID,
+	})
+	if !strings.Contains(err, "Error from API (409)") {
+		t.Fatalf("expected conflict, got %q", err)
+	}
+}
+
+func TestAllocationConflictVerify(t *testing.T) {
+	meta, s := testFakeProviderMetaRaw(t, testAllocationVerify)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	// The racer creates the address between the GET of the first free
+	// address and the POST, and the POST is rejected.
+	testFakeRaceOnce(s, "POST", "/addresses/", func() {
+		s.Insert("addresses", map[string]interface{}{"ip": "10.10.1.1", "subnetId": subnetID})
+	})
+	state := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id": subnetID,
+	})
+	testFakeCheckAttrs(t, state, map[string]string{
+		"ip_address":  "10.10.1.2",
+		"description": "",
+	})
+}
+
+func TestAllocationDuplicateAddressVerify(t *testing.T) {
+	meta, s := testFakeProviderMetaRaw(t, testAllocationVerify)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	// Free up an ID for the racer, so that its address was created first.
+	state := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.100",
+	})
+	testFakeDestroy(t, meta, "phpipam_address", state)
+
+	// The racer got the same address past PHPIPAM's checks.
+	testFakeRaceOnce(s, "GET", "/addresses/", func() {
+		s.Insert("addresses", map[string]interface{}{"id": state.ID, "ip": "10.10.1.1", "subnetId": subnetID})
+	})
+	state = testFakeApply(t, meta, "phpipam_first_free_address", nil, map[string]interface{}{
+		"subnet_id":   subnetID,
+		"description": "Terraform test first free address",
+	})
+	testFakeCheckAttrs(t, state, map[string]string{
+		"ip_address":  "10.10.1.2",
+		"description": "Terraform test first free address",
+	})
+
+	addrs, err := meta.(*ProviderPHPIPAMClient).subnetsController.GetAddressesInSubnet(subnetID)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if len(addrs) != 2 {
+		t.Fatalf("expected the racer's and the allocated address, got %d addresses", len(addrs))
+	}
+}
+
+func TestAllocationOverlappingSubnetVerify(t *testing.T) {
+	meta, s := testFakeProviderMetaRaw(t, testAllocationVerify)
+	parentID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	parent := meta.(*ProviderPHPIPAMClient).subnetsController
+	p, err := parent.GetSubnetByID(parentID)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// Free up an ID for the racer, so that its subnet was created first.
+	state := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":       p.SectionID,
+		"subnet_address":   "10.10.1.192",
+		"subnet_mask":      26,
+		"master_subnet_id": parentID,
+	})
+	testFakeDestroy(t, meta, "phpipam_subnet", state)
+
+	// The racer got an overlapping subnet past PHPIPAM's checks.
+	testFakeRaceOnce(s, "GET", "/slaves/", func() {
+		s.Insert("subnets", map[string]interface{}{
+			"id":             state.ID,
+			"subnet":         "10.10.1.0",
+			"mask":           25,
+			"sectionId":      p.SectionID,
+			"masterSubnetId": parentID,
+		})
+	})
+	state = testFakeApply(t, meta, "phpipam_first_free_subnet", nil, map[string]interface{}{
+		"parent_subnet_id": parentID,
+		"subnet_mask":      26,
+	})
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_address":   "10.10.1.128",
+		"master_subnet_id": strconv.Itoa(parentID),
+		"description":      "",
+	})
+}
+
+func TestAllocationAddressBlockVerify(t *testing.T) {
+	meta, s := testFakeProviderMetaRaw(t, testAllocationVerify)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	// The racer takes the second address of the block while it is created,
+	// so the whole block is released and allocated again.
+	var posts int
+	s.OnRequest(func(r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/addresses/") {
+			if posts++; posts == 2 {
+				s.Insert("addresses", map[string]interface{}{"ip": "10.10.1.2", "subnetId": subnetID})
+			}
+		}
+	})
+	state := testFakeApply(t, meta, "phpipam_address_block", nil, map[string]interface{}{
+		"subnet_id":     subnetID,
+		"address_count": 3,
+		"description":   "Terraform test address block",
+	})
+	testFakeCheckAttrs(t, state, map[string]string{
+		"ip_addresses.0": "10.10.1.1",
+		"ip_addresses.1": "10.10.1.3",
+		"ip_addresses.2": "10.10.1.4",
+		"description":    "Terraform test address block",
+	})
+}
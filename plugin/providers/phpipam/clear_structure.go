@@ -0,0 +1,198 @@
+package phpipam
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// clearableFields maps the optional fields of a resource that can be cleared
+// to their names in the API.
+//
+// These fields are also computed, so Terraform keeps their value when they are
+// removed from the configuration, and the SDK structures omit empty values
+// from requests, so an update cannot set them back to empty. A clearable field
+// that is explicitly set to its zero value, or that was set in the
+// configuration when the resource was last applied and has been removed from
+// it since, is planned to be cleared instead, see customizeDiff, and the empty
+// value is sent explicitly, see updates.
+//
+// Fields that have never been set in the configuration keep the values that
+// they have in PHPIPAM, such as values set in the UI or by scans. The fields
+// that were set when the resource was last applied are recorded in its
+// configured_fields attribute, see configuredFieldsSchema.
+type clearableFields map[string]string
+
+// configuredFieldsSchema returns the schema for the configured_fields
+// attribute of resources with clearableFields. It lists the clearable fields
+// that were set in the configuration when the resource was last applied,
+// separated by commas. This is a string rather than a list, as the SDK plans
+// computed lists that are missing from the state of resources applied with
+// earlier versions of the provider as unknown on every plan.
+func configuredFieldsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+}
+
+// customizeDiff plans clearing the fields that are set in the state, and that
+// are either explicitly set to their zero value in the configuration, or were
+// configured when the resource was last applied and are not set anymore. It
+// also plans the new value of configured_fields.
+func (f clearableFields) customizeDiff(d *schema.ResourceDiff) error {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	old, _ := d.GetChange("configured_fields")
+	prior := clearableList(old)
+	configured := f.configured(raw)
+	for _, k := range f.keys() {
+		v := raw.GetAttr(k)
+		if d.Id() == "" || !((v.IsNull() && prior.Has(k)) || (!v.IsNull() && clearableCtyZero(v))) {
+			continue
+		}
+		if cur := d.Get(k); cur != clearableZero(cur) {
+			if err := d.SetNew(k, clearableZero(cur)); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Resources applied with earlier versions of the provider have no
+	// configured_fields, and only start recording them with their next
+	// change, so that upgrading does not plan changes by itself.
+	if clearableEqual(prior, configured) {
+		return nil
+	}
+	if d.Id() == "" || len(d.GetChangedKeysPrefix("")) > 0 || !d.GetRawState().GetAttr("configured_fields").IsNull() {
+		return d.SetNew("configured_fields", strings.Join(configured, ","))
+	}
+	return nil
+}
+
+// updates returns the fields that an update needs to clear, by their names in
+// the API, along with the empty values to send for them. These are the fields
+// that customizeDiff plans to clear, and on a new resource, which updates an
+// existing object that it adopts, the fields that are explicitly set to their
+// zero value.
+//
+// The SDK plans cleared strings as unknown values, so the fields to clear are
+// found from the configuration rather than the plan, when it is available.
+func (f clearableFields) updates(d *schema.ResourceData) map[string]interface{} {
+	raw := d.GetRawConfig()
+	old, _ := d.GetChange("configured_fields")
+	prior := clearableList(old)
+	out := make(map[string]interface{})
+	for k, api := range f {
+		o, n := d.GetChange(k)
+		var cleared bool
+		if !raw.IsNull() && raw.IsKnown() {
+			v := raw.GetAttr(k)
+			cleared = (!v.IsNull() && clearableCtyZero(v)) || (v.IsNull() && prior.Has(k) && !d.IsNewResource())
+		} else {
+			cleared = n == clearableZero(n) && d.HasChange(k) && !d.IsNewResource()
+		}
+		if !cleared || (o == clearableZero(o) && !d.IsNewResource()) {
+			continue
+		}
+		// PHPIPAM stores integers and booleans as strings.
+		switch n.(type) {
+		case string:
+			out[api] = ""
+		default:
+			out[api] = "0"
+		}
+	}
+	return out
+}
+
+// setConfigured records the clearable fields that are set in the
+// configuration in configured_fields. The SDK plans an empty string for a
+// computed attribute as an unknown value, so this is set on apply rather than
+// relying on the plan from customizeDiff.
+func (f clearableFields) setConfigured(d *schema.ResourceData) error {
+	raw := d.GetRawConfig()
+	if raw.IsNull() || !raw.IsKnown() {
+		return nil
+	}
+	return d.Set("configured_fields", strings.Join(f.configured(raw), ","))
+}
+
+// configured returns the clearable fields that are set in raw, a
+// configuration, sorted.
+func (f clearableFields) configured(raw cty.Value) linearSearchSlice {
+	out := linearSearchSlice{}
+	for _, k := range f.keys() {
+		if !raw.GetAttr(k).IsNull() {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+// keys returns the names of the fields in f, sorted.
+func (f clearableFields) keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// clearableList returns the value of a configured_fields attribute as a
+// linearSearchSlice.
+func clearableList(v interface{}) linearSearchSlice {
+	out := linearSearchSlice{}
+	if s, _ := v.(string); s != "" {
+		out = strings.Split(s, ",")
+	}
+	return out
+}
+
+// clearableEqual returns true if a and b hold the same fields, in the same
+// order.
+func clearableEqual(a, b linearSearchSlice) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// clearableZero returns the zero value of the type of v.
+func clearableZero(v interface{}) interface{} {
+	switch v.(type) {
+	case string:
+		return ""
+	case bool:
+		return false
+	}
+	return 0
+}
+
+// clearableCtyZero returns true if v, a value from the configuration, is null
+// or the zero value of its type.
+func clearableCtyZero(v cty.Value) bool {
+	switch {
+	case v.IsNull():
+		return true
+	case !v.IsKnown():
+		return false
+	case v.Type() == cty.String:
+		return v.AsString() == ""
+	case v.Type() == cty.Bool:
+		return v.False()
+	case v.Type() == cty.Number:
+		return v.AsBigFloat().Sign() == 0
+	}
+	return false
+}
@@ -4,6 +4,11 @@ import (
 	"log"
 	"sync"
 
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/devices"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/locations"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/nameservers"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/tags"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/vrfs"
 	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
 	"github.com/pavel-z1/phpipam-sdk-go/controllers/l2domains"
 	"github.com/pavel-z1/phpipam-sdk-go/controllers/sections"
@@ -37,6 +42,12 @@ type Config struct {
 
 	// Whether the API client is configured to nest custom fields
 	NestCustomFields bool
+
+	// How requests that fail with transient errors are retried.
+	Retry RetryConfig
+
+	// How free IP addresses and subnets are allocated.
+	Allocation AllocationConfig
 }
 
 // ProviderPHPIPAMClient is a structure that contains the client connections
@@ -44,23 +55,44 @@ type Config struct {
 // subnets.Controller, or addresses.Controller.
 type ProviderPHPIPAMClient struct {
 	// The client for the addresses controller.
-	addressesController *addresses.Controller
+	addressesController *addressesController
 
 	// The client for the sections controller.
-	sectionsController *sections.Controller
+	sectionsController *sectionsController
 
 	// The client for the l2domains controller.
-	l2domainsController *l2domains.Controller
+	l2domainsController *l2domainsController
 
 	// The client for the subnets controller.
-	subnetsController *subnets.Controller
+	subnetsController *subnetsController
 
 	// The client for the vlans controller.
-	vlansController *vlans.Controller
+	vlansController *vlansController
+
+	// The client for the vrf controller.
+	vrfsController *vrfsController
+
+	// The client for the devices controller.
+	devicesController *devicesController
+
+	// The client for the locations controller.
+	locationsController *locationsController
+
+	// The client for the nameservers controller.
+	nameserversController *nameserversController
+
+	// The client for the IP address tags controller.
+	tagsController *tagsController
 
 	// Mutex for free IP address allocation.
 	addressAllocationLock sync.Mutex
 
+	// Mutex for free subnet allocation.
+	subnetAllocationLock sync.Mutex
+
+	// How free IP addresses and subnets are allocated.
+	allocation AllocationConfig
+
 	// Whether the API client is configured to nest custom values
 	NestCustomFields bool
 }
@@ -79,12 +111,18 @@ func (c *Config) Client() (interface{}, error) {
 
 	// Create the client object and return it
 	client := ProviderPHPIPAMClient{
-		addressesController: addresses.NewController(sess),
-		sectionsController:  sections.NewController(sess),
-		l2domainsController: l2domains.NewController(sess),
-		subnetsController:   subnets.NewController(sess),
-		vlansController:     vlans.NewController(sess),
-		NestCustomFields:    c.NestCustomFields,
+		addressesController:   &addressesController{addresses.NewController(sess), c.Retry},
+		sectionsController:    &sectionsController{sections.NewController(sess), c.Retry},
+		l2domainsController:   &l2domainsController{l2domains.NewController(sess), c.Retry},
+		subnetsController:     &subnetsController{subnets.NewController(sess), c.Retry},
+		vlansController:       &vlansController{vlans.NewController(sess), c.Retry},
+		vrfsController:        &vrfsController{vrfs.NewController(sess), c.Retry},
+		devicesController:     &devicesController{devices.NewController(sess), c.Retry},
+		locationsController:   &locationsController{locations.NewController(sess), c.Retry},
+		nameserversController: &nameserversController{nameservers.NewController(sess), c.Retry},
+		tagsController:        &tagsController{tags.NewController(sess), c.Retry},
+		allocation:            c.Allocation,
+		NestCustomFields:      c.NestCustomFields,
 	}
 
 	// Validate that our conneciton is okay
@@ -97,7 +135,7 @@ func (c *Config) Client() (interface{}, error) {
 
 // ValidateConnection ensures that we can connect to PHPIPAM early, so that we
 // do not fail in the middle of a TF run if it can be prevented.
-func (c *Config) ValidateConnection(sc *sections.Controller) error {
+func (c *Config) ValidateConnection(sc *sectionsController) error {
 	_, err := sc.ListSections()
 	return err
 }
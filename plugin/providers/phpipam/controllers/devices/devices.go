@@ -0,0 +1,141 @@
+// Package devices provides types and methods for working with the devices
+// controller.
+package devices
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/client"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/session"
+)
+
+// Device represents a PHPIPAM device.
+type Device struct {
+	// The device ID. This is the entry ID in the PHPIPAM database.
+	ID int `json:"id,string,omitempty"`
+
+	// The device's host name.
+	Hostname string `json:"hostname,omitempty"`
+
+	// The management IP address of the device.
+	IPAddress string `json:"ip,omitempty"`
+
+	// The ID of the device type.
+	Type int `json:"type,string,omitempty"`
+
+	// The device vendor.
+	Vendor string `json:"vendor,omitempty"`
+
+	// The device model.
+	Model string `json:"model,omitempty"`
+
+	// A detailed description of the device.
+	Description string `json:"description,omitempty"`
+
+	// The sections this device is available in, as a semicolon-separated list
+	// of section IDs.
+	Sections string `json:"sections,omitempty"`
+
+	// The ID of the location the device is in.
+	Location int `json:"location,string,omitempty"`
+
+	// The ID of the rack the device is mounted in.
+	Rack int `json:"rack,string,omitempty"`
+
+	// The first rack unit the device occupies.
+	RackStart int `json:"rack_start,string,omitempty"`
+
+	// The number of rack units the device occupies.
+	RackSize int `json:"rack_size,string,omitempty"`
+
+	// The date of the last edit to this resource.
+	EditDate string `json:"editDate,omitempty"`
+
+	// A map[string]interface{} of custom fields to set on the resource. Note
+	// that this functionality requires PHPIPAM 1.3 or higher with the "Nest
+	// custom fields" flag set on the specific API integration. If this is not
+	// enabled, this map will be nil on GETs and POSTs and PATCHes with this
+	// field set will fail. Use the explicit custom field functions instead.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// Controller is the base client for the devices controller.
+type Controller struct {
+	client.Client
+}
+
+// NewController returns a new instance of the client for the devices
+// controller.
+func NewController(sess *session.Session) *Controller {
+	c := &Controller{
+		Client: *client.NewClient(sess),
+	}
+	return c
+}
+
+// ListDevices lists all devices.
+func (c *Controller) ListDevices() (out []Device, err error) {
+	err = c.SendRequest("GET", "/devices/", &struct{}{}, &out)
+	return
+}
+
+// CreateDevice creates a device by sending a POST request.
+func (c *Controller) CreateDevice(in Device) (message string, err error) {
+	err = c.SendRequest("POST", "/devices/", &in, &message)
+	return
+}
+
+// GetDeviceByID GETs a device via its ID in the PHPIPAM database.
+func (c *Controller) GetDeviceByID(id int) (out Device, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/devices/%d/", id), &struct{}{}, &out)
+	return
+}
+
+// GetDevicesByHostname GETs the devices with a host name exactly matching the
+// one supplied.
+func (c *Controller) GetDevicesByHostname(hostname string) (out []Device, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/devices/?filter_by=hostname&filter_value=%s", url.QueryEscape(hostname)), &struct{}{}, &out)
+	return
+}
+
+// GetAddressesOnDevice GETs the IP addresses that are assigned to a device.
+func (c *Controller) GetAddressesOnDevice(id int) (out []addresses.Address, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/devices/%d/addresses/", id), &struct{}{}, &out)
+	return
+}
+
+// GetDeviceCustomFieldsSchema GETs the custom fields for the devices
+// controller via client.GetCustomFieldsSchema.
+func (c *Controller) GetDeviceCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	out, err = c.Client.GetCustomFieldsSchema("devices")
+	return
+}
+
+// GetDeviceCustomFields GETs the custom fields for a device via
+// client.GetCustomFields.
+func (c *Controller) GetDeviceCustomFields(id int) (out map[string]interface{}, err error) {
+	out, err = c.Client.GetCustomFields(id, "devices")
+	return
+}
+
+// UpdateDevice updates a device by sending a PATCH request.
+func (c *Controller) UpdateDevice(in Device) (message string, err error) {
+	err = c.SendRequest("PATCH", "/devices/", &in, &message)
+	return
+}
+
+// UpdateDeviceCustomFields PATCHes the device's custom fields via
+// client.UpdateCustomFields.
+func (c *Controller) UpdateDeviceCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	message, err = c.Client.UpdateCustomFields(id, in, "devices")
+	return
+}
+
+// DeleteDevice deletes a device by its ID.
+func (c *Controller) DeleteDevice(id int) (message string, err error) {
+	err = c.SendRequest("DELETE", fmt.Sprintf("/devices/%d/", id), &struct{}{}, &message)
+	return
+}
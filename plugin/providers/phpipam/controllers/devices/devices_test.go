@@ -0,0 +1,163 @@
+package devices
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/internal/controllertest"
+)
+
+var testCreateDeviceInput = Device{
+	Hostname:  "switch01",
+	IPAddress: "10.10.1.245",
+	Type:      1,
+}
+
+const testCreateDeviceOutputExpected = `Device created`
+const testCreateDeviceOutputJSON = `
+{
+  "code": 201,
+  "success": true,
+  "data": "Device created"
+}
+`
+
+var testGetDeviceByIDOutputExpected = Device{
+	ID:          4,
+	Hostname:    "switch01",
+	IPAddress:   "10.10.1.245",
+	Type:        1,
+	Vendor:      "Cisco",
+	Model:       "C9300",
+	Description: "Access switch",
+	Sections:    "1;2",
+	Location:    3,
+	Rack:        2,
+	RackStart:   10,
+	RackSize:    1,
+}
+
+const testGetDeviceByIDOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": {
+    "id": "4",
+    "hostname": "switch01",
+    "ip": "10.10.1.245",
+    "type": "1",
+    "vendor": "Cisco",
+    "model": "C9300",
+    "description": "Access switch",
+    "sections": "1;2",
+    "location": "3",
+    "rack": "2",
+    "rack_start": "10",
+    "rack_size": "1",
+    "editDate": null
+  }
+}
+`
+
+var testGetDevicesByHostnameOutputExpected = []Device{
+	Device{
+		ID:        4,
+		Hostname:  "switch01",
+		IPAddress: "10.10.1.245",
+		Type:      1,
+	},
+}
+
+const testGetDevicesByHostnameOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": [
+    {
+      "id": "4",
+      "hostname": "switch01",
+      "ip": "10.10.1.245",
+      "type": "1",
+      "vendor": null,
+      "model": null,
+      "description": null,
+      "sections": null,
+      "location": null,
+      "rack": null,
+      "rack_start": null,
+      "rack_size": null,
+      "editDate": null
+    }
+  ]
+}
+`
+
+const testDeleteDeviceOutputExpected = `Device deleted`
+const testDeleteDeviceOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": "Device deleted"
+}
+`
+
+func TestCreateDevice(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusCreated, "/devices/", testCreateDeviceOutputJSON)
+	client := NewController(sess)
+
+	expected := testCreateDeviceOutputExpected
+	actual, err := client.CreateDevice(testCreateDeviceInput)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetDeviceByID(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/devices/4/", testGetDeviceByIDOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetDeviceByIDOutputExpected
+	actual, err := client.GetDeviceByID(4)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetDevicesByHostname(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/devices/?filter_by=hostname&filter_value=switch01", testGetDevicesByHostnameOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetDevicesByHostnameOutputExpected
+	actual, err := client.GetDevicesByHostname("switch01")
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestDeleteDevice(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/devices/4/", testDeleteDeviceOutputJSON)
+	client := NewController(sess)
+
+	expected := testDeleteDeviceOutputExpected
+	actual, err := client.DeleteDevice(4)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
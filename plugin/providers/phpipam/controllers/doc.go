@@ -0,0 +1,10 @@
+// Package controllers holds the PHPIPAM API controllers that the provider
+// needs and that are not part of phpipam-sdk-go yet, one package per
+// controller.
+//
+// They follow the conventions of the SDK controllers and are built on top of
+// the SDK's generic client, so that they can move to the SDK unchanged.
+// Locations, nameserver sets and IP address tags are managed through the tools
+// controller of the PHPIPAM API, so their requests are made against
+// /tools/locations/, /tools/nameservers/ and /tools/tags/.
+package controllers
@@ -0,0 +1,48 @@
+// Package controllertest provides the fake API server and session shared by
+// the tests of the controllers.
+package controllertest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/session"
+)
+
+// AppID is the application ID of the sessions returned by this package. The
+// paths of the requests that the server sees start with it.
+const AppID = "0123456789abcdefgh"
+
+// NewSession starts a test server that answers every request with status and
+// output, and fails t if the request URI is not uri, relative to the
+// application ID. It returns a logged in session for the server, which is
+// shut down when t finishes.
+func NewSession(t *testing.T, status int, uri, output string) *session.Session {
+	uri = "/" + AppID + uri
+	return NewSessionFunc(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RequestURI() != uri {
+			t.Errorf("Expected request URI %s, got %s", uri, r.URL.RequestURI())
+		}
+		w.Header().Add("Content-Type", "application/json")
+		http.Error(w, output, status)
+	})
+}
+
+// NewSessionFunc is like NewSession, but answers requests with f.
+func NewSessionFunc(t *testing.T, f http.HandlerFunc) *session.Session {
+	ts := httptest.NewServer(f)
+	t.Cleanup(ts.Close)
+	return &session.Session{
+		Config: phpipam.Config{
+			AppID:    AppID,
+			Endpoint: ts.URL,
+			Password: "changeit",
+			Username: "nobody",
+		},
+		Token: session.Token{
+			String: "foobarbazboop",
+		},
+	}
+}
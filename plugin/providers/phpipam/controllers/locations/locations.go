@@ -0,0 +1,131 @@
+// Package locations provides types and methods for working with the
+// locations controller.
+package locations
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/client"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/session"
+)
+
+// Location represents a PHPIPAM location.
+type Location struct {
+	// The location ID. This is the entry ID in the PHPIPAM database.
+	ID int `json:"id,string,omitempty"`
+
+	// The location name.
+	Name string `json:"name,omitempty"`
+
+	// A detailed description of the location.
+	Description string `json:"description,omitempty"`
+
+	// The postal address of the location.
+	Address string `json:"address,omitempty"`
+
+	// The latitude of the location.
+	Latitude string `json:"lat,omitempty"`
+
+	// The longitude of the location.
+	Longitude string `json:"long,omitempty"`
+
+	// A map[string]interface{} of custom fields to set on the resource. Note
+	// that this functionality requires PHPIPAM 1.3 or higher with the "Nest
+	// custom fields" flag set on the specific API integration. If this is not
+	// enabled, this map will be nil on GETs and POSTs and PATCHes with this
+	// field set will fail. Use the explicit custom field functions instead.
+	CustomFields map[string]interface{} `json:"custom_fields,omitempty"`
+}
+
+// Controller is the base client for the locations controller.
+type Controller struct {
+	client.Client
+}
+
+// NewController returns a new instance of the client for the locations
+// controller.
+func NewController(sess *session.Session) *Controller {
+	c := &Controller{
+		Client: *client.NewClient(sess),
+	}
+	return c
+}
+
+// ListLocations lists all locations.
+func (c *Controller) ListLocations() (out []Location, err error) {
+	err = c.SendRequest("GET", "/tools/locations/", &struct{}{}, &out)
+	return
+}
+
+// CreateLocation creates a location by sending a POST request.
+func (c *Controller) CreateLocation(in Location) (message string, err error) {
+	err = c.SendRequest("POST", "/tools/locations/", &in, &message)
+	return
+}
+
+// GetLocationByID GETs a location via its ID in the PHPIPAM database.
+func (c *Controller) GetLocationByID(id int) (out Location, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/tools/locations/%d/", id), &struct{}{}, &out)
+	return
+}
+
+// GetLocationsByName GETs the locations matching the supplied name.
+func (c *Controller) GetLocationsByName(name string) (out []Location, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/tools/locations/?filter_by=name&filter_value=%s", url.QueryEscape(name)), &struct{}{}, &out)
+	return
+}
+
+// GetLocationCustomFieldsSchema GETs the custom fields for the locations
+// controller via client.GetCustomFieldsSchema.
+func (c *Controller) GetLocationCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	out, err = c.Client.GetCustomFieldsSchema("tools/locations")
+	return
+}
+
+// GetLocationCustomFields GETs the custom fields for a location via
+// client.GetCustomFields.
+func (c *Controller) GetLocationCustomFields(id int) (out map[string]interface{}, err error) {
+	out, err = c.Client.GetCustomFields(id, "tools/locations")
+	return
+}
+
+// UpdateLocation updates a location by sending a PATCH request.
+func (c *Controller) UpdateLocation(in Location) (message string, err error) {
+	err = c.SendRequest("PATCH", fmt.Sprintf("/tools/locations/%d/", in.ID), &in, &message)
+	return
+}
+
+// UpdateLocationCustomFields PATCHes the location's custom fields.
+//
+// This function differs from client.UpdateCustomFields in that the tools
+// controller expects the ID of the location in the request URI, and not in
+// the request body.
+func (c *Controller) UpdateLocationCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	// Verify that we are only updating fields that are custom fields.
+	var schema map[string]phpipam.CustomField
+	schema, err = c.GetLocationCustomFieldsSchema()
+	if err != nil {
+		return
+	}
+	for k := range in {
+		if _, ok := schema[k]; !ok {
+			return "", fmt.Errorf("Custom field %s not found in schema for controller tools/locations", k)
+		}
+	}
+
+	params := make(map[string]interface{})
+	for k, v := range in {
+		params[k] = v
+	}
+
+	err = c.SendRequest("PATCH", fmt.Sprintf("/tools/locations/%d/", id), &params, &message)
+	return
+}
+
+// DeleteLocation deletes a location by its ID.
+func (c *Controller) DeleteLocation(id int) (message string, err error) {
+	err = c.SendRequest("DELETE", fmt.Sprintf("/tools/locations/%d/", id), &struct{}{}, &message)
+	return
+}
@@ -0,0 +1,146 @@
+package locations
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/internal/controllertest"
+)
+
+var testCreateLocationInput = Location{
+	Name:    "dc1",
+	Address: "1 Example Street",
+}
+
+const testCreateLocationOutputExpected = `Location created`
+const testCreateLocationOutputJSON = `
+{
+  "code": 201,
+  "success": true,
+  "data": "Location created"
+}
+`
+
+var testGetLocationByIDOutputExpected = Location{
+	ID:          3,
+	Name:        "dc1",
+	Description: "Primary data center",
+	Address:     "1 Example Street",
+	Latitude:    "46.0569",
+	Longitude:   "14.5058",
+}
+
+const testGetLocationByIDOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": {
+    "id": "3",
+    "name": "dc1",
+    "description": "Primary data center",
+    "address": "1 Example Street",
+    "lat": "46.0569",
+    "long": "14.5058"
+  }
+}
+`
+
+const testGetLocationCustomFieldsSchemaJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": {
+    "CustomTestLocations": {
+      "name": "CustomTestLocations",
+      "type": "varchar(255)",
+      "Comment": "Test field for locations controller",
+      "Null": "YES",
+      "Default": ""
+    }
+  }
+}
+`
+
+const testUpdateLocationCustomFieldsOutputExpected = `Location updated`
+const testUpdateLocationCustomFieldsOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": "Location updated"
+}
+`
+
+func TestCreateLocation(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusCreated, "/tools/locations/", testCreateLocationOutputJSON)
+	client := NewController(sess)
+
+	expected := testCreateLocationOutputExpected
+	actual, err := client.CreateLocation(testCreateLocationInput)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetLocationByID(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/locations/3/", testGetLocationByIDOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetLocationByIDOutputExpected
+	actual, err := client.GetLocationByID(3)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestUpdateLocationCustomFields(t *testing.T) {
+	sess := controllertest.NewSessionFunc(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/"+controllertest.AppID+"/tools/locations/custom_fields/":
+			http.Error(w, testGetLocationCustomFieldsSchemaJSON, http.StatusOK)
+		case r.Method == "PATCH" && r.URL.Path == "/"+controllertest.AppID+"/tools/locations/3/":
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `{"CustomTestLocations":"foo"}` {
+				t.Errorf("Unexpected request body %s", body)
+			}
+			http.Error(w, testUpdateLocationCustomFieldsOutputJSON, http.StatusOK)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			http.Error(w, "", http.StatusNotFound)
+		}
+	})
+	client := NewController(sess)
+
+	expected := testUpdateLocationCustomFieldsOutputExpected
+	actual, err := client.UpdateLocationCustomFields(3, map[string]interface{}{"CustomTestLocations": "foo"})
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+
+	if _, err := client.UpdateLocationCustomFields(3, map[string]interface{}{"Bogus": "foo"}); err == nil {
+		t.Fatalf("Expected error updating a field that is not in the schema")
+	}
+}
+
+func TestDeleteLocation(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/locations/3/", testUpdateLocationCustomFieldsOutputJSON)
+	client := NewController(sess)
+
+	if _, err := client.DeleteLocation(3); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+}
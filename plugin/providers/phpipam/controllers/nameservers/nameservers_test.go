@@ -0,0 +1,115 @@
+package nameservers
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/internal/controllertest"
+)
+
+var testCreateNameserverInput = Nameserver{
+	Name:        "internal",
+	Addresses:   "10.10.0.53;10.10.1.53",
+	Permissions: "1;2",
+}
+
+const testCreateNameserverOutputExpected = `Nameserver created`
+const testCreateNameserverOutputJSON = `
+{
+  "code": 201,
+  "success": true,
+  "data": "Nameserver created"
+}
+`
+
+var testGetNameserversByNameOutputExpected = []Nameserver{
+	Nameserver{
+		ID:          2,
+		Name:        "internal",
+		Addresses:   "10.10.0.53;10.10.1.53",
+		Description: "Internal resolvers",
+		Permissions: "1;2",
+		EditDate:    "2017-05-01 10:00:00",
+	},
+}
+
+const testGetNameserversByNameOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": [
+    {
+      "id": "2",
+      "name": "internal",
+      "namesrv1": "10.10.0.53;10.10.1.53",
+      "description": "Internal resolvers",
+      "permissions": "1;2",
+      "editDate": "2017-05-01 10:00:00"
+    }
+  ]
+}
+`
+
+const testUpdateNameserverOutputExpected = `Nameserver updated`
+const testUpdateNameserverOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": "Nameserver updated"
+}
+`
+
+func TestCreateNameserver(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusCreated, "/tools/nameservers/", testCreateNameserverOutputJSON)
+	client := NewController(sess)
+
+	expected := testCreateNameserverOutputExpected
+	actual, err := client.CreateNameserver(testCreateNameserverInput)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetNameserversByName(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/nameservers/?filter_by=name&filter_value=internal", testGetNameserversByNameOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetNameserversByNameOutputExpected
+	actual, err := client.GetNameserversByName("internal")
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestUpdateNameserver(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/nameservers/2/", testUpdateNameserverOutputJSON)
+	client := NewController(sess)
+
+	expected := testUpdateNameserverOutputExpected
+	actual, err := client.UpdateNameserver(Nameserver{ID: 2, Description: "Updated"})
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestDeleteNameserver(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/nameservers/2/", testUpdateNameserverOutputJSON)
+	client := NewController(sess)
+
+	if _, err := client.DeleteNameserver(2); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+}
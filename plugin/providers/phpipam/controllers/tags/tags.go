@@ -0,0 +1,91 @@
+// Package tags provides types and methods for working with the IP address
+// tags controller.
+package tags
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/client"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/session"
+)
+
+// Tag represents a PHPIPAM IP address tag.
+type Tag struct {
+	// The tag ID. This is the entry ID in the PHPIPAM database, and the value
+	// used in the tag field of an IP address.
+	ID int `json:"id,string,omitempty"`
+
+	// The name of the tag, such as "Used" or "Reserved".
+	Type string `json:"type,omitempty"`
+
+	// true if the tag is shown in the address list.
+	ShowTag phpipam.BoolIntString `json:"showtag"`
+
+	// The background colour of the tag, as a hex colour code.
+	BGColor string `json:"bgcolor,omitempty"`
+
+	// The foreground colour of the tag, as a hex colour code.
+	FGColor string `json:"fgcolor,omitempty"`
+
+	// "Yes" if address ranges with this tag are compressed in the address
+	// list, "No" otherwise.
+	Compress string `json:"compress,omitempty"`
+
+	// "Yes" if this is one of the built-in tags, which cannot be deleted.
+	// This field is read-only.
+	Locked string `json:"locked,omitempty"`
+
+	// true if the tag of addresses is updated by the ping scan agents.
+	UpdateTag phpipam.BoolIntString `json:"updateTag"`
+}
+
+// Controller is the base client for the tags controller.
+type Controller struct {
+	client.Client
+}
+
+// NewController returns a new instance of the client for the tags controller.
+func NewController(sess *session.Session) *Controller {
+	c := &Controller{
+		Client: *client.NewClient(sess),
+	}
+	return c
+}
+
+// ListTags lists all IP address tags.
+func (c *Controller) ListTags() (out []Tag, err error) {
+	err = c.SendRequest("GET", "/tools/tags/", &struct{}{}, &out)
+	return
+}
+
+// CreateTag creates an IP address tag by sending a POST request.
+func (c *Controller) CreateTag(in Tag) (message string, err error) {
+	err = c.SendRequest("POST", "/tools/tags/", &in, &message)
+	return
+}
+
+// GetTagByID GETs an IP address tag via its ID in the PHPIPAM database.
+func (c *Controller) GetTagByID(id int) (out Tag, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/tools/tags/%d/", id), &struct{}{}, &out)
+	return
+}
+
+// GetTagsByName GETs the IP address tags matching the supplied name.
+func (c *Controller) GetTagsByName(name string) (out []Tag, err error) {
+	err = c.SendRequest("GET", fmt.Sprintf("/tools/tags/?filter_by=type&filter_value=%s", url.QueryEscape(name)), &struct{}{}, &out)
+	return
+}
+
+// UpdateTag updates an IP address tag by sending a PATCH request.
+func (c *Controller) UpdateTag(in Tag) (message string, err error) {
+	err = c.SendRequest("PATCH", fmt.Sprintf("/tools/tags/%d/", in.ID), &in, &message)
+	return
+}
+
+// DeleteTag deletes an IP address tag by its ID.
+func (c *Controller) DeleteTag(id int) (message string, err error) {
+	err = c.SendRequest("DELETE", fmt.Sprintf("/tools/tags/%d/", id), &struct{}{}, &message)
+	return
+}
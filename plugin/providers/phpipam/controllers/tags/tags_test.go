@@ -0,0 +1,118 @@
+package tags
+
+import (
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/internal/controllertest"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+)
+
+var testCreateTagInput = Tag{
+	Type:     "Decommissioning",
+	ShowTag:  phpipam.BoolIntString(true),
+	BGColor:  "#ff8800",
+	FGColor:  "#ffffff",
+	Compress: "No",
+}
+
+const testCreateTagOutputExpected = `Tag created`
+const testCreateTagOutputJSON = `
+{
+  "code": 201,
+  "success": true,
+  "data": "Tag created"
+}
+`
+
+var testGetTagsByNameOutputExpected = []Tag{
+	Tag{
+		ID:        2,
+		Type:      "Used",
+		ShowTag:   phpipam.BoolIntString(false),
+		BGColor:   "#a9c9a4",
+		FGColor:   "#ffffff",
+		Compress:  "No",
+		Locked:    "Yes",
+		UpdateTag: phpipam.BoolIntString(true),
+	},
+}
+
+const testGetTagsByNameOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": [
+    {
+      "id": "2",
+      "type": "Used",
+      "showtag": "0",
+      "bgcolor": "#a9c9a4",
+      "fgcolor": "#ffffff",
+      "compress": "No",
+      "locked": "Yes",
+      "updateTag": "1"
+    }
+  ]
+}
+`
+
+const testDeleteTagOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": "Tag deleted"
+}
+`
+
+func TestCreateTag(t *testing.T) {
+	sess := controllertest.NewSessionFunc(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RequestURI() != "/"+controllertest.AppID+"/tools/tags/" {
+			t.Errorf("Unexpected request URI %s", r.URL.RequestURI())
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		expected := `{"type":"Decommissioning","showtag":"1","bgcolor":"#ff8800","fgcolor":"#ffffff","compress":"No","updateTag":"0"}`
+		if string(body) != expected {
+			t.Errorf("Expected request body %s, got %s", expected, body)
+		}
+		w.Header().Add("Content-Type", "application/json")
+		http.Error(w, testCreateTagOutputJSON, http.StatusCreated)
+	})
+	client := NewController(sess)
+
+	expected := testCreateTagOutputExpected
+	actual, err := client.CreateTag(testCreateTagInput)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetTagsByName(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/tags/?filter_by=type&filter_value=Used", testGetTagsByNameOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetTagsByNameOutputExpected
+	actual, err := client.GetTagsByName("Used")
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestDeleteTag(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/tools/tags/5/", testDeleteTagOutputJSON)
+	client := NewController(sess)
+
+	if _, err := client.DeleteTag(5); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+}
@@ -0,0 +1,155 @@
+package vrfs
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/internal/controllertest"
+)
+
+var testCreateVRFInput = VRF{
+	Name: "foovrf",
+	RD:   "65000:100",
+}
+
+const testCreateVRFOutputExpected = `Vrf created`
+const testCreateVRFOutputJSON = `
+{
+  "code": 201,
+  "success": true,
+  "data": "Vrf created"
+}
+`
+
+var testGetVRFByIDOutputExpected = VRF{
+	ID:          2,
+	Name:        "foovrf",
+	RD:          "65000:100",
+	Description: "Test VRF",
+	Sections:    "1;2",
+}
+
+const testGetVRFByIDOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": {
+    "id": "2",
+    "name": "foovrf",
+    "rd": "65000:100",
+    "description": "Test VRF",
+    "sections": "1;2",
+    "editDate": null
+  }
+}
+`
+
+var testGetVRFsByNameOutputExpected = []VRF{
+	VRF{
+		ID:   2,
+		Name: "foovrf",
+		RD:   "65000:100",
+	},
+}
+
+const testGetVRFsByNameOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": [
+    {
+      "id": "2",
+      "name": "foovrf",
+      "rd": "65000:100",
+      "description": null,
+      "sections": null,
+      "editDate": null
+    }
+  ]
+}
+`
+
+const testDeleteVRFOutputExpected = `Vrf deleted`
+const testDeleteVRFOutputJSON = `
+{
+  "code": 200,
+  "success": true,
+  "data": "Vrf deleted"
+}
+`
+
+func TestCreateVRF(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusCreated, "/vrf/", testCreateVRFOutputJSON)
+	client := NewController(sess)
+
+	expected := testCreateVRFOutputExpected
+	actual, err := client.CreateVRF(testCreateVRFInput)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetVRFByID(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/vrf/2/", testGetVRFByIDOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetVRFByIDOutputExpected
+	actual, err := client.GetVRFByID(2)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetVRFsByName(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/vrf/?filter_by=name&filter_value=foovrf", testGetVRFsByNameOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetVRFsByNameOutputExpected
+	actual, err := client.GetVRFsByName("foovrf")
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestGetVRFsByRD(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/vrf/?filter_by=rd&filter_value=65000%3A100", testGetVRFsByNameOutputJSON)
+	client := NewController(sess)
+
+	expected := testGetVRFsByNameOutputExpected
+	actual, err := client.GetVRFsByRD("65000:100")
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
+
+func TestDeleteVRF(t *testing.T) {
+	sess := controllertest.NewSession(t, http.StatusOK, "/vrf/2/", testDeleteVRFOutputJSON)
+	client := NewController(sess)
+
+	expected := testDeleteVRFOutputExpected
+	actual, err := client.DeleteVRF(2)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+}
@@ -8,9 +8,6 @@ import (
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
-	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
-	"github.com/pavel-z1/phpipam-sdk-go/controllers/vlans"
 )
 
 // customFieldFilterSchema returns a *schema.Schema for the custom_field_filter
@@ -106,12 +103,18 @@ func updateCustomFields(d *schema.ResourceData, client interface{}) error {
 	var old map[string]interface{}
 	var err error
 	switch c := client.(type) {
-	case *addresses.Controller:
+	case *addressesController:
 		old, err = c.GetAddressCustomFields(d.Get("address_id").(int))
-	case *subnets.Controller:
+	case *subnetsController:
 		old, err = c.GetSubnetCustomFields(d.Get("subnet_id").(int))
-	case *vlans.Controller:
+	case *vlansController:
 		old, err = c.GetVLANCustomFields(d.Get("vlan_id").(int))
+	case *vrfsController:
+		old, err = c.GetVRFCustomFields(d.Get("vrf_id").(int))
+	case *devicesController:
+		old, err = c.GetDeviceCustomFields(d.Get("device_id").(int))
+	case *locationsController:
+		old, err = c.GetLocationCustomFields(d.Get("location_id").(int))
 	default:
 		panic(fmt.Errorf("Invalid client type passed %#v - this is a bug", client))
 	}
@@ -134,13 +137,25 @@ nextKey:
 		customFields[k] = nil
 	}
 
+	// There is nothing to update. The SDK does not handle empty updates when
+	// custom fields are defined for the controller.
+	if len(customFields) == 0 {
+		return nil
+	}
+
 	switch c := client.(type) {
-	case *addresses.Controller:
+	case *addressesController:
 		_, err = c.UpdateAddressCustomFields(d.Get("address_id").(int), customFields)
-	case *subnets.Controller:
+	case *subnetsController:
 		_, err = c.UpdateSubnetCustomFields(d.Get("subnet_id").(int), customFields)
-	case *vlans.Controller:
+	case *vlansController:
 		_, err = c.UpdateVLANCustomFields(d.Get("vlan_id").(int), d.Get("name").(string), customFields)
+	case *vrfsController:
+		_, err = c.UpdateVRFCustomFields(d.Get("vrf_id").(int), customFields)
+	case *devicesController:
+		_, err = c.UpdateDeviceCustomFields(d.Get("device_id").(int), customFields)
+	case *locationsController:
+		_, err = c.UpdateLocationCustomFields(d.Get("location_id").(int), customFields)
 	default:
 		panic(fmt.Errorf("Invalid client type passed %#v - this is a bug", client))
 	}
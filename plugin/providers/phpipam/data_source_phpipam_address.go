@@ -2,6 +2,7 @@ package phpipam
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"strconv"
 	"strings"
@@ -59,6 +60,18 @@ func dataSourcePHPIPAMAddressRead(d *schema.ResourceData, meta interface{}) erro
 			}
 			return err
 		}
+	case d.Get("mac_address").(string) != "" || (d.Get("hostname").(string) != "" && d.Get("subnet_id").(int) == 0):
+		out, err = addressSearchAcrossSubnets(d, meta)
+		if err != nil {
+			return err
+		}
+		query := fmt.Sprintf("host name %q", d.Get("hostname").(string))
+		if d.Get("mac_address").(string) != "" {
+			query = fmt.Sprintf("MAC address %q", d.Get("mac_address").(string))
+		}
+		if len(out) != 1 {
+			return addressSearchError(query, out)
+		}
 	case d.Get("subnet_id").(int) != 0 && (d.Get("description").(string) != "" || d.Get("hostname").(string) != "" || len(d.Get("custom_field_filter").(map[string]interface{})) > 0):
 		out, err = addressSearchInSubnet(d, meta)
 		if err != nil {
@@ -78,7 +91,7 @@ func dataSourcePHPIPAMAddressRead(d *schema.ResourceData, meta interface{}) erro
 				return err
 			}
 		} else {
-			return errors.New("No valid combination of parameters found - need one of address_id, ip_address, or subnet_id and (description|hostname|custom_field_filter)")
+			return errors.New("No valid combination of parameters found - need one of address_id, ip_address, hostname, mac_address, or subnet_id and (description|custom_field_filter)")
 		}
 	}
 	if len(out) != 1 {
@@ -100,10 +113,22 @@ func dataSourcePHPIPAMAddressRead(d *schema.ResourceData, meta interface{}) erro
 
 	flattenAddress(out[0], d)
 
+	// Resolve the name of the state tag, so that addresses can be managed and
+	// read by the tag name instead of its ID.
+	if out[0].Tag != 0 {
+		tag, err := meta.(*ProviderPHPIPAMClient).tagsController.GetTagByID(out[0].Tag)
+		if err != nil {
+			return err
+		}
+		d.Set("state_tag", tag.Type)
+	} else {
+		d.Set("state_tag", "")
+	}
+
 	return nil
 }
 
-func checkAddresssesCustomFiledsExists(d *schema.ResourceData, client *addresses.Controller) bool {
+func checkAddresssesCustomFiledsExists(d *schema.ResourceData, client *addressesController) bool {
 	if _, ok := d.GetOk("custom_field_filter"); ok {
 		return true
 	} else if _, err := client.GetAddressCustomFieldsSchema(); err == nil {
@@ -0,0 +1,83 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/tags"
+)
+
+func dataSourcePHPIPAMAddressTag() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourcePHPIPAMAddressTagRead,
+		Schema: dataSourceAddressTagSchema(),
+	}
+}
+
+func dataSourcePHPIPAMAddressTagRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).tagsController
+	var out tags.Tag
+	// We need to determine how to get the tag. An ID search takes priority,
+	// and after that the tag name.
+	switch {
+	case d.Get("tag_id").(int) != 0:
+		var err error
+		out, err = c.GetTagByID(d.Get("tag_id").(int))
+		if err != nil {
+			return err
+		}
+	case d.Get("name").(string) != "":
+		v, err := c.GetTagsByName(d.Get("name").(string))
+		if err != nil {
+			if strings.Contains(err.Error(), "No results (filter applied)") {
+				log.Printf("Can't find address tag with name %s", d.Get("name").(string))
+				return nil
+			}
+			return err
+		}
+		// Only one result should be returned by this search. Fail on multiples.
+		if len(v) != 1 {
+			return errors.New("Address tag search returned either zero or multiple results. Please correct your search and try again")
+		}
+		out = v[0]
+	default:
+		// We need to ensure imported resources are not recreated when terraform apply is ran
+		// imported resources only have an Id which we need to map back to tag_id
+		id := d.Id()
+		if len(id) > 0 {
+			tag_id, err := strconv.Atoi(id)
+			if err != nil {
+				return err
+			}
+			out, err = c.GetTagByID(tag_id)
+			if err != nil {
+				return err
+			}
+		} else {
+			return errors.New("tag_id or name not defined, cannot proceed with reading data")
+		}
+	}
+
+	flattenAddressTag(out, d)
+	return nil
+}
+
+// addressTagIDByName resolves an IP address tag name, such as "Used" or
+// "Reserved", to its ID in the PHPIPAM database.
+func addressTagIDByName(c *tagsController, name string) (int, error) {
+	v, err := c.GetTagsByName(name)
+	if err != nil {
+		if strings.Contains(err.Error(), "No results (filter applied)") {
+			return 0, fmt.Errorf("Address tag %q not found", name)
+		}
+		return 0, err
+	}
+	if len(v) != 1 {
+		return 0, fmt.Errorf("Address tag search for %q returned multiple results", name)
+	}
+	return v[0].ID, nil
+}
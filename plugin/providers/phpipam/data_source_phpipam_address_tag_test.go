@@ -0,0 +1,34 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourcePHPIPAMAddressTagConfig = `
+data "phpipam_address_tag" "used" {
+  name = "Used"
+}
+
+data "phpipam_address_tag" "used_by_id" {
+  tag_id = data.phpipam_address_tag.used.tag_id
+}
+`
+
+func TestAccDataSourcePHPIPAMAddressTag(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMAddressTagConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.phpipam_address_tag.used", "tag_id", "2"),
+					resource.TestCheckResourceAttr("data.phpipam_address_tag.used", "locked", "true"),
+					resource.TestCheckResourceAttrPair("data.phpipam_address_tag.used", "name", "data.phpipam_address_tag.used_by_id", "name"),
+				),
+			},
+		},
+	})
+}
@@ -1,6 +1,8 @@
 package phpipam
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -113,12 +115,12 @@ data "phpipam_address" "custom_search" {
 `
 
 func TestAccDataSourcePHPIPAMAddress(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMAddressConfig,
@@ -144,3 +146,98 @@ func TestAccDataSourcePHPIPAMAddress(t *testing.T) {
 		},
 	})
 }
+
+func TestDataSourcePHPIPAMAddressAcrossSubnets(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	first := testFakeSubnet(t, meta, "10.10.4.0", 24)
+	second := testFakeSubnet(t, meta, "10.10.5.0", 24)
+	other := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     1,
+		"subnet_address": "10.10.6.0",
+		"subnet_mask":    24,
+	})
+	otherID, _ := strconv.Atoi(other.ID)
+
+	address := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":   first,
+		"ip_address":  "10.10.4.10",
+		"hostname":    "web01.cust1.local",
+		"mac_address": "00:1a:2b:3c:4d:5e",
+	})
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":   second,
+		"ip_address":  "10.10.5.10",
+		"hostname":    "web02.cust1.local",
+		"mac_address": "00:1a:2b:3c:4d:5f",
+	})
+	duplicate := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  otherID,
+		"ip_address": "10.10.6.10",
+		"hostname":   "web02.cust1.local",
+	})
+
+	byHostname := testFakeReadDataSource(t, meta, "phpipam_address", map[string]interface{}{
+		"hostname": "web01.cust1.local",
+	})
+	testFakeCheckAttrs(t, byHostname, map[string]string{
+		"address_id": address.ID,
+		"subnet_id":  strconv.Itoa(first),
+	})
+
+	// Host names are escaped in the request path.
+	escaped := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  first,
+		"ip_address": "10.10.4.11",
+		"hostname":   "web 03/a?b#c",
+	})
+	byEscapedHostname := testFakeReadDataSource(t, meta, "phpipam_address", map[string]interface{}{
+		"hostname": "web 03/a?b#c",
+	})
+	testFakeCheckAttrs(t, byEscapedHostname, map[string]string{
+		"address_id": escaped.ID,
+	})
+
+	// MAC addresses match in any notation.
+	for _, mac := range []string{"00:1A:2B:3C:4D:5E", "00-1a-2b-3c-4d-5e", "001a.2b3c.4d5e"} {
+		byMAC := testFakeReadDataSource(t, meta, "phpipam_address", map[string]interface{}{
+			"mac_address": mac,
+		})
+		testFakeCheckAttrs(t, byMAC, map[string]string{
+			"address_id": address.ID,
+		})
+	}
+
+	_, err := testFakeReadDataSourceE(meta, "phpipam_address", map[string]interface{}{
+		"hostname": "web02.cust1.local",
+	})
+	if !strings.Contains(err, "2 addresses found with host name \"web02.cust1.local\"") || !strings.Contains(err, "10.10.6.10") {
+		t.Fatalf("expected an ambiguous host name error, got %q", err)
+	}
+
+	bySection := testFakeReadDataSource(t, meta, "phpipam_address", map[string]interface{}{
+		"hostname":   "web02.cust1.local",
+		"section_id": 1,
+	})
+	testFakeCheckAttrs(t, bySection, map[string]string{
+		"address_id": duplicate.ID,
+	})
+
+	bySubnet := testFakeReadDataSource(t, meta, "phpipam_address", map[string]interface{}{
+		"mac_address": "00:1a:2b:3c:4d:5f",
+		"subnet_id":   second,
+	})
+	testFakeCheckAttrs(t, bySubnet, map[string]string{
+		"ip_address": "10.10.5.10",
+	})
+
+	if _, err := testFakeReadDataSourceE(meta, "phpipam_address", map[string]interface{}{
+		"mac_address": "00:1a:2b:3c:4d:60",
+	}); !strings.Contains(err, "No address found with MAC address") {
+		t.Fatalf("expected a not found error, got %q", err)
+	}
+	if _, err := testFakeReadDataSourceE(meta, "phpipam_address", map[string]interface{}{
+		"mac_address": "00:1a:2b:3c:4d",
+	}); !strings.Contains(err, "Invalid MAC address") {
+		t.Fatalf("expected an invalid MAC address error, got %q", err)
+	}
+}
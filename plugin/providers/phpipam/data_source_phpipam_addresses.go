@@ -28,6 +28,13 @@ func dataSourcePHPIPAMAddresses() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeInt},
 			},
+			"addresses": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceAddressesSchema(),
+				},
+			},
 		},
 	}
 }
@@ -37,11 +44,37 @@ func dataSourcePHPIPAMAddressesRead(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	readCustomFields := checkAddresssesCustomFiledsExists(d, c)
+	// Resolve the names of the state tags with a single request, rather than
+	// one for each address.
+	tagNames := make(map[int]string)
+	if len(out) > 0 {
+		tagList, err := meta.(*ProviderPHPIPAMClient).tagsController.ListTags()
+		if err != nil {
+			return err
+		}
+		for _, v := range tagList {
+			tagNames[v.ID] = v.Type
+		}
+	}
 	var sum int
 	ids := make([]int, 0)
+	list := make([]interface{}, 0)
 	for _, v := range out {
 		sum += v.ID
 		ids = append(ids, v.ID)
+		m := flattenAddressMap(v)
+		m["state_tag"] = tagNames[v.Tag]
+		if readCustomFields {
+			fields, err := c.GetAddressCustomFields(v.ID)
+			if err != nil {
+				return err
+			}
+			trimMap(fields)
+			m["custom_fields"] = fields
+		}
+		list = append(list, m)
 	}
 
 	d.SetId(strconv.Itoa(sum))
@@ -49,6 +82,10 @@ func dataSourcePHPIPAMAddressesRead(d *schema.ResourceData, meta interface{}) er
 	if err != nil {
 		return err
 	}
+	err = d.Set("addresses", list)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -83,12 +83,12 @@ output "actual_address_ids_custom_fields" {
 `
 
 func TestAccDataSourcePHPIPAMAddresses(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMAddressesConfigStage1,
@@ -99,6 +99,12 @@ func TestAccDataSourcePHPIPAMAddresses(t *testing.T) {
 					testCheckOutputPair("expected_address_ids", "actual_address_ids_description"),
 					testCheckOutputPair("expected_address_ids", "actual_address_ids_hostname"),
 					testCheckOutputPair("expected_address_ids", "actual_address_ids_custom_fields"),
+					resource.TestCheckResourceAttr("data.phpipam_addresses.addresses_by_custom_fields", "addresses.#", "5"),
+					resource.TestCheckResourceAttrPair("data.phpipam_addresses.addresses_by_custom_fields", "addresses.0.address_id", "phpipam_address.addresses.0", "address_id"),
+					resource.TestCheckResourceAttr("data.phpipam_addresses.addresses_by_custom_fields", "addresses.0.ip_address", "10.10.3.10"),
+					resource.TestCheckResourceAttr("data.phpipam_addresses.addresses_by_custom_fields", "addresses.0.hostname", "tf-addresses-test1.example.internal"),
+					resource.TestCheckResourceAttr("data.phpipam_addresses.addresses_by_custom_fields", "addresses.0.custom_fields.custom_CustomTestAddresses2", "Entry 10.10.3.10"),
+					resource.TestCheckResourceAttr("data.phpipam_addresses.addresses_by_custom_fields", "addresses.4.ip_address", "10.10.3.14"),
 				),
 			},
 		},
@@ -0,0 +1,84 @@
+package phpipam
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/devices"
+)
+
+func dataSourcePHPIPAMDevice() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourcePHPIPAMDeviceRead,
+		Schema: dataSourceDeviceSchema(),
+	}
+}
+
+func dataSourcePHPIPAMDeviceRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).devicesController
+	var out devices.Device
+	// We need to determine how to get the device. An ID search takes priority,
+	// and after that the host name.
+	switch {
+	case d.Get("device_id").(int) != 0:
+		var err error
+		out, err = c.GetDeviceByID(d.Get("device_id").(int))
+		if err != nil {
+			return err
+		}
+	case d.Get("hostname").(string) != "":
+		v, err := c.GetDevicesByHostname(d.Get("hostname").(string))
+		if err != nil {
+			if strings.Contains(err.Error(), "No results (filter applied)") {
+				log.Printf("Can't find device with hostname %s", d.Get("hostname").(string))
+				return nil
+			}
+			return err
+		}
+		// Only one result should be returned by this search. Fail on multiples.
+		if len(v) != 1 {
+			return errors.New("Device search returned either zero or multiple results. Please correct your search and try again")
+		}
+		out = v[0]
+	default:
+		// We need to ensure imported resources are not recreated when terraform apply is ran
+		// imported resources only have an Id which we need to map back to device_id
+		id := d.Id()
+		if len(id) > 0 {
+			device_id, err := strconv.Atoi(id)
+			if err != nil {
+				return err
+			}
+			out, err = c.GetDeviceByID(device_id)
+			if err != nil {
+				return err
+			}
+		} else {
+			return errors.New("device_id or hostname not defined, cannot proceed with reading data")
+		}
+	}
+
+	if checkDevicesCustomFieldsExists(c) {
+		fields, err := c.GetDeviceCustomFields(out.ID)
+		if err != nil {
+			return err
+		}
+		trimMap(fields)
+		if err := d.Set("custom_fields", fields); err != nil {
+			return err
+		}
+	}
+
+	flattenDevice(out, d)
+	return nil
+}
+
+// checkDevicesCustomFieldsExists returns true if there are custom fields
+// defined for the devices controller.
+func checkDevicesCustomFieldsExists(client *devicesController) bool {
+	_, err := client.GetDeviceCustomFieldsSchema()
+	return err == nil
+}
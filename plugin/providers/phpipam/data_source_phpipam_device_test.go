@@ -0,0 +1,46 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourcePHPIPAMDeviceConfig = `
+resource "phpipam_device" "device" {
+  hostname    = "tf-test-device"
+  ip_address  = "10.10.1.245"
+  description = "Terraform test device"
+}
+
+data "phpipam_device" "device_by_hostname" {
+  hostname   = "tf-test-device"
+  depends_on = [phpipam_device.device]
+}
+
+data "phpipam_device" "device_by_id" {
+  device_id  = data.phpipam_device.device_by_hostname.device_id
+  depends_on = [data.phpipam_device.device_by_hostname]
+}
+`
+
+func TestAccDataSourcePHPIPAMDevice(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			deviceSweep("tf-test-device", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMDeviceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.phpipam_device.device_by_hostname", "device_id", "data.phpipam_device.device_by_id", "device_id"),
+					resource.TestCheckResourceAttrPair("data.phpipam_device.device_by_hostname", "hostname", "data.phpipam_device.device_by_id", "hostname"),
+					resource.TestCheckResourceAttr("data.phpipam_device.device_by_hostname", "ip_address", "10.10.1.245"),
+					resource.TestCheckResourceAttr("data.phpipam_device.device_by_hostname", "description", "Terraform test device"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePHPIPAMDevices() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePHPIPAMDevicesRead,
+		Schema: map[string]*schema.Schema{
+			"hostname_match":    subnetDescriptionMatchSchema([]string{}),
+			"description_match": subnetDescriptionMatchSchema([]string{}),
+			"type_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"location_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"custom_field_filter": customFieldFilterSchema([]string{}),
+			"device_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+func dataSourcePHPIPAMDevicesRead(d *schema.ResourceData, meta interface{}) error {
+	out, err := deviceSearch(d, meta)
+	if err != nil {
+		return err
+	}
+	var sum int
+	ids := make([]int, 0)
+	for _, v := range out {
+		sum += v.ID
+		ids = append(ids, v.ID)
+	}
+
+	d.SetId(strconv.Itoa(sum))
+	err = d.Set("device_ids", ids)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
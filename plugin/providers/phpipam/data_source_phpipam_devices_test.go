@@ -0,0 +1,58 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourcePHPIPAMDevicesConfigStage1 = `
+resource "phpipam_device" "devices" {
+  count       = 3
+  hostname    = "tf-test-device-${count.index}"
+  description = "Terraform test device (multiple devices data source)"
+}
+`
+
+const testAccDataSourcePHPIPAMDevicesConfigStage2 = testAccDataSourcePHPIPAMDevicesConfigStage1 + `
+data "phpipam_devices" "devices_by_hostname" {
+  hostname_match = "^tf-test-device-[0-9]+$"
+}
+
+data "phpipam_devices" "devices_by_description" {
+  description_match = "multiple devices data source"
+}
+
+output "expected_device_ids" {
+  value = phpipam_device.devices.*.device_id
+}
+
+output "actual_device_ids_hostname" {
+  value = data.phpipam_devices.devices_by_hostname.device_ids
+}
+
+output "actual_device_ids_description" {
+  value = data.phpipam_devices.devices_by_description.device_ids
+}
+`
+
+func TestAccDataSourcePHPIPAMDevices(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMDevicesConfigStage1,
+			},
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMDevicesConfigStage2,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckOutputPair("expected_device_ids", "actual_device_ids_hostname"),
+					testCheckOutputPair("expected_device_ids", "actual_device_ids_description"),
+				),
+			},
+		},
+	})
+}
@@ -65,12 +65,12 @@ data "phpipam_first_free_address" "next" {
 `
 
 func TestAccDataSourcePHPIPAMFirstFreeAddress(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMFirstFreeAddressConfig,
@@ -83,12 +83,12 @@ func TestAccDataSourcePHPIPAMFirstFreeAddress(t *testing.T) {
 }
 
 func TestAccDataSourcePHPIPAMFirstFreeAddressNoFree(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config:      testAccDataSourcePHPIPAMFirstFreeAddressNoFreeConfig,
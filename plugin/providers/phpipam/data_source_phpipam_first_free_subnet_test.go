@@ -67,12 +67,12 @@ resource "phpipam_subnet" "subnet2"  {
 `
 
 func TestAccDataSourcePHPIPAMFirstFreeSubnet(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMFirstFreeSubnetConfig,
@@ -85,12 +85,12 @@ func TestAccDataSourcePHPIPAMFirstFreeSubnet(t *testing.T) {
 }
 
 func TestAccDataSourcePHPIPAMFirstFreeSubnetNoFree(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config:      testAccDataSourcePHPIPAMFirstFreeSubnetNoFreeConfig,
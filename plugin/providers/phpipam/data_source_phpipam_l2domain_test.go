@@ -24,12 +24,12 @@ data "phpipam_l2domain" "l2domain_by_id" {
 `
 
 func TestAccDataSourcePHPIPAML2Domain(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			l2domainSweep("tf-test-l2domain", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAML2DomainConfig,
@@ -0,0 +1,57 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePHPIPAML2Domains() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePHPIPAML2DomainsRead,
+		Schema: map[string]*schema.Schema{
+			"name_match":          subnetDescriptionMatchSchema([]string{}),
+			"description_match":   subnetDescriptionMatchSchema([]string{}),
+			"custom_field_filter": customFieldFilterSchema([]string{}),
+			"domain_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"l2domains": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceL2DomainsSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePHPIPAML2DomainsRead(d *schema.ResourceData, meta interface{}) error {
+	out, err := l2DomainSearch(d, meta)
+	if err != nil {
+		return err
+	}
+	var sum int
+	ids := make([]int, 0)
+	list := make([]interface{}, 0)
+	for _, v := range out {
+		sum += v.ID
+		ids = append(ids, v.ID)
+		list = append(list, flattenL2DomainMap(v))
+	}
+
+	d.SetId(strconv.Itoa(sum))
+	err = d.Set("domain_ids", ids)
+	if err != nil {
+		return err
+	}
+	err = d.Set("l2domains", list)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDataSourcePHPIPAML2Domains(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	var domains []*terraform.InstanceState
+	for _, name := range []string{"tf-test-l2domains-1", "tf-test-l2domains-2"} {
+		domains = append(domains, testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+			"name":        name,
+			"description": "Terraform test L2 domain (multiple L2 domains data source)",
+		}))
+	}
+
+	all := testFakeReadDataSource(t, meta, "phpipam_l2domains", map[string]interface{}{})
+	testFakeCheckAttrs(t, all, map[string]string{
+		"domain_ids.#":     "3",
+		"l2domains.0.name": "default",
+		"l2domains.2.name": "tf-test-l2domains-2",
+	})
+
+	byName := testFakeReadDataSource(t, meta, "phpipam_l2domains", map[string]interface{}{
+		"name_match": "^tf-test-l2domains-",
+	})
+	testFakeCheckIDs(t, byName, "domain_ids", domains...)
+
+	byDescription := testFakeReadDataSource(t, meta, "phpipam_l2domains", map[string]interface{}{
+		"description_match": "multiple L2 domains data source",
+	})
+	testFakeCheckIDs(t, byDescription, "domain_ids", domains...)
+}
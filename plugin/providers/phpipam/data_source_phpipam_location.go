@@ -0,0 +1,84 @@
+package phpipam
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/locations"
+)
+
+func dataSourcePHPIPAMLocation() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourcePHPIPAMLocationRead,
+		Schema: dataSourceLocationSchema(),
+	}
+}
+
+func dataSourcePHPIPAMLocationRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).locationsController
+	var out locations.Location
+	// We need to determine how to get the location. An ID search takes
+	// priority, and after that the location name.
+	switch {
+	case d.Get("location_id").(int) != 0:
+		var err error
+		out, err = c.GetLocationByID(d.Get("location_id").(int))
+		if err != nil {
+			return err
+		}
+	case d.Get("name").(string) != "":
+		v, err := c.GetLocationsByName(d.Get("name").(string))
+		if err != nil {
+			if strings.Contains(err.Error(), "No results (filter applied)") {
+				log.Printf("Can't find location with name %s", d.Get("name").(string))
+				return nil
+			}
+			return err
+		}
+		// Only one result should be returned by this search. Fail on multiples.
+		if len(v) != 1 {
+			return errors.New("Location search returned either zero or multiple results. Please correct your search and try again")
+		}
+		out = v[0]
+	default:
+		// We need to ensure imported resources are not recreated when terraform apply is ran
+		// imported resources only have an Id which we need to map back to location_id
+		id := d.Id()
+		if len(id) > 0 {
+			location_id, err := strconv.Atoi(id)
+			if err != nil {
+				return err
+			}
+			out, err = c.GetLocationByID(location_id)
+			if err != nil {
+				return err
+			}
+		} else {
+			return errors.New("location_id or name not defined, cannot proceed with reading data")
+		}
+	}
+
+	if checkLocationsCustomFieldsExists(c) {
+		fields, err := c.GetLocationCustomFields(out.ID)
+		if err != nil {
+			return err
+		}
+		trimMap(fields)
+		if err := d.Set("custom_fields", fields); err != nil {
+			return err
+		}
+	}
+
+	flattenLocation(out, d)
+	return nil
+}
+
+// checkLocationsCustomFieldsExists returns true if there are custom fields
+// defined for the locations controller.
+func checkLocationsCustomFieldsExists(client *locationsController) bool {
+	_, err := client.GetLocationCustomFieldsSchema()
+	return err == nil
+}
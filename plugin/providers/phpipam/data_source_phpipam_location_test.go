@@ -0,0 +1,46 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourcePHPIPAMLocationConfig = `
+resource "phpipam_location" "location" {
+  name        = "tf-test-location"
+  description = "Terraform test location"
+  address     = "1 Example Street"
+}
+
+data "phpipam_location" "location_by_name" {
+  name       = "tf-test-location"
+  depends_on = [phpipam_location.location]
+}
+
+data "phpipam_location" "location_by_id" {
+  location_id = data.phpipam_location.location_by_name.location_id
+  depends_on  = [data.phpipam_location.location_by_name]
+}
+`
+
+func TestAccDataSourcePHPIPAMLocation(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			locationSweep("tf-test-location", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMLocationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.phpipam_location.location_by_name", "location_id", "data.phpipam_location.location_by_id", "location_id"),
+					resource.TestCheckResourceAttrPair("data.phpipam_location.location_by_name", "name", "data.phpipam_location.location_by_id", "name"),
+					resource.TestCheckResourceAttr("data.phpipam_location.location_by_name", "description", "Terraform test location"),
+					resource.TestCheckResourceAttr("data.phpipam_location.location_by_name", "address", "1 Example Street"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,66 @@
+package phpipam
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/nameservers"
+)
+
+func dataSourcePHPIPAMNameserver() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourcePHPIPAMNameserverRead,
+		Schema: dataSourceNameserverSchema(),
+	}
+}
+
+func dataSourcePHPIPAMNameserverRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).nameserversController
+	var out nameservers.Nameserver
+	// We need to determine how to get the nameserver set. An ID search takes
+	// priority, and after that the nameserver set name.
+	switch {
+	case d.Get("nameserver_id").(int) != 0:
+		var err error
+		out, err = c.GetNameserverByID(d.Get("nameserver_id").(int))
+		if err != nil {
+			return err
+		}
+	case d.Get("name").(string) != "":
+		v, err := c.GetNameserversByName(d.Get("name").(string))
+		if err != nil {
+			if strings.Contains(err.Error(), "No results (filter applied)") {
+				log.Printf("Can't find nameserver with name %s", d.Get("name").(string))
+				return nil
+			}
+			return err
+		}
+		// Only one result should be returned by this search. Fail on multiples.
+		if len(v) != 1 {
+			return errors.New("Nameserver search returned either zero or multiple results. Please correct your search and try again")
+		}
+		out = v[0]
+	default:
+		// We need to ensure imported resources are not recreated when terraform apply is ran
+		// imported resources only have an Id which we need to map back to nameserver_id
+		id := d.Id()
+		if len(id) > 0 {
+			nameserver_id, err := strconv.Atoi(id)
+			if err != nil {
+				return err
+			}
+			out, err = c.GetNameserverByID(nameserver_id)
+			if err != nil {
+				return err
+			}
+		} else {
+			return errors.New("nameserver_id or name not defined, cannot proceed with reading data")
+		}
+	}
+
+	flattenNameserver(out, d)
+	return nil
+}
@@ -0,0 +1,46 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourcePHPIPAMNameserverConfig = `
+resource "phpipam_nameserver" "nameserver" {
+  name        = "tf-test-nameserver"
+  addresses   = ["10.10.0.53", "10.10.1.53"]
+  description = "Terraform test nameserver"
+}
+
+data "phpipam_nameserver" "nameserver_by_name" {
+  name       = "tf-test-nameserver"
+  depends_on = [phpipam_nameserver.nameserver]
+}
+
+data "phpipam_nameserver" "nameserver_by_id" {
+  nameserver_id = data.phpipam_nameserver.nameserver_by_name.nameserver_id
+  depends_on    = [data.phpipam_nameserver.nameserver_by_name]
+}
+`
+
+func TestAccDataSourcePHPIPAMNameserver(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			nameserverSweep("tf-test-nameserver", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMNameserverConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.phpipam_nameserver.nameserver_by_name", "nameserver_id", "data.phpipam_nameserver.nameserver_by_id", "nameserver_id"),
+					resource.TestCheckResourceAttrPair("data.phpipam_nameserver.nameserver_by_name", "name", "data.phpipam_nameserver.nameserver_by_id", "name"),
+					resource.TestCheckResourceAttr("data.phpipam_nameserver.nameserver_by_name", "description", "Terraform test nameserver"),
+					resource.TestCheckResourceAttr("data.phpipam_nameserver.nameserver_by_name", "addresses.#", "2"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package phpipam
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePHPIPAMSearch() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePHPIPAMSearchRead,
+		Schema:      dataSourceSearchSchema(),
+	}
+}
+
+func dataSourcePHPIPAMSearchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	m, err := newSearchMatcher(d.Get("query").(string), d.Get("regex").(bool))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// Search all object types unless only some of them are requested.
+	types := make(map[string]bool)
+	for _, v := range d.Get("object_types").(*schema.Set).List() {
+		types[v.(string)] = true
+	}
+	if len(types) == 0 {
+		for _, v := range searchObjectTypes {
+			types[v] = true
+		}
+	}
+
+	results, err := search(meta, m, types, d.Get("section_id").(int), d.Get("subnet_id").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(d.Get("query").(string))
+	if err := flattenSearchResults(results, d); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
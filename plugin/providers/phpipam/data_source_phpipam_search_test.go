@@ -0,0 +1,136 @@
+package phpipam
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDataSourcePHPIPAMSearch(t *testing.T) {
+	meta, server := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.50.0.0", 24)
+	subnet := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(subnetID)})
+	address := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.50.0.10",
+		"hostname":   "web01.example.internal",
+	})
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.50.0.11",
+		"hostname":   "db01.example.internal",
+	})
+	vlan := testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+		"name":        "web",
+		"number":      2050,
+		"description": "Web servers",
+	})
+	device := testFakeApply(t, meta, "phpipam_device", nil, map[string]interface{}{
+		"hostname": "WEB-SWITCH01",
+	})
+
+	// A search ignores case, and returns matches of all types.
+	byText := testFakeReadDataSource(t, meta, "phpipam_search", map[string]interface{}{
+		"query":      "web",
+		"section_id": subnet.Attributes["section_id"],
+	})
+	testFakeCheckIDs(t, byText, "address_ids", address)
+	testFakeCheckIDs(t, byText, "vlan_ids", vlan)
+	testFakeCheckIDs(t, byText, "device_ids", device)
+	testFakeCheckAttrs(t, byText, map[string]string{
+		"subnet_ids.#":            "0",
+		"results.#":               "3",
+		"results.0.object_type":   "addresses",
+		"results.0.label":         "10.50.0.10",
+		"results.0.matched_field": "hostname",
+		"results.0.matched_value": "web01.example.internal",
+		"results.0.subnet_id":     strconv.Itoa(subnetID),
+		"results.0.section_id":    subnet.Attributes["section_id"],
+		"results.1.object_type":   "vlans",
+		"results.2.object_type":   "devices",
+	})
+
+	// Without a section or subnet, addresses are looked up by the API rather
+	// than by reading the addresses of every subnet. A search for an IP
+	// address finds the address and the subnet that it belongs to.
+	var subnetReads int
+	server.OnRequest(func(r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/addresses/") && strings.Contains(r.URL.Path, "/subnets/") {
+			subnetReads++
+		}
+	})
+	byIP := testFakeReadDataSource(t, meta, "phpipam_search", map[string]interface{}{
+		"query": "10.50.0.11",
+	})
+	testFakeCheckAttrs(t, byIP, map[string]string{
+		"address_ids.#":           "1",
+		"subnet_ids.#":            "1",
+		"subnet_ids.0":            strconv.Itoa(subnetID),
+		"results.1.matched_field": "cidr",
+		"results.1.label":         "10.50.0.0/24",
+	})
+
+	byHostname := testFakeReadDataSource(t, meta, "phpipam_search", map[string]interface{}{
+		"query":        "web01.example.internal",
+		"object_types": []interface{}{"addresses"},
+	})
+	testFakeCheckIDs(t, byHostname, "address_ids", address)
+	testFakeCheckAttrs(t, byHostname, map[string]string{
+		"results.0.section_id": subnet.Attributes["section_id"],
+	})
+	if subnetReads != 0 {
+		t.Fatalf("expected no reads of the addresses of subnets, got %d", subnetReads)
+	}
+
+	// Regular expressions need the addresses of a subnet or section.
+	if _, err := testFakeReadDataSourceE(meta, "phpipam_search", map[string]interface{}{
+		"query":        "^web",
+		"regex":        true,
+		"object_types": []interface{}{"addresses"},
+	}); !strings.Contains(err, "requires section_id or subnet_id") {
+		t.Fatalf("expected scope error, got %q", err)
+	}
+	byRegex := testFakeReadDataSource(t, meta, "phpipam_search", map[string]interface{}{
+		"query":        "^(web|db)[0-9]+\\.",
+		"regex":        true,
+		"object_types": []interface{}{"addresses", "devices"},
+		"subnet_id":    subnetID,
+	})
+	testFakeCheckAttrs(t, byRegex, map[string]string{
+		"address_ids.#": "2",
+		"device_ids.#":  "0",
+		"vlan_ids.#":    "0",
+	})
+
+	// Within a subnet, subnets are searched in the subnet and the subnets
+	// nested within it.
+	nested := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":       subnet.Attributes["section_id"],
+		"master_subnet_id": subnetID,
+		"subnet_address":   "10.50.0.128",
+		"subnet_mask":      25,
+		"description":      "web nested",
+	})
+	testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     subnet.Attributes["section_id"],
+		"subnet_address": "10.60.0.0",
+		"subnet_mask":    24,
+		"description":    "web other",
+	})
+	bySubnet := testFakeReadDataSource(t, meta, "phpipam_search", map[string]interface{}{
+		"query":        "web",
+		"object_types": []interface{}{"subnets"},
+		"subnet_id":    subnetID,
+	})
+	testFakeCheckIDs(t, bySubnet, "subnet_ids", nested)
+
+	if _, err := testFakeReadDataSourceE(meta, "phpipam_search", map[string]interface{}{
+		"query": "(",
+		"regex": true,
+	}); err == "" {
+		t.Fatal("expected an invalid regular expression to fail")
+	}
+}
@@ -24,12 +24,12 @@ data "phpipam_section" "section_by_id" {
 `
 
 func TestAccDataSourcePHPIPAMSection(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMSectionConfig,
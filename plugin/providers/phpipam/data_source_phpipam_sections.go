@@ -0,0 +1,57 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePHPIPAMSections() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePHPIPAMSectionsRead,
+		Schema: map[string]*schema.Schema{
+			"name_match":          subnetDescriptionMatchSchema([]string{}),
+			"description_match":   subnetDescriptionMatchSchema([]string{}),
+			"custom_field_filter": customFieldFilterSchema([]string{}),
+			"section_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"sections": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceSectionsSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePHPIPAMSectionsRead(d *schema.ResourceData, meta interface{}) error {
+	out, err := sectionSearch(d, meta)
+	if err != nil {
+		return err
+	}
+	var sum int
+	ids := make([]int, 0)
+	list := make([]interface{}, 0)
+	for _, v := range out {
+		sum += v.ID
+		ids = append(ids, v.ID)
+		list = append(list, flattenSectionMap(v))
+	}
+
+	d.SetId(strconv.Itoa(sum))
+	err = d.Set("section_ids", ids)
+	if err != nil {
+		return err
+	}
+	err = d.Set("sections", list)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
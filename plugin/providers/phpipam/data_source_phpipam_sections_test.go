@@ -0,0 +1,52 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDataSourcePHPIPAMSections(t *testing.T) {
+	meta, s := testFakeProviderMeta(t)
+	s.AddCustomField("sections", "custom_CustomTestSections", "varchar(255)")
+
+	var sections []*terraform.InstanceState
+	for _, name := range []string{"tf-test-sections-1", "tf-test-sections-2"} {
+		sections = append(sections, testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+			"name":        name,
+			"description": "Terraform test section (multiple sections data source)",
+		}))
+	}
+	s.Insert("sections", map[string]interface{}{
+		"name":                      "tf-test-sections-custom",
+		"custom_CustomTestSections": "terraform-test-multiple",
+	})
+
+	all := testFakeReadDataSource(t, meta, "phpipam_sections", map[string]interface{}{})
+	testFakeCheckAttrs(t, all, map[string]string{
+		"section_ids.#":          "5",
+		"sections.0.name":        "Customers",
+		"sections.3.name":        "tf-test-sections-2",
+		"sections.3.description": "Terraform test section (multiple sections data source)",
+	})
+
+	byName := testFakeReadDataSource(t, meta, "phpipam_sections", map[string]interface{}{
+		"name_match": "^tf-test-sections-[0-9]+$",
+	})
+	testFakeCheckIDs(t, byName, "section_ids", sections...)
+
+	byDescription := testFakeReadDataSource(t, meta, "phpipam_sections", map[string]interface{}{
+		"description_match": "multiple sections data source",
+	})
+	testFakeCheckIDs(t, byDescription, "section_ids", sections...)
+
+	byCustomFields := testFakeReadDataSource(t, meta, "phpipam_sections", map[string]interface{}{
+		"custom_field_filter": map[string]interface{}{
+			"custom_CustomTestSections": "^terraform-test",
+		},
+	})
+	testFakeCheckAttrs(t, byCustomFields, map[string]string{
+		"sections.#":      "1",
+		"sections.0.name": "tf-test-sections-custom",
+	})
+}
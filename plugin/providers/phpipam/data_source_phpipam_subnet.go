@@ -97,7 +97,7 @@ func dataSourcePHPIPAMSubnetRead(ctx context.Context, d *schema.ResourceData, me
 	return nil
 }
 
-func checkSubnetsCustomFiledsExists(d *schema.ResourceData, client *subnets.Controller) bool {
+func checkSubnetsCustomFiledsExists(d *schema.ResourceData, client *subnetsController) bool {
 	if _, ok := d.GetOk("custom_field_filter"); ok {
 		return true
 	} else if _, err := client.GetSubnetCustomFieldsSchema(); err == nil {
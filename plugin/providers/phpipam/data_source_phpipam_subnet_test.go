@@ -82,12 +82,12 @@ data "phpipam_subnet" "custom_search" {
 `
 
 func TestAccDataSourcePHPIPAMSubnet(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMSubnetConfig,
@@ -105,12 +105,12 @@ func TestAccDataSourcePHPIPAMSubnet(t *testing.T) {
 }
 
 func TestAccDataSourcePHPIPAMSubnet_CustomFields(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMSubnetCustomFieldConfig,
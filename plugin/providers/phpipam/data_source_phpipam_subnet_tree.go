@@ -0,0 +1,72 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+func dataSourcePHPIPAMSubnetTree() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePHPIPAMSubnetTreeRead,
+		Schema:      dataSourceSubnetTreeSchema(),
+	}
+}
+
+func dataSourcePHPIPAMSubnetTreeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	var list []subnets.Subnet
+	switch {
+	case d.Get("subnet_id").(int) != 0:
+		id := d.Get("subnet_id").(int)
+		root, err := c.GetSubnetByID(id)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		children, err := c.GetSubnetsInSubnetRecursive(id)
+		if err != nil && apiErrorCode(err) != 404 {
+			return diag.FromErr(fmt.Errorf("Could not read child subnets of subnet ID %d: %s", id, err))
+		}
+		list = append(list, root)
+		for _, v := range children {
+			if v.ID != id {
+				list = append(list, v)
+			}
+		}
+		d.SetId(strconv.Itoa(id))
+	default:
+		id := d.Get("section_id").(int)
+		var err error
+		list, err = meta.(*ProviderPHPIPAMClient).sectionsController.GetSubnetsInSection(id)
+		if err != nil && apiErrorCode(err) != 404 {
+			return diag.FromErr(fmt.Errorf("Could not read subnets of section ID %d: %s", id, err))
+		}
+		d.SetId(strconv.Itoa(id))
+	}
+
+	roots := buildSubnetTree(list)
+	var err error
+	walkSubnetTree(roots, func(n *subnetTreeNode) {
+		if err != nil || subnetCIDR(n.Subnet) == "" {
+			return
+		}
+		var usage SubnetUsage
+		if usage, err = c.GetSubnetUsage(n.Subnet.ID); err != nil {
+			err = fmt.Errorf("Could not read usage of subnet %s: %s", subnetCIDR(n.Subnet), err)
+			return
+		}
+		n.Usage = &usage
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := flattenSubnetTree(roots, d); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package phpipam
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDataSourcePHPIPAMSubnetTree(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	rootID := testFakeSubnet(t, meta, "10.30.0.0", 16)
+	testFakeSubnet(t, meta, "10.31.0.0", 24)
+	root := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(rootID)})
+	sectionID, _ := strconv.Atoi(root.Attributes["section_id"])
+
+	nested := func(master int, address string, mask int) int {
+		state := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+			"section_id":       sectionID,
+			"master_subnet_id": master,
+			"subnet_address":   address,
+			"subnet_mask":      mask,
+			"description":      address,
+		})
+		id, _ := strconv.Atoi(state.ID)
+		return id
+	}
+	// Created out of order, to check that siblings are sorted by address.
+	secondID := nested(rootID, "10.30.1.0", 24)
+	firstID := nested(rootID, "10.30.0.0", 24)
+	leafID := nested(firstID, "10.30.0.0", 26)
+	for _, ip := range []string{"10.30.0.10", "10.30.0.11"} {
+		testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+			"subnet_id":  leafID,
+			"ip_address": ip,
+		})
+	}
+
+	bySubnet := testFakeReadDataSource(t, meta, "phpipam_subnet_tree", map[string]interface{}{
+		"subnet_id": rootID,
+	})
+	testFakeCheckAttrs(t, bySubnet, map[string]string{
+		"subnets.#":                     "4",
+		"subnets.0.subnet_id":           strconv.Itoa(rootID),
+		"subnets.0.cidr":                "10.30.0.0/16",
+		"subnets.0.depth":               "0",
+		"subnets.0.child_subnet_ids.#":  "2",
+		"subnets.0.child_subnet_ids.0":  strconv.Itoa(firstID),
+		"subnets.0.child_subnet_ids.1":  strconv.Itoa(secondID),
+		"subnets.1.subnet_id":           strconv.Itoa(firstID),
+		"subnets.1.depth":               "1",
+		"subnets.2.subnet_id":           strconv.Itoa(leafID),
+		"subnets.2.cidr":                "10.30.0.0/26",
+		"subnets.2.description":         "10.30.0.0",
+		"subnets.2.depth":               "2",
+		"subnets.2.used_hosts":          "2",
+		"subnets.2.max_hosts":           "62",
+		"subnets.2.free_hosts":          "60",
+		"subnets.2.child_subnet_ids.#":  "0",
+		"subnets.3.subnet_id":           strconv.Itoa(secondID),
+		"subnets.3.depth":               "1",
+		"subnets.3.utilization_percent": "0",
+	})
+
+	bySection := testFakeReadDataSource(t, meta, "phpipam_subnet_tree", map[string]interface{}{
+		"section_id": sectionID,
+	})
+	testFakeCheckAttrs(t, bySection, map[string]string{
+		"subnets.#":      "5",
+		"subnets.0.cidr": "10.30.0.0/16",
+		"subnets.4.cidr": "10.31.0.0/24",
+	})
+
+	var tree []struct {
+		CIDR     string `json:"cidr"`
+		Children []struct {
+			CIDR     string `json:"cidr"`
+			Children []struct {
+				CIDR string `json:"cidr"`
+			} `json:"children"`
+		} `json:"children"`
+	}
+	if err := json.Unmarshal([]byte(bySection.Attributes["tree_json"]), &tree); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if len(tree) != 2 || tree[0].CIDR != "10.30.0.0/16" || len(tree[0].Children) != 2 || len(tree[0].Children[0].Children) != 1 || tree[0].Children[0].Children[0].CIDR != "10.30.0.0/26" {
+		t.Fatalf("unexpected tree: %s", bySection.Attributes["tree_json"])
+	}
+}
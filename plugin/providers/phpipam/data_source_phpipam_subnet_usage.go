@@ -0,0 +1,45 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePHPIPAMSubnetUsage() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourcePHPIPAMSubnetUsageRead,
+		Schema:      dataSourceSubnetUsageSchema(),
+	}
+}
+
+func dataSourcePHPIPAMSubnetUsageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	id := d.Get("subnet_id").(int)
+	subnet, err := c.GetSubnetByID(id)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	usage, err := c.GetSubnetUsage(id)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not read usage of subnet ID %d: %s", id, err))
+	}
+
+	// The usage does not include the number of addresses with each tag, so
+	// count them from the subnet's addresses.
+	list, err := c.GetAddressesInSubnet(id)
+	if err != nil && apiErrorCode(err) != 404 {
+		return diag.FromErr(fmt.Errorf("Could not read addresses of subnet ID %d: %s", id, err))
+	}
+	tagList, err := meta.(*ProviderPHPIPAMClient).tagsController.ListTags()
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Could not read address tags: %s", err))
+	}
+
+	d.SetId(strconv.Itoa(id))
+	flattenSubnetUsage(subnet, usage, subnetTagCounts(list, tagList), d)
+	return nil
+}
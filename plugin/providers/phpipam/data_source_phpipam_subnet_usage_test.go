@@ -0,0 +1,64 @@
+package phpipam
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDataSourcePHPIPAMSubnetUsage(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.40.0.0", 29)
+	state := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(subnetID)})
+	config := map[string]interface{}{
+		"section_id":            state.Attributes["section_id"],
+		"subnet_address":        "10.40.0.0",
+		"subnet_mask":           29,
+		"utilization_threshold": 50,
+	}
+	testFakeApply(t, meta, "phpipam_subnet", state, config)
+
+	addresses := map[string]int{
+		"10.40.0.1": 2,
+		"10.40.0.2": 2,
+		"10.40.0.3": 3,
+	}
+	for ip, tag := range addresses {
+		testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+			"subnet_id":    subnetID,
+			"ip_address":   ip,
+			"state_tag_id": tag,
+		})
+	}
+
+	usage := testFakeReadDataSource(t, meta, "phpipam_subnet_usage", map[string]interface{}{
+		"subnet_id": subnetID,
+	})
+	testFakeCheckAttrs(t, usage, map[string]string{
+		"cidr":                  "10.40.0.0/29",
+		"used_hosts":            "3",
+		"max_hosts":             "6",
+		"free_hosts":            "3",
+		"free_hosts_percent":    "50",
+		"utilization_percent":   "50",
+		"utilization_threshold": "50",
+		"above_threshold":       "false",
+		"tag_counts.%":          "4",
+		"tag_counts.Used":       "2",
+		"tag_counts.Reserved":   "1",
+		"tag_counts.Offline":    "0",
+	})
+
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.40.0.4",
+	})
+	usage = testFakeReadDataSource(t, meta, "phpipam_subnet_usage", map[string]interface{}{
+		"subnet_id": subnetID,
+	})
+	testFakeCheckAttrs(t, usage, map[string]string{
+		"used_hosts":      "4",
+		"above_threshold": "true",
+	})
+}
@@ -25,6 +25,13 @@ func dataSourcePHPIPAMSubnets() *schema.Resource {
 				Computed: true,
 				Elem:     &schema.Schema{Type: schema.TypeInt},
 			},
+			"subnets": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceSubnetsSchema(),
+				},
+			},
 		},
 	}
 }
@@ -34,11 +41,27 @@ func dataSourcePHPIPAMSubnetsRead(d *schema.ResourceData, meta interface{}) erro
 	if err != nil {
 		return err
 	}
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	// Custom fields are only part of the subnets when they are nested in the
+	// API responses. Otherwise, they are read for each subnet separately.
+	readCustomFields := !meta.(*ProviderPHPIPAMClient).NestCustomFields && checkSubnetsCustomFiledsExists(d, c)
 	var sum int
 	ids := make([]int, 0)
+	list := make([]interface{}, 0)
 	for _, v := range out {
 		sum += v.ID
 		ids = append(ids, v.ID)
+		m := flattenSubnetMap(v)
+		// Skip folders for now as there is issues pulling them down in the API.
+		if readCustomFields && !bool(v.IsFolder) {
+			fields, err := c.GetSubnetCustomFields(v.ID)
+			if err != nil {
+				return err
+			}
+			trimMap(fields)
+			m["custom_fields"] = fields
+		}
+		list = append(list, m)
 	}
 
 	d.SetId(strconv.Itoa(sum))
@@ -46,6 +69,10 @@ func dataSourcePHPIPAMSubnetsRead(d *schema.ResourceData, meta interface{}) erro
 	if err != nil {
 		return err
 	}
+	err = d.Set("subnets", list)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -74,12 +74,12 @@ output "actual_subnet_ids_custom_fields" {
 `
 
 func TestAccDataSourcePHPIPAMSubnets(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMSubnetsConfigStage1,
@@ -90,6 +90,13 @@ func TestAccDataSourcePHPIPAMSubnets(t *testing.T) {
 					testCheckOutputPair("expected_subnet_ids", "actual_subnet_ids_description"),
 					testCheckOutputPair("expected_subnet_ids", "actual_subnet_ids_description_match"),
 					testCheckOutputPair("expected_subnet_ids", "actual_subnet_ids_custom_fields"),
+					resource.TestCheckResourceAttr("data.phpipam_subnets.subnets_by_description", "subnets.#", "3"),
+					resource.TestCheckResourceAttrPair("data.phpipam_subnets.subnets_by_description", "subnets.0.subnet_id", "phpipam_subnet.subnets.0", "subnet_id"),
+					resource.TestCheckResourceAttr("data.phpipam_subnets.subnets_by_description", "subnets.0.subnet_address", "10.10.3.0"),
+					resource.TestCheckResourceAttr("data.phpipam_subnets.subnets_by_description", "subnets.0.subnet_mask", "24"),
+					resource.TestCheckResourceAttrPair("data.phpipam_subnets.subnets_by_description", "subnets.0.section_id", "phpipam_section.section", "section_id"),
+					resource.TestCheckResourceAttr("data.phpipam_subnets.subnets_by_description", "subnets.0.custom_fields.custom_CustomTestSubnets2", "Entry 10.10.3.0/24"),
+					resource.TestCheckResourceAttr("data.phpipam_subnets.subnets_by_description", "subnets.2.subnet_address", "10.10.5.0"),
 				),
 			},
 		},
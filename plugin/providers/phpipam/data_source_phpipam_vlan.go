@@ -82,7 +82,7 @@ func dataSourcePHPIPAMVLANRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func checkVlansCustomFiledsExists(d *schema.ResourceData, client *vlans.Controller) bool {
+func checkVlansCustomFiledsExists(d *schema.ResourceData, client *vlansController) bool {
 	if _, ok := d.GetOk("custom_field_filter"); ok {
 		return true
 	} else if _, err := client.GetVLANCustomFieldsSchema(); err == nil {
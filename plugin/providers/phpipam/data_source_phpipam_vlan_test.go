@@ -33,12 +33,12 @@ data "phpipam_vlan" "vlan_by_id" {
 `
 
 func TestAccDataSourcePHPIPAMVLAN(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			l2domainSweep("tf-test-l2domain", t)
 		},
-		Providers: testAccProviders,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccDataSourcePHPIPAMVLANConfig,
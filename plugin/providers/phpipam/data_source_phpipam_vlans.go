@@ -0,0 +1,72 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourcePHPIPAMVLANs() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourcePHPIPAMVLANsRead,
+		Schema: map[string]*schema.Schema{
+			"l2_domain_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"name_match":          subnetDescriptionMatchSchema([]string{}),
+			"description_match":   subnetDescriptionMatchSchema([]string{}),
+			"custom_field_filter": customFieldFilterSchema([]string{}),
+			"vlan_ids": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+			"vlans": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: dataSourceVLANsSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePHPIPAMVLANsRead(d *schema.ResourceData, meta interface{}) error {
+	out, err := vlanSearch(d, meta)
+	if err != nil {
+		return err
+	}
+	c := meta.(*ProviderPHPIPAMClient).vlansController
+	readCustomFields := len(out) > 0 && checkVlansCustomFiledsExists(d, c)
+	var sum int
+	ids := make([]int, 0)
+	list := make([]interface{}, 0)
+	for _, v := range out {
+		sum += v.ID
+		ids = append(ids, v.ID)
+		m := flattenVLANMap(v)
+		if readCustomFields {
+			fields, err := c.GetVLANCustomFields(v.ID)
+			if err != nil {
+				return err
+			}
+			trimMap(fields)
+			m["custom_fields"] = fields
+		}
+		list = append(list, m)
+	}
+
+	d.SetId(strconv.Itoa(sum))
+	err = d.Set("vlan_ids", ids)
+	if err != nil {
+		return err
+	}
+	err = d.Set("vlans", list)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package phpipam
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestDataSourcePHPIPAMVLANs(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	domain := testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name": "tf-test-l2domain",
+	})
+	domainID, _ := strconv.Atoi(domain.ID)
+	var vlans []*terraform.InstanceState
+	for i := 0; i < 3; i++ {
+		vlans = append(vlans, testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+			"name":         fmt.Sprintf("tf-test-vlan-%d", i),
+			"number":       2001 + i,
+			"l2_domain_id": domainID,
+			"description":  "Terraform test vlan (multiple vlans data source)",
+			"custom_fields": map[string]interface{}{
+				"custom_CustomTestVLANs": fmt.Sprintf("terraform-test-%d", i),
+			},
+		}))
+	}
+	other := testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+		"name":   "tf-other-vlan",
+		"number": 3001,
+	})
+
+	all := testFakeReadDataSource(t, meta, "phpipam_vlans", map[string]interface{}{})
+	testFakeCheckIDs(t, all, "vlan_ids", append(vlans, other)...)
+
+	byDomain := testFakeReadDataSource(t, meta, "phpipam_vlans", map[string]interface{}{
+		"l2_domain_id": domainID,
+	})
+	testFakeCheckIDs(t, byDomain, "vlan_ids", vlans...)
+	testFakeCheckAttrs(t, byDomain, map[string]string{
+		"vlans.1.name":         "tf-test-vlan-1",
+		"vlans.1.number":       "2002",
+		"vlans.1.l2_domain_id": domain.ID,
+		"vlans.1.custom_fields.custom_CustomTestVLANs": "terraform-test-1",
+	})
+
+	byName := testFakeReadDataSource(t, meta, "phpipam_vlans", map[string]interface{}{
+		"name_match": "^tf-test-vlan-",
+	})
+	testFakeCheckIDs(t, byName, "vlan_ids", vlans...)
+
+	byCustomFields := testFakeReadDataSource(t, meta, "phpipam_vlans", map[string]interface{}{
+		"description_match": "multiple vlans data source",
+		"custom_field_filter": map[string]interface{}{
+			"custom_CustomTestVLANs": "-[02]$",
+		},
+	})
+	testFakeCheckIDs(t, byCustomFields, "vlan_ids", vlans[0], vlans[2])
+}
@@ -0,0 +1,94 @@
+package phpipam
+
+import (
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/vrfs"
+)
+
+func dataSourcePHPIPAMVRF() *schema.Resource {
+	return &schema.Resource{
+		Read:   dataSourcePHPIPAMVRFRead,
+		Schema: dataSourceVRFSchema(),
+	}
+}
+
+func dataSourcePHPIPAMVRFRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).vrfsController
+	var out vrfs.VRF
+	// We need to determine how to get the VRF. An ID search takes priority,
+	// and after that the name and the route distinguisher.
+	switch {
+	case d.Get("vrf_id").(int) != 0:
+		var err error
+		out, err = c.GetVRFByID(d.Get("vrf_id").(int))
+		if err != nil {
+			return err
+		}
+	case d.Get("name").(string) != "":
+		v, err := c.GetVRFsByName(d.Get("name").(string))
+		if err != nil {
+			if strings.Contains(err.Error(), "No results (filter applied)") {
+				log.Printf("Can't find VRF with name %s", d.Get("name").(string))
+				return nil
+			}
+			return err
+		}
+		// Only one result should be returned by this search. Fail on multiples.
+		if len(v) != 1 {
+			return errors.New("VRF search returned either zero or multiple results. Please correct your search and try again")
+		}
+		out = v[0]
+	case d.Get("rd").(string) != "":
+		v, err := c.GetVRFsByRD(d.Get("rd").(string))
+		if err != nil {
+			return err
+		}
+		// Only one result should be returned by this search. Fail on multiples.
+		if len(v) != 1 {
+			return errors.New("VRF search returned either zero or multiple results. Please correct your search and try again")
+		}
+		out = v[0]
+	default:
+		// We need to ensure imported resources are not recreated when terraform apply is ran
+		// imported resources only have an Id which we need to map back to vrf_id
+		id := d.Id()
+		if len(id) > 0 {
+			vrf_id, err := strconv.Atoi(id)
+			if err != nil {
+				return err
+			}
+			out, err = c.GetVRFByID(vrf_id)
+			if err != nil {
+				return err
+			}
+		} else {
+			return errors.New("vrf_id, name or rd not defined, cannot proceed with reading data")
+		}
+	}
+
+	if checkVRFsCustomFieldsExists(c) {
+		fields, err := c.GetVRFCustomFields(out.ID)
+		if err != nil {
+			return err
+		}
+		trimMap(fields)
+		if err := d.Set("custom_fields", fields); err != nil {
+			return err
+		}
+	}
+
+	flattenVRF(out, d)
+	return nil
+}
+
+// checkVRFsCustomFieldsExists returns true if there are custom fields defined
+// for the VRF controller.
+func checkVRFsCustomFieldsExists(client *vrfsController) bool {
+	_, err := client.GetVRFCustomFieldsSchema()
+	return err == nil
+}
@@ -0,0 +1,52 @@
+package phpipam
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+const testAccDataSourcePHPIPAMVRFConfig = `
+resource "phpipam_vrf" "vrf" {
+  name        = "tf-test-vrf"
+  rd          = "65000:100"
+  description = "Terraform test vrf"
+}
+
+data "phpipam_vrf" "vrf_by_name" {
+  name       = "tf-test-vrf"
+  depends_on = [phpipam_vrf.vrf]
+}
+
+data "phpipam_vrf" "vrf_by_rd" {
+  rd         = "65000:100"
+  depends_on = [phpipam_vrf.vrf]
+}
+
+data "phpipam_vrf" "vrf_by_id" {
+  vrf_id     = data.phpipam_vrf.vrf_by_name.vrf_id
+  depends_on = [data.phpipam_vrf.vrf_by_name]
+}
+`
+
+func TestAccDataSourcePHPIPAMVRF(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			vrfSweep("tf-test-vrf", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccDataSourcePHPIPAMVRFConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.phpipam_vrf.vrf_by_name", "vrf_id", "data.phpipam_vrf.vrf_by_id", "vrf_id"),
+					resource.TestCheckResourceAttrPair("data.phpipam_vrf.vrf_by_name", "vrf_id", "data.phpipam_vrf.vrf_by_rd", "vrf_id"),
+					resource.TestCheckResourceAttrPair("data.phpipam_vrf.vrf_by_name", "name", "data.phpipam_vrf.vrf_by_id", "name"),
+					resource.TestCheckResourceAttr("data.phpipam_vrf.vrf_by_name", "rd", "65000:100"),
+					resource.TestCheckResourceAttr("data.phpipam_vrf.vrf_by_name", "description", "Terraform test vrf"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,214 @@
+package phpipam
+
+import (
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/devices"
+)
+
+// resourceDeviceOptionalFields represents all the fields that are optional in
+// the phpipam_device resource. These fields get flagged as Optional, with zero
+// value defaults (the field is not set), in addition to being marked as
+// Computed. Any field not listed here cannot be supplied by the resource and
+// is solely computed.
+var resourceDeviceOptionalFields = linearSearchSlice{
+	"ip_address",
+	"type_id",
+	"vendor",
+	"model",
+	"description",
+	"sections",
+	"location_id",
+	"rack_id",
+	"rack_start",
+	"rack_size",
+}
+
+// bareDeviceSchema returns a map[string]*schema.Schema with the schema used
+// to represent a PHPIPAM device resource. This output should then be modified
+// so that required and computed fields are set properly for both the data
+// source and the resource.
+func bareDeviceSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"device_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"hostname": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"ip_address": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"type_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"vendor": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"model": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"description": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"sections": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"location_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"rack_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"rack_start": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"rack_size": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"edit_date": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"custom_fields": &schema.Schema{
+			Type: schema.TypeMap,
+		},
+	}
+}
+
+// resourceDeviceSchema returns the schema for the phpipam_device resource.
+// It sets the required and optional fields, the latter defined in
+// resourceDeviceOptionalFields, and ensures that all optional and
+// non-configurable fields are computed as well.
+func resourceDeviceSchema() map[string]*schema.Schema {
+	schema := bareDeviceSchema()
+	for k, v := range schema {
+		switch {
+		// Device host name is required
+		case k == "hostname":
+			v.Required = true
+		case k == "custom_fields":
+			v.Optional = true
+		case resourceDeviceOptionalFields.Has(k):
+			v.Optional = true
+			v.Computed = true
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// dataSourceDeviceSchema returns the schema for the phpipam_device data
+// source. It sets the searchable fields and sets up the attribute conflicts
+// between device ID and host name. It also ensures that all fields are
+// computed as well.
+func dataSourceDeviceSchema() map[string]*schema.Schema {
+	schema := bareDeviceSchema()
+	for k, v := range schema {
+		switch k {
+		case "device_id":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"hostname"}
+		case "hostname":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"device_id"}
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// expandDevice returns the devices.Device structure for a phpipam_device
+// resource or data source. Depending on if we are dealing with the resource
+// or data source, extra considerations may need to be taken.
+func expandDevice(d *schema.ResourceData) devices.Device {
+	s := devices.Device{
+		ID:          d.Get("device_id").(int),
+		Hostname:    d.Get("hostname").(string),
+		IPAddress:   d.Get("ip_address").(string),
+		Type:        d.Get("type_id").(int),
+		Vendor:      d.Get("vendor").(string),
+		Model:       d.Get("model").(string),
+		Description: d.Get("description").(string),
+		Sections:    d.Get("sections").(string),
+		Location:    d.Get("location_id").(int),
+		Rack:        d.Get("rack_id").(int),
+		RackStart:   d.Get("rack_start").(int),
+		RackSize:    d.Get("rack_size").(int),
+	}
+
+	return s
+}
+
+// flattenDevice sets fields in a *schema.ResourceData with fields supplied by
+// the input devices.Device. This is used in read operations.
+func flattenDevice(s devices.Device, d *schema.ResourceData) {
+	d.SetId(strconv.Itoa(s.ID))
+	d.Set("device_id", s.ID)
+	d.Set("hostname", s.Hostname)
+	d.Set("ip_address", s.IPAddress)
+	d.Set("type_id", s.Type)
+	d.Set("vendor", s.Vendor)
+	d.Set("model", s.Model)
+	d.Set("description", s.Description)
+	d.Set("sections", s.Sections)
+	d.Set("location_id", s.Location)
+	d.Set("rack_id", s.Rack)
+	d.Set("rack_start", s.RackStart)
+	d.Set("rack_size", s.RackSize)
+	d.Set("edit_date", s.EditDate)
+}
+
+// deviceSearch provides the search functionality for the phpipam_devices data
+// source, returning a []devices.Device of all devices that match every filter
+// that has been supplied. If no filters are supplied, all devices are
+// returned.
+func deviceSearch(d *schema.ResourceData, meta interface{}) ([]devices.Device, error) {
+	c := meta.(*ProviderPHPIPAMClient).devicesController
+	result := make([]devices.Device, 0)
+
+	v, err := c.ListDevices()
+	if err != nil {
+		return result, err
+	}
+	for _, r := range v {
+		// Don't trap regex errors here because we should have already validated
+		// the expressions via the ValidateFunc.
+		if expr := d.Get("hostname_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Hostname); !matched {
+				continue
+			}
+		}
+		if expr := d.Get("description_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Description); !matched {
+				continue
+			}
+		}
+		if id := d.Get("type_id").(int); id != 0 && r.Type != id {
+			continue
+		}
+		if id := d.Get("location_id").(int); id != 0 && r.Location != id {
+			continue
+		}
+		if search := d.Get("custom_field_filter").(map[string]interface{}); len(search) > 0 {
+			fields, err := c.GetDeviceCustomFields(r.ID)
+			if err != nil {
+				return result, err
+			}
+			matched, err := customFieldFilter(fields, search)
+			if err != nil {
+				return result, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,426 @@
+package phpipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// The export mode of the provider binary writes Terraform configuration for
+// the objects of an existing PHPIPAM instance, along with import blocks, so
+// that they can be brought under management with terraform plan and apply.
+//
+// Every object is read through the Read function of its resource, exactly as
+// it would be after terraform import, so that the generated configuration
+// produces an empty plan once imported.
+
+// ExportOptions controls which objects Export writes configuration for.
+type ExportOptions struct {
+	// The directory the .tf files are written to. It is created if needed.
+	Dir string
+
+	// The name of a section. When set, only this section, its subnets and
+	// their addresses are exported, along with the VLANs and L2 domains that
+	// the subnets are in.
+	Section string
+
+	// The CIDR or ID of a subnet. When set, only this subnet, the subnets
+	// nested within it and their addresses are exported, along with the VLANs
+	// and L2 domains that the subnets are in.
+	Subnet string
+}
+
+// exportResources lists the exported resource types, in the order that
+// their files are written.
+var exportResources = []struct {
+	name string
+	file string
+}{
+	{"phpipam_l2domain", "l2domains.tf"},
+	{"phpipam_vlan", "vlans.tf"},
+	{"phpipam_section", "sections.tf"},
+	{"phpipam_subnet", "subnets.tf"},
+	{"phpipam_address", "addresses.tf"},
+}
+
+// exportIDFields maps the referenced resource types to the field that holds
+// the ID of the object.
+var exportIDFields = map[string]string{
+	"phpipam_l2domain": "domain_id",
+	"phpipam_vlan":     "vlan_id",
+	"phpipam_section":  "section_id",
+	"phpipam_subnet":   "subnet_id",
+}
+
+// exportReferences maps the fields of exported resources that hold the ID of
+// another object to the resource type of that object. These fields are
+// written as references when the object is exported as well.
+var exportReferences = map[string]map[string]string{
+	"phpipam_vlan": {
+		"l2_domain_id": "phpipam_l2domain",
+	},
+	"phpipam_section": {
+		"master_section_id": "phpipam_section",
+	},
+	"phpipam_subnet": {
+		"section_id":       "phpipam_section",
+		"master_subnet_id": "phpipam_subnet",
+		"linked_subnet_id": "phpipam_subnet",
+		"vlan_id":          "phpipam_vlan",
+	},
+	"phpipam_address": {
+		"subnet_id": "phpipam_subnet",
+	},
+}
+
+// exportSkipFields lists the meta-parameters that are not written, as they
+// are not part of the objects in PHPIPAM.
+var exportSkipFields = linearSearchSlice{
+	"adopt_existing",
+	"allocation_key",
+	"keep_on_destroy",
+	"remove_dns_on_delete",
+}
+
+// exportObject is an object that is written to the generated configuration.
+type exportObject struct {
+	// The resource type of the object, such as phpipam_subnet.
+	resource string
+
+	// The ID of the object in PHPIPAM.
+	id int
+
+	// The name of the resource block, unique within its resource type.
+	name string
+
+	// The state of the object, as read by its resource.
+	data *schema.ResourceData
+}
+
+// exporter holds the objects collected by Export.
+type exporter struct {
+	client *ProviderPHPIPAMClient
+
+	// The objects to export, by resource type.
+	objects map[string][]*exportObject
+
+	// The resource block addresses of the exported objects, by resource type
+	// and ID, such as phpipam_section.customers.
+	addresses map[string]map[int]string
+
+	// The resource block names in use, by resource type.
+	names map[string]map[string]bool
+}
+
+// Export connects to PHPIPAM with config, and writes Terraform configuration
+// and import blocks for the sections, L2 domains, VLANs, subnets and
+// addresses selected by opts to .tf files, one per resource type.
+func Export(config Config, opts ExportOptions) error {
+	meta, err := config.Client()
+	if err != nil {
+		return err
+	}
+	return exportConfiguration(meta, opts)
+}
+
+// exportConfiguration implements Export with an existing provider client.
+func exportConfiguration(meta interface{}, opts ExportOptions) error {
+	if opts.Section != "" && opts.Subnet != "" {
+		return errors.New("Only one of a section or a subnet can be exported at a time")
+	}
+	e := &exporter{
+		client:    meta.(*ProviderPHPIPAMClient),
+		objects:   make(map[string][]*exportObject),
+		addresses: make(map[string]map[int]string),
+		names:     make(map[string]map[string]bool),
+	}
+	if err := e.collect(opts); err != nil {
+		return err
+	}
+
+	dir := opts.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	for _, r := range exportResources {
+		if len(e.objects[r.name]) == 0 {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, r.file), e.render(r.name), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collect reads the objects selected by opts.
+func (e *exporter) collect(opts ExportOptions) error {
+	var subnetList []subnets.Subnet
+	switch {
+	case opts.Subnet != "":
+		root, err := e.exportRootSubnet(opts.Subnet)
+		if err != nil {
+			return err
+		}
+		children, err := e.client.subnetsController.GetSubnetsInSubnetRecursive(root.ID)
+		if err != nil && apiErrorCode(err) != 404 {
+			return err
+		}
+		subnetList = append([]subnets.Subnet{root}, children...)
+	default:
+		sectionList, err := e.client.sectionsController.ListSections()
+		if err != nil {
+			return err
+		}
+		for _, s := range sectionList {
+			if opts.Section != "" && s.Name != opts.Section {
+				continue
+			}
+			if err := e.add("phpipam_section", s.ID, s.Name); err != nil {
+				return err
+			}
+			out, err := e.client.sectionsController.GetSubnetsInSection(s.ID)
+			if err != nil && apiErrorCode(err) != 404 {
+				return err
+			}
+			subnetList = append(subnetList, out...)
+		}
+		if opts.Section != "" && len(e.objects["phpipam_section"]) == 0 {
+			return fmt.Errorf("Cannot export section %q: no section with this name", opts.Section)
+		}
+	}
+
+	vlanIDs := make(map[int]bool)
+	for _, s := range subnetList {
+		// Folders are not subnets that can be managed by phpipam_subnet, and
+		// subnets within them keep the folder ID as their master subnet ID.
+		if s.IsFolder {
+			continue
+		}
+		if err := e.add("phpipam_subnet", s.ID, fmt.Sprintf("subnet_%s_%d", s.SubnetAddress, s.Mask)); err != nil {
+			return err
+		}
+		if s.VLANID != 0 {
+			vlanIDs[s.VLANID] = true
+		}
+		list, err := e.client.subnetsController.GetAddressesInSubnet(s.ID)
+		if err != nil && apiErrorCode(err) != 404 {
+			return err
+		}
+		for _, a := range list {
+			if err := e.add("phpipam_address", a.ID, "address_"+a.IPAddress); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Without a filter, every VLAN and L2 domain is exported. Otherwise, only
+	// those that the exported subnets are in are.
+	filtered := opts.Section != "" || opts.Subnet != ""
+	vlanList, err := e.client.vlansController.ListVLANs()
+	if err != nil && apiErrorCode(err) != 404 {
+		return err
+	}
+	domainIDs := make(map[int]bool)
+	for _, v := range vlanList {
+		if filtered && !vlanIDs[v.ID] {
+			continue
+		}
+		if err := e.add("phpipam_vlan", v.ID, v.Name); err != nil {
+			return err
+		}
+		domainIDs[v.DomainID] = true
+	}
+	domainList, err := e.client.l2domainsController.ListL2Domains()
+	if err != nil && apiErrorCode(err) != 404 {
+		return err
+	}
+	for _, l := range domainList {
+		// The default L2 domain is built into PHPIPAM, and cannot be managed.
+		if l.ID == defaultL2DomainID || (filtered && !domainIDs[l.ID]) {
+			continue
+		}
+		if err := e.add("phpipam_l2domain", l.ID, l.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportRootSubnet returns the subnet with the ID or CIDR in id, which must
+// identify a single subnet.
+func (e *exporter) exportRootSubnet(id string) (subnets.Subnet, error) {
+	c := e.client.subnetsController
+	if n, err := strconv.Atoi(id); err == nil {
+		return c.GetSubnetByID(n)
+	}
+	out, err := c.GetSubnetsByCIDR(id)
+	if err != nil && apiErrorCode(err) != 404 {
+		return subnets.Subnet{}, err
+	}
+	if len(out) != 1 {
+		return subnets.Subnet{}, fmt.Errorf("Cannot export subnet %q: %d subnets found with this CIDR. Use the subnet ID instead", id, len(out))
+	}
+	return out[0], nil
+}
+
+// add reads the object with the supplied ID through the Read function of its
+// resource, and adds it to the export under a block name derived from name.
+// Objects that cannot be found anymore are skipped.
+func (e *exporter) add(resource string, id int, name string) error {
+	r := Provider().ResourcesMap[resource]
+	state, diags := r.RefreshWithoutUpgrade(context.Background(), &terraform.InstanceState{ID: strconv.Itoa(id)}, e.client)
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			return fmt.Errorf("Cannot export %s %d: %s", resource, id, d.Summary)
+		}
+	}
+	if state == nil || state.ID == "" {
+		return nil
+	}
+
+	if e.names[resource] == nil {
+		e.names[resource] = make(map[string]bool)
+		e.addresses[resource] = make(map[int]string)
+	}
+	base := exportBlockName(name, resource)
+	name = base
+	for n := 2; e.names[resource][name]; n++ {
+		name = fmt.Sprintf("%s_%d", base, n)
+	}
+	e.names[resource][name] = true
+	e.addresses[resource][id] = resource + "." + name
+	e.objects[resource] = append(e.objects[resource], &exportObject{
+		resource: resource,
+		id:       id,
+		name:     name,
+		data:     r.Data(state),
+	})
+	return nil
+}
+
+// render returns the configuration and import blocks for the exported
+// objects of the supplied resource type, formatted like terraform fmt does.
+func (e *exporter) render(resource string) []byte {
+	s := Provider().ResourcesMap[resource].Schema
+	var keys []string
+	for k, v := range s {
+		if (v.Optional || v.Required) && !exportSkipFields.Has(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	f := hclwrite.NewEmptyFile()
+	root := f.Body()
+	for _, o := range e.objects[resource] {
+		root.AppendNewline()
+		body := root.AppendNewBlock("resource", []string{resource, o.name}).Body()
+		var customFields map[string]interface{}
+		written := make(map[string]bool)
+		for _, k := range keys {
+			v, ok := o.data.GetOk(k)
+			if !ok || exportConflicts(s[k], written) {
+				continue
+			}
+			written[k] = true
+			if k == "custom_fields" {
+				customFields = v.(map[string]interface{})
+				continue
+			}
+			if target, ok := exportReferences[resource][k]; ok {
+				if ref, ok := e.addresses[target][v.(int)]; ok {
+					body.SetAttributeTraversal(k, exportTraversal(ref, exportIDFields[target]))
+					continue
+				}
+			}
+			switch s[k].Type {
+			case schema.TypeString:
+				body.SetAttributeValue(k, cty.StringVal(v.(string)))
+			case schema.TypeInt:
+				body.SetAttributeValue(k, cty.NumberIntVal(int64(v.(int))))
+			case schema.TypeBool:
+				body.SetAttributeValue(k, cty.BoolVal(v.(bool)))
+			}
+		}
+		if len(customFields) > 0 {
+			fields := make(map[string]cty.Value, len(customFields))
+			for k, v := range customFields {
+				fields[k] = cty.StringVal(fmt.Sprint(v))
+			}
+			if len(written) > 1 {
+				body.AppendNewline()
+			}
+			body.SetAttributeValue("custom_fields", cty.MapVal(fields))
+		}
+
+		root.AppendNewline()
+		imp := root.AppendNewBlock("import", nil).Body()
+		imp.SetAttributeTraversal("to", exportTraversal(resource+"."+o.name))
+		imp.SetAttributeValue("id", cty.StringVal(strconv.Itoa(o.id)))
+	}
+	return append([]byte("# Generated by the phpipam provider's export mode.\n"), hclwrite.Format(f.Bytes())...)
+}
+
+// exportConflicts returns true if a field that conflicts with the field with
+// schema s has been written already, such as state_tag_id with state_tag.
+func exportConflicts(s *schema.Schema, written map[string]bool) bool {
+	for _, k := range s.ConflictsWith {
+		if written[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// exportTraversal returns the reference to the resource block at addr, such
+// as phpipam_section.customers, followed by the supplied attributes.
+func exportTraversal(addr string, attrs ...string) hcl.Traversal {
+	parts := append(strings.Split(addr, "."), attrs...)
+	out := hcl.Traversal{hcl.TraverseRoot{Name: parts[0]}}
+	for _, p := range parts[1:] {
+		out = append(out, hcl.TraverseAttr{Name: p})
+	}
+	return out
+}
+
+// exportBlockName returns name as a valid resource block name, which only
+// contains lower case letters, digits, underscores and dashes, and starts
+// with a letter or an underscore. The name of the resource type without the
+// phpipam_ prefix is used for empty names.
+func exportBlockName(name, resource string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '_', c == '-':
+			b.WriteRune(c)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	out := strings.Trim(b.String(), "_")
+	kind := strings.TrimPrefix(resource, "phpipam_")
+	switch {
+	case out == "":
+		return kind
+	case out[0] >= '0' && out[0] <= '9', out[0] == '-':
+		return kind + "_" + out
+	}
+	return out
+}
@@ -0,0 +1,301 @@
+package phpipam
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// testExportBlock is a resource block read back from generated configuration,
+// with the ID from its import block.
+type testExportBlock struct {
+	resource string
+	name     string
+	id       string
+	body     *hclsyntax.Body
+}
+
+func TestExportConfiguration(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	domain := testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name":        "tf-export",
+		"description": "Exported L2 domain",
+	})
+	domainID, _ := strconv.Atoi(domain.ID)
+	vlan := testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+		"name":         "tf-export",
+		"number":       1234,
+		"l2_domain_id": domainID,
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestVLANs": "exported",
+		},
+	})
+	vlanID, _ := strconv.Atoi(vlan.ID)
+	section := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name":        "tf-export",
+		"description": "Exported \"section\" with ${literal} %{sequences}\nand a newline",
+	})
+	sectionID, _ := strconv.Atoi(section.ID)
+	parent := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": "10.10.0.0",
+		"subnet_mask":    16,
+		"vlan_id":        vlanID,
+	})
+	parentID, _ := strconv.Atoi(parent.ID)
+	child := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":       sectionID,
+		"master_subnet_id": parentID,
+		"subnet_address":   "10.10.1.0",
+		"subnet_mask":      24,
+		"description":      "Exported subnet",
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestSubnets": "exported",
+		},
+	})
+	childID, _ := strconv.Atoi(child.ID)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  childID,
+		"ip_address": "10.10.1.5",
+		"hostname":   "exported.example.com",
+		"state_tag":  "Used",
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestAddresses": "exported",
+		},
+	})
+	other := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name": "tf-export-other",
+	})
+	otherID, _ := strconv.Atoi(other.ID)
+	testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     otherID,
+		"subnet_address": "10.20.0.0",
+		"subnet_mask":    24,
+	})
+
+	// Everything is exported without a filter, and every object produces an
+	// empty plan once imported.
+	dir := t.TempDir()
+	if err := exportConfiguration(meta, ExportOptions{Dir: dir}); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	blocks := testExportParse(t, dir)
+	testExportCheckPlans(t, meta, blocks)
+	testExportCheckBlocks(t, blocks, map[string]string{
+		"phpipam_l2domain.tf-export":         domain.ID,
+		"phpipam_vlan.tf-export":             vlan.ID,
+		"phpipam_section.tf-export":          section.ID,
+		"phpipam_section.tf-export-other":    other.ID,
+		"phpipam_section.customers":          "1",
+		"phpipam_subnet.subnet_10_10_0_0_16": parent.ID,
+		"phpipam_subnet.subnet_10_10_1_0_24": child.ID,
+		"phpipam_subnet.subnet_10_20_0_0_24": "",
+		"phpipam_address.address_10_10_1_5":  "",
+		"phpipam_l2domain.default":           "-",
+	})
+	subnets, _ := os.ReadFile(filepath.Join(dir, "subnets.tf"))
+	for _, s := range []string{
+		"section_id       = phpipam_section.tf-export.section_id",
+		"master_subnet_id = phpipam_subnet.subnet_10_10_0_0_16.subnet_id",
+		"vlan_id        = phpipam_vlan.tf-export.vlan_id",
+		"custom_CustomTestSubnets = \"exported\"",
+	} {
+		if !strings.Contains(string(subnets), s) {
+			t.Errorf("expected subnets.tf to contain %q, got:\n%s", s, subnets)
+		}
+	}
+	addresses, _ := os.ReadFile(filepath.Join(dir, "addresses.tf"))
+	if !strings.Contains(string(addresses), `state_tag  = "Used"`) || strings.Contains(string(addresses), "state_tag_id") {
+		t.Errorf("expected addresses.tf to set state_tag only, got:\n%s", addresses)
+	}
+
+	// A section filter exports the section, its subnets and addresses, and the
+	// VLANs and L2 domains that they are in.
+	dir = t.TempDir()
+	if err := exportConfiguration(meta, ExportOptions{Dir: dir, Section: "tf-export-other"}); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	blocks = testExportParse(t, dir)
+	testExportCheckPlans(t, meta, blocks)
+	if len(blocks) != 2 {
+		t.Fatalf("expected 2 exported objects with a section filter, got %d", len(blocks))
+	}
+	testExportCheckBlocks(t, blocks, map[string]string{
+		"phpipam_section.tf-export-other":    other.ID,
+		"phpipam_subnet.subnet_10_20_0_0_24": "",
+	})
+
+	// A subnet filter exports the subnet tree, and refers to the section by
+	// its ID.
+	dir = t.TempDir()
+	if err := exportConfiguration(meta, ExportOptions{Dir: dir, Subnet: "10.10.0.0/16"}); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	blocks = testExportParse(t, dir)
+	testExportCheckPlans(t, meta, blocks)
+	if len(blocks) != 5 {
+		t.Fatalf("expected 5 exported objects with a subnet filter, got %d", len(blocks))
+	}
+	testExportCheckBlocks(t, blocks, map[string]string{
+		"phpipam_l2domain.tf-export":         domain.ID,
+		"phpipam_vlan.tf-export":             vlan.ID,
+		"phpipam_subnet.subnet_10_10_0_0_16": parent.ID,
+		"phpipam_subnet.subnet_10_10_1_0_24": child.ID,
+		"phpipam_address.address_10_10_1_5":  "",
+	})
+	subnets, _ = os.ReadFile(filepath.Join(dir, "subnets.tf"))
+	if !strings.Contains(string(subnets), "section_id       = "+section.ID+"\n") {
+		t.Errorf("expected the section to be referred to by ID, got:\n%s", subnets)
+	}
+
+	for _, opts := range []ExportOptions{
+		{Dir: t.TempDir(), Section: "tf-export", Subnet: "10.10.0.0/16"},
+		{Dir: t.TempDir(), Section: "tf-export-missing"},
+		{Dir: t.TempDir(), Subnet: "10.99.0.0/16"},
+	} {
+		if err := exportConfiguration(meta, opts); err == nil {
+			t.Errorf("expected error exporting %#v", opts)
+		}
+	}
+}
+
+// testExportParse parses the configuration generated in dir, and returns the
+// resource blocks in it.
+func testExportParse(t *testing.T, dir string) []testExportBlock {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	var blocks []testExportBlock
+	ids := make(map[string]string)
+	for _, f := range files {
+		src, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		file, diags := hclsyntax.ParseConfig(src, f, hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("%s: %s\n%s", filepath.Base(f), diags, src)
+		}
+		for _, b := range file.Body.(*hclsyntax.Body).Blocks {
+			switch b.Type {
+			case "resource":
+				blocks = append(blocks, testExportBlock{resource: b.Labels[0], name: b.Labels[1], body: b.Body})
+			case "import":
+				to := b.Body.Attributes["to"].Expr.(*hclsyntax.ScopeTraversalExpr).Traversal
+				id, _ := b.Body.Attributes["id"].Expr.Value(nil)
+				ids[to.RootName()+"."+to[1].(hcl.TraverseAttr).Name] = id.AsString()
+			}
+		}
+	}
+	for i, b := range blocks {
+		id, ok := ids[b.resource+"."+b.name]
+		if !ok {
+			t.Fatalf("no import block for %s.%s", b.resource, b.name)
+		}
+		blocks[i].id = id
+	}
+	return blocks
+}
+
+// testExportCheckBlocks checks that blocks contains the expected resource
+// blocks, with the supplied IDs. An empty ID matches any ID, and an ID of -
+// expects no such block.
+func testExportCheckBlocks(t *testing.T, blocks []testExportBlock, expected map[string]string) {
+	t.Helper()
+	found := make(map[string]string)
+	for _, b := range blocks {
+		found[b.resource+"."+b.name] = b.id
+	}
+	for k, v := range expected {
+		id, ok := found[k]
+		switch {
+		case v == "-" && ok:
+			t.Errorf("expected no block for %s", k)
+		case v != "-" && !ok:
+			t.Errorf("expected a block for %s", k)
+		case v != "-" && v != "" && id != v:
+			t.Errorf("expected %s to import ID %s, got %s", k, v, id)
+		}
+	}
+}
+
+// testExportCheckPlans imports every block, and checks that planning the
+// block's configuration against the imported state produces no changes.
+func testExportCheckPlans(t *testing.T, meta interface{}, blocks []testExportBlock) {
+	t.Helper()
+	// References evaluate to the ID fields of the imported objects.
+	vars := make(map[string]map[string]cty.Value)
+	for _, b := range blocks {
+		if vars[b.resource] == nil {
+			vars[b.resource] = make(map[string]cty.Value)
+		}
+		id, _ := strconv.Atoi(b.id)
+		vars[b.resource][b.name] = cty.ObjectVal(map[string]cty.Value{
+			exportIDFields[b.resource]: cty.NumberIntVal(int64(id)),
+		})
+	}
+	ctx := &hcl.EvalContext{Variables: make(map[string]cty.Value)}
+	for k, v := range vars {
+		ctx.Variables[k] = cty.ObjectVal(v)
+	}
+
+	for _, b := range blocks {
+		raw := make(map[string]interface{})
+		for k, attr := range b.body.Attributes {
+			v, diags := attr.Expr.Value(ctx)
+			if diags.HasErrors() {
+				t.Fatalf("%s.%s: %s", b.resource, b.name, diags)
+			}
+			raw[k] = testExportValue(v)
+		}
+		state := testFakeImport(t, meta, b.resource, b.id)
+		r := Provider().ResourcesMap[b.resource]
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), meta)
+		if err != nil {
+			t.Fatalf("%s.%s: %s", b.resource, b.name, err)
+		}
+		if diff == nil {
+			continue
+		}
+		// Meta-parameters are not part of the imported state, and are set to
+		// their defaults by the first apply, as with any import.
+		for k := range diff.Attributes {
+			if exportSkipFields.Has(k) {
+				delete(diff.Attributes, k)
+			}
+		}
+		if !diff.Empty() {
+			t.Errorf("%s.%s: expected empty plan after import, got %#v", b.resource, b.name, diff.Attributes)
+		}
+	}
+}
+
+// testExportValue converts a value of generated configuration to the raw
+// form used by terraform.NewResourceConfigRaw.
+func testExportValue(v cty.Value) interface{} {
+	switch {
+	case v.Type() == cty.String:
+		return v.AsString()
+	case v.Type() == cty.Number:
+		n, _ := v.AsBigFloat().Int64()
+		return int(n)
+	case v.Type() == cty.Bool:
+		return v.True()
+	}
+	m := make(map[string]interface{})
+	for k, e := range v.AsValueMap() {
+		m[k] = testExportValue(e)
+	}
+	return m
+}
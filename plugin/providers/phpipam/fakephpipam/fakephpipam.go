@@ -0,0 +1,1164 @@
+// Package fakephpipam provides an in-process fake of the PHPIPAM API, for use
+// in tests that need to exercise the provider without a live PHPIPAM server.
+//
+// The fake keeps all objects in memory and implements the subset of the API
+// used by the provider: the user (token) controller, sections, subnets,
+// addresses, VLANs, L2 domains, VRFs and devices, the tools controllers for
+// locations, nameservers and tags, and the custom_fields method of each
+// controller. Responses use the same {code, success, data, message} envelope
+// as PHPIPAM, and error messages mirror the ones PHPIPAM returns, so that
+// code matching on API errors behaves the same against the fake.
+//
+// Like PHPIPAM with the api-stringify-results header set, all scalar values
+// are stored and returned as strings.
+package fakephpipam
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AppID is the application ID that the fake server accepts.
+	AppID = "terraform"
+
+	// Username is the user name that the fake server accepts.
+	Username = "admin"
+
+	// Password is the password that the fake server accepts.
+	Password = "ipamadmin"
+)
+
+// timeLayout represents the datetime format used by the PHPIPAM API.
+const timeLayout = "2006-01-02 15:04:05"
+
+// object is a single PHPIPAM object, keyed by its API field names.
+type object map[string]interface{}
+
+// controller describes a PHPIPAM API controller that is backed by the generic
+// object store.
+type controller struct {
+	// The name used in response messages, such as "Section created".
+	name string
+
+	// The message returned when an object cannot be found by its ID.
+	notFound string
+
+	// The fields that must be set when creating an object.
+	required []string
+}
+
+// controllers lists the controllers implemented by the fake server, keyed by
+// their path in the API.
+var controllers = map[string]controller{
+	"sections":          {name: "Section", notFound: "Section does not exist", required: []string{"name"}},
+	"subnets":           {name: "Subnet", notFound: "Subnet does not exist", required: []string{"subnet", "mask", "sectionId"}},
+	"addresses":         {name: "Address", notFound: "Address not found", required: []string{"ip", "subnetId"}},
+	"vlans":             {name: "Vlan", notFound: "Vlan not found", required: []string{"name", "number"}},
+	"l2domains":         {name: "L2 domain", notFound: "Invalid domain id", required: []string{"name"}},
+	"vrf":               {name: "Vrf", notFound: "Vrf not found", required: []string{"name"}},
+	"devices":           {name: "Device", notFound: "Device not found", required: []string{"hostname"}},
+	"tools/locations":   {name: "Location", notFound: "Location not found", required: []string{"name"}},
+	"tools/nameservers": {name: "Nameserver", notFound: "Nameserver not found", required: []string{"name"}},
+	"tools/tags":        {name: "Tag", notFound: "Tag not found", required: []string{"type"}},
+}
+
+// CustomField describes a custom field definition on the fake server.
+type CustomField struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Comment string `json:"Comment"`
+	Null    string `json:"Null"`
+	Default string `json:"Default"`
+}
+
+// Server is a fake PHPIPAM API server. The API is served under
+// URL + "/" + AppID, like it would be on a real PHPIPAM install.
+type Server struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	token        string
+	issued       map[string]bool
+	nextID       map[string]int
+	objects      map[string]map[int]object
+	customFields map[string]map[string]CustomField
+	failures     []Failure
+	hook         func(r *http.Request)
+}
+
+// Failure describes an error returned by the server for a request, in place
+// of its normal response.
+type Failure struct {
+	// The HTTP status code of the response.
+	Code int
+
+	// The error message. If empty, the response is a plain text error page,
+	// like the ones returned by a reverse proxy, instead of a JSON API error.
+	Message string
+
+	// Whether the request is processed as usual before the error is returned,
+	// like when a proxy times out waiting for the response.
+	Applied bool
+}
+
+// NewServer starts and returns a new fake PHPIPAM server. The server is
+// seeded with the same default objects as a fresh PHPIPAM install: the
+// "Customers" and "IPv6" sections, the "default" L2 domain, and the built-in
+// IP address tags. The caller should call Close when finished, to shut it
+// down.
+func NewServer() *Server {
+	s := &Server{
+		issued:       make(map[string]bool),
+		nextID:       make(map[string]int),
+		objects:      make(map[string]map[int]object),
+		customFields: make(map[string]map[string]CustomField),
+	}
+	for k := range controllers {
+		s.objects[k] = make(map[int]object)
+	}
+	s.seed()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Endpoint returns the API endpoint of the server, suitable for the endpoint
+// provider option or the PHPIPAM_ENDPOINT_ADDR environment variable.
+func (s *Server) Endpoint() string {
+	return s.URL
+}
+
+// AddCustomField adds a custom field definition to the supplied controller,
+// such as "addresses" or "subnets". Custom field names should include the
+// custom_ prefix.
+func (s *Server) AddCustomField(controller, name, fieldType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.customFields[controller] == nil {
+		s.customFields[controller] = make(map[string]CustomField)
+	}
+	s.customFields[controller][name] = CustomField{
+		Name: name,
+		Type: fieldType,
+		Null: "YES",
+	}
+}
+
+// ExpireToken expires the current session token, so that the next request
+// made with it fails with "Token expired".
+func (s *Server) ExpireToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = "expired"
+}
+
+// FailNext makes the next requests, other than logins, fail with the
+// supplied failures, one request per failure.
+func (s *Server) FailNext(failures ...Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, failures...)
+}
+
+// OnRequest sets a function that is called before every request, other than
+// logins, is handled. The function can change the server's objects, for
+// example with Insert to simulate a concurrent client.
+func (s *Server) OnRequest(f func(r *http.Request)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hook = f
+}
+
+// Insert adds an object to a controller, such as "addresses" or "subnets",
+// without any of the validation done on create, and returns its ID. This
+// simulates objects that PHPIPAM lets through when concurrent clients race
+// each other. If fields has an "id", the object is stored under that ID.
+func (s *Server) Insert(controller string, fields map[string]interface{}) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := stringify(object(fields))
+	o["editDate"] = time.Now().Format(timeLayout)
+	if id, err := strconv.Atoi(toString(o["id"])); err == nil {
+		s.objects[controller][id] = o
+		if id > s.nextID[controller] {
+			s.nextID[controller] = id
+		}
+		return id
+	}
+	return s.insert(controller, o)
+}
+
+// seed creates the default objects of a fresh PHPIPAM install.
+func (s *Server) seed() {
+	s.insert("sections", object{"name": "Customers", "description": "Section for customers", "strictMode": "1", "subnetOrdering": "default", "showVLAN": "0", "showVRF": "0"})
+	s.insert("sections", object{"name": "IPv6", "description": "Section for IPv6 addresses", "strictMode": "1", "subnetOrdering": "default", "showVLAN": "0", "showVRF": "0"})
+	s.insert("l2domains", object{"name": "default", "description": "Default L2 domain", "sections": nil})
+	s.insert("tools/tags", object{"type": "Offline", "showtag": "1", "bgcolor": "#f59c99", "fgcolor": "#ffffff", "compress": "No", "locked": "Yes", "updateTag": "1"})
+	s.insert("tools/tags", object{"type": "Used", "showtag": "0", "bgcolor": "#a9c9a4", "fgcolor": "#ffffff", "compress": "No", "locked": "Yes", "updateTag": "1"})
+	s.insert("tools/tags", object{"type": "Reserved", "showtag": "1", "bgcolor": "#9ac0cd", "fgcolor": "#ffffff", "compress": "No", "locked": "Yes", "updateTag": "1"})
+	s.insert("tools/tags", object{"type": "DHCP", "showtag": "1", "bgcolor": "#c9c9c9", "fgcolor": "#ffffff", "compress": "Yes", "locked": "Yes", "updateTag": "1"})
+}
+
+// insert adds an object to the store and returns its new ID.
+func (s *Server) insert(c string, o object) int {
+	s.nextID[c]++
+	id := s.nextID[c]
+	o["id"] = strconv.Itoa(id)
+	s.objects[c][id] = o
+	return id
+}
+
+// response is the PHPIPAM API response envelope.
+type response struct {
+	Code    int         `json:"code"`
+	Success bool        `json:"success"`
+	Data    interface{} `json:"data,omitempty"`
+	Message string      `json:"message,omitempty"`
+	ID      string      `json:"id,omitempty"`
+}
+
+// apiError is an error that is returned to the client with the supplied
+// status code.
+type apiError struct {
+	code    int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func errorf(code int, format string, a ...interface{}) error {
+	return &apiError{code: code, message: fmt.Sprintf(format, a...)}
+}
+
+func writeResponse(w http.ResponseWriter, r response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(r.Code)
+	json.NewEncoder(w).Encode(r)
+}
+
+// handle is the entry point for all requests to the fake server.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	hook := s.hook
+	s.mu.Unlock()
+	if hook != nil && !strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/user") {
+		hook(r)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := "/" + AppID + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		writeResponse(w, response{Code: http.StatusBadRequest, Message: "Invalid application id"})
+		return
+	}
+	// The path is split before it is unescaped, so that escaped slashes are
+	// kept within their path segment.
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.EscapedPath(), prefix), "/"), "/")
+	for i, p := range parts {
+		parts[i], _ = url.PathUnescape(p)
+	}
+
+	var body object
+	if r.Body != nil && r.Method != "GET" {
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		if err := dec.Decode(&body); err != nil && err.Error() != "EOF" {
+			writeResponse(w, response{Code: http.StatusBadRequest, Message: fmt.Sprintf("Invalid JSON: %s", err)})
+			return
+		}
+	}
+	body = stringify(body)
+
+	var failure *Failure
+	if parts[0] != "user" && len(s.failures) > 0 {
+		failure = &s.failures[0]
+		s.failures = s.failures[1:]
+	}
+
+	var resp response
+	var err error
+	if parts[0] == "user" {
+		resp, err = s.handleUser(r)
+	} else if failure != nil && !failure.Applied {
+		// The request is not processed.
+	} else if err = s.authenticate(r); err == nil {
+		resp, err = s.route(r, parts, body)
+	}
+	if failure != nil {
+		if failure.Message == "" {
+			w.Header().Set("Content-Type", "text/html")
+			w.WriteHeader(failure.Code)
+			fmt.Fprintf(w, "<html><body><h1>%d %s</h1></body></html>", failure.Code, http.StatusText(failure.Code))
+			return
+		}
+		err = errorf(failure.Code, "%s", failure.Message)
+	}
+	if err != nil {
+		code := http.StatusInternalServerError
+		if e, ok := err.(*apiError); ok {
+			code = e.code
+		}
+		resp = response{Code: code, Message: err.Error()}
+	} else {
+		resp.Success = true
+	}
+	writeResponse(w, resp)
+}
+
+// handleUser implements the user controller, which hands out session tokens.
+func (s *Server) handleUser(r *http.Request) (response, error) {
+	if r.Method != "POST" {
+		return response{}, errorf(http.StatusBadRequest, "Invalid request")
+	}
+	// The SDK re-uses the session token header when it refreshes an expired
+	// token, so accept any token that was previously issued as well as basic
+	// auth.
+	user, pass, ok := r.BasicAuth()
+	if !s.issued[r.Header.Get("phpipam-token")] && (!ok || user != Username || pass != Password) {
+		return response{}, errorf(http.StatusInternalServerError, "Invalid username or password")
+	}
+	s.token = fmt.Sprintf("token%d", len(s.issued)+1)
+	s.issued[s.token] = true
+	return response{
+		Code: http.StatusOK,
+		Data: map[string]string{
+			"token":   s.token,
+			"expires": time.Now().Add(6 * time.Hour).Format(timeLayout),
+		},
+	}, nil
+}
+
+// authenticate checks the session token supplied with a request.
+func (s *Server) authenticate(r *http.Request) error {
+	token := r.Header.Get("phpipam-token")
+	switch {
+	case token == "":
+		return errorf(http.StatusForbidden, "Please provide token")
+	case token != s.token && s.token == "expired":
+		return errorf(http.StatusForbidden, "Token expired")
+	case token != s.token:
+		return errorf(http.StatusForbidden, "Invalid token")
+	}
+	return nil
+}
+
+// route dispatches an authenticated request to the handler for its
+// controller.
+func (s *Server) route(r *http.Request, parts []string, body object) (response, error) {
+	c := parts[0]
+	rest := parts[1:]
+	if c == "tools" && len(parts) > 1 {
+		c = "tools/" + parts[1]
+		rest = parts[2:]
+	}
+	if _, ok := controllers[c]; !ok {
+		return response{}, errorf(http.StatusBadRequest, "Invalid controller")
+	}
+
+	if len(rest) == 1 && rest[0] == "custom_fields" && r.Method == "GET" {
+		return s.getCustomFields(c)
+	}
+
+	if resp, ok, err := s.routeSpecial(r, c, rest, body); ok {
+		return resp, err
+	}
+
+	switch r.Method {
+	case "GET":
+		switch len(rest) {
+		case 0:
+			return filterResponse(r, s.list(c, nil))
+		case 1:
+			o, err := s.get(c, rest[0])
+			return response{Code: http.StatusOK, Data: o}, err
+		}
+	case "POST":
+		if len(rest) == 0 {
+			return s.create(c, body)
+		}
+	case "PATCH":
+		switch len(rest) {
+		case 0:
+			return s.update(c, toString(body["id"]), body)
+		case 1:
+			return s.update(c, rest[0], body)
+		}
+	case "DELETE":
+		if len(rest) == 1 {
+			return s.delete(c, rest[0])
+		}
+	}
+	return response{}, errorf(http.StatusBadRequest, "Invalid request")
+}
+
+// routeSpecial handles the controller-specific API methods. ok is false if
+// the request is not one of them, in which case it should be handled by the
+// generic handlers.
+func (s *Server) routeSpecial(r *http.Request, c string, rest []string, body object) (resp response, ok bool, err error) {
+	m := r.Method
+	switch {
+	// GET /sections/{name}/
+	case c == "sections" && m == "GET" && len(rest) == 1 && !isNumeric(rest[0]):
+		for _, o := range s.list("sections", nil) {
+			if o["name"] == rest[0] {
+				return response{Code: http.StatusOK, Data: o}, true, nil
+			}
+		}
+		return resp, true, errorf(http.StatusNotFound, "Not Found")
+
+	// GET /sections/{id}/subnets/
+	case c == "sections" && m == "GET" && len(rest) == 2 && rest[1] == "subnets":
+		list := s.list("subnets", func(o object) bool { return o["sectionId"] == rest[0] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No subnets found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /subnets/cidr/{address}/{mask}/
+	case c == "subnets" && m == "GET" && len(rest) == 3 && rest[0] == "cidr":
+		list := s.list("subnets", func(o object) bool { return o["subnet"] == rest[1] && o["mask"] == rest[2] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No subnets found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /subnets/{id}/addresses/
+	case c == "subnets" && m == "GET" && len(rest) == 2 && rest[1] == "addresses":
+		if _, err := s.get("subnets", rest[0]); err != nil {
+			return resp, true, err
+		}
+		list := s.list("addresses", func(o object) bool { return o["subnetId"] == rest[0] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No addresses found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /subnets/{id}/slaves/
+	case c == "subnets" && m == "GET" && len(rest) == 2 && rest[1] == "slaves":
+		if _, err := s.get("subnets", rest[0]); err != nil {
+			return resp, true, err
+		}
+		list := s.list("subnets", func(o object) bool { return o["masterSubnetId"] == rest[0] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No slaves")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /subnets/{id}/slaves_recursive/
+	case c == "subnets" && m == "GET" && len(rest) == 2 && rest[1] == "slaves_recursive":
+		if _, err := s.get("subnets", rest[0]); err != nil {
+			return resp, true, err
+		}
+		list := s.descendants(rest[0])
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No slaves")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /subnets/{id}/usage/
+	case c == "subnets" && m == "GET" && len(rest) == 2 && rest[1] == "usage":
+		resp, err = s.subnetUsage(rest[0])
+		return resp, true, err
+
+	// PATCH /subnets/{id}/resize/
+	case c == "subnets" && m == "PATCH" && len(rest) == 2 && rest[1] == "resize":
+		resp, err = s.resizeSubnet(rest[0], toString(body["mask"]))
+		return resp, true, err
+
+	// PATCH /subnets/{id}/split/
+	case c == "subnets" && m == "PATCH" && len(rest) == 2 && rest[1] == "split":
+		resp, err = s.splitSubnet(rest[0], toString(body["number"]))
+		return resp, true, err
+
+	// GET /subnets/{id}/first_free/
+	case c == "subnets" && m == "GET" && len(rest) == 2 && rest[1] == "first_free":
+		ip, err := s.firstFreeAddress(rest[0])
+		return response{Code: http.StatusOK, Data: ip}, true, err
+
+	// GET and POST /subnets/{id}/first_subnet/{mask}/
+	case c == "subnets" && len(rest) == 3 && rest[1] == "first_subnet":
+		prefix, err := s.firstFreeSubnet(rest[0], rest[2])
+		if err != nil {
+			return resp, true, err
+		}
+		switch m {
+		case "GET":
+			return response{Code: http.StatusOK, Data: prefix.String()}, true, nil
+		case "POST":
+			parent, _ := s.get("subnets", rest[0])
+			body["subnet"] = prefix.Addr().String()
+			body["mask"] = strconv.Itoa(prefix.Bits())
+			body["masterSubnetId"] = rest[0]
+			if _, ok := body["sectionId"]; !ok {
+				body["sectionId"] = parent["sectionId"]
+			}
+			resp, err = s.create("subnets", body)
+			resp.Data = prefix.String()
+			return resp, true, err
+		}
+
+	// GET /addresses/search/{ip}/
+	case c == "addresses" && m == "GET" && len(rest) == 2 && rest[0] == "search":
+		list := s.list("addresses", func(o object) bool { return o["ip"] == rest[1] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "Address not found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /addresses/search_hostname/{hostname}/
+	case c == "addresses" && m == "GET" && len(rest) == 2 && rest[0] == "search_hostname":
+		list := s.list("addresses", func(o object) bool { return o["hostname"] == rest[1] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "Address not found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /addresses/search_mac/{mac}/
+	case c == "addresses" && m == "GET" && len(rest) == 2 && rest[0] == "search_mac":
+		// PHPIPAM reformats both the query and the stored addresses, so MAC
+		// addresses match in any notation.
+		list := s.list("addresses", func(o object) bool {
+			return o["mac"] != nil && macDigits(toString(o["mac"])) == macDigits(rest[1])
+		})
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "Address not found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /addresses/{ip}/{subnetId}/
+	case c == "addresses" && m == "GET" && len(rest) == 2 && !isNumeric(rest[0]):
+		list := s.list("addresses", func(o object) bool { return o["ip"] == rest[0] && o["subnetId"] == rest[1] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "Address not found")
+		}
+		return response{Code: http.StatusOK, Data: list[0]}, true, nil
+
+	// POST /addresses/first_free/{subnetId}/
+	case c == "addresses" && m == "POST" && len(rest) == 2 && rest[0] == "first_free":
+		ip, err := s.firstFreeAddress(rest[1])
+		if err != nil {
+			return resp, true, err
+		}
+		body["ip"] = ip
+		body["subnetId"] = rest[1]
+		resp, err = s.create("addresses", body)
+		resp.Data = ip
+		return resp, true, err
+
+	// GET /vlans/search/{number}/
+	case c == "vlans" && m == "GET" && len(rest) == 2 && rest[0] == "search":
+		list := s.list("vlans", func(o object) bool { return o["number"] == rest[1] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "Vlans not found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /l2domains/{id}/vlans/
+	case c == "l2domains" && m == "GET" && len(rest) == 2 && rest[1] == "vlans":
+		list := s.list("vlans", func(o object) bool { return o["domainId"] == rest[0] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No vlans found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /vrf/{id}/subnets/
+	case c == "vrf" && m == "GET" && len(rest) == 2 && rest[1] == "subnets":
+		list := s.list("subnets", func(o object) bool { return o["vrfId"] == rest[0] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No subnets found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+
+	// GET /devices/{id}/addresses/
+	case c == "devices" && m == "GET" && len(rest) == 2 && rest[1] == "addresses":
+		list := s.list("addresses", func(o object) bool { return o["deviceId"] == rest[0] })
+		if len(list) == 0 {
+			return resp, true, errorf(http.StatusNotFound, "No addresses found")
+		}
+		resp, err = filterResponse(r, list)
+		return resp, true, err
+	}
+	return resp, false, nil
+}
+
+// getCustomFields returns the custom field definitions for a controller.
+func (s *Server) getCustomFields(c string) (response, error) {
+	if len(s.customFields[c]) == 0 {
+		// PHPIPAM returns this as a failure with a 200 status code.
+		return response{}, errorf(http.StatusOK, "No custom fields defined")
+	}
+	return response{Code: http.StatusOK, Data: s.customFields[c]}, nil
+}
+
+// list returns all objects of a controller that match the supplied function,
+// ordered by ID. A nil function matches all objects.
+func (s *Server) list(c string, match func(object) bool) []object {
+	ids := make([]int, 0, len(s.objects[c]))
+	for id := range s.objects[c] {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	out := make([]object, 0, len(ids))
+	for _, id := range ids {
+		if match == nil || match(s.objects[c][id]) {
+			out = append(out, s.objects[c][id])
+		}
+	}
+	return out
+}
+
+// filterResponse applies the filter_by and filter_value query parameters to a
+// list response.
+func filterResponse(r *http.Request, list []object) (response, error) {
+	by := r.URL.Query().Get("filter_by")
+	if by == "" {
+		return response{Code: http.StatusOK, Data: list}, nil
+	}
+	value := r.URL.Query().Get("filter_value")
+	out := make([]object, 0)
+	for _, o := range list {
+		if toString(o[by]) == value {
+			out = append(out, o)
+		}
+	}
+	if len(out) == 0 {
+		return response{}, errorf(http.StatusNotFound, "No results (filter applied)")
+	}
+	return response{Code: http.StatusOK, Data: out}, nil
+}
+
+// get returns an object by its ID.
+func (s *Server) get(c, id string) (object, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "Invalid Id")
+	}
+	o, ok := s.objects[c][n]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, controllers[c].notFound)
+	}
+	return o, nil
+}
+
+// create creates an object after validating it.
+func (s *Server) create(c string, body object) (response, error) {
+	ctl := controllers[c]
+	if body["id"] != nil {
+		return response{}, errorf(http.StatusBadRequest, "Id is not allowed when creating objects")
+	}
+	for _, k := range ctl.required {
+		if toString(body[k]) == "" {
+			return response{}, errorf(http.StatusBadRequest, "%s is mandatory", k)
+		}
+	}
+	if err := s.checkCustomFields(c, body); err != nil {
+		return response{}, err
+	}
+	// Like PHPIPAM, subnets that are not nested have a master subnet ID of 0.
+	if c == "subnets" && toString(body["masterSubnetId"]) == "" {
+		body["masterSubnetId"] = "0"
+	}
+	if err := s.validate(c, "", body); err != nil {
+		return response{}, err
+	}
+	o := make(object)
+	for k, v := range body {
+		if v != nil {
+			o[k] = v
+		}
+	}
+	o["editDate"] = time.Now().Format(timeLayout)
+	id := s.insert(c, o)
+	return response{Code: http.StatusCreated, Message: fmt.Sprintf("%s created", ctl.name), ID: strconv.Itoa(id)}, nil
+}
+
+// update merges the supplied fields into an existing object. Fields set to
+// null are cleared.
+func (s *Server) update(c, id string, body object) (response, error) {
+	if id == "" {
+		return response{}, errorf(http.StatusBadRequest, "Id is required")
+	}
+	o, err := s.get(c, id)
+	if err != nil {
+		return response{}, err
+	}
+	delete(body, "id")
+	if err := s.checkCustomFields(c, body); err != nil {
+		return response{}, err
+	}
+	if err := s.validate(c, id, body); err != nil {
+		return response{}, err
+	}
+	for k, v := range body {
+		if v == nil {
+			delete(o, k)
+		} else {
+			o[k] = v
+		}
+	}
+	o["editDate"] = time.Now().Format(timeLayout)
+	return response{Code: http.StatusOK, Message: fmt.Sprintf("%s updated", controllers[c].name)}, nil
+}
+
+// delete deletes an object, along with any objects that PHPIPAM would remove
+// with it.
+func (s *Server) delete(c, id string) (response, error) {
+	o, err := s.get(c, id)
+	if err != nil {
+		return response{}, err
+	}
+	if c == "tools/tags" && o["locked"] == "Yes" {
+		return response{}, errorf(http.StatusConflict, "Cannot delete locked tag")
+	}
+	s.remove(c, id)
+	return response{Code: http.StatusOK, Message: fmt.Sprintf("%s deleted", controllers[c].name)}, nil
+}
+
+// remove removes an object from the store, cascading to sections' subnets and
+// subnets' addresses and child subnets.
+func (s *Server) remove(c, id string) {
+	switch c {
+	case "sections":
+		for _, o := range s.list("subnets", func(o object) bool { return o["sectionId"] == id }) {
+			s.remove("subnets", toString(o["id"]))
+		}
+	case "subnets":
+		for _, o := range s.list("subnets", func(o object) bool { return o["masterSubnetId"] == id }) {
+			s.remove("subnets", toString(o["id"]))
+		}
+		for _, o := range s.list("addresses", func(o object) bool { return o["subnetId"] == id }) {
+			s.remove("addresses", toString(o["id"]))
+		}
+	}
+	n, _ := strconv.Atoi(id)
+	delete(s.objects[c], n)
+}
+
+// checkCustomFields ensures that any custom_ fields in a request body are
+// defined for the controller.
+func (s *Server) checkCustomFields(c string, body object) error {
+	for k := range body {
+		if strings.HasPrefix(k, "custom_") {
+			if _, ok := s.customFields[c][k]; !ok {
+				return errorf(http.StatusBadRequest, "Invalid custom field %s", k)
+			}
+		}
+	}
+	return nil
+}
+
+// validate performs the controller-specific validation that PHPIPAM does on
+// create and update. id is empty for creates.
+func (s *Server) validate(c, id string, body object) error {
+	switch c {
+	case "sections":
+		if name, ok := body["name"]; ok {
+			for _, o := range s.list("sections", nil) {
+				if o["name"] == name && o["id"] != id {
+					return errorf(http.StatusConflict, "Section with name %s already exists", name)
+				}
+			}
+		}
+	case "subnets":
+		if id != "" {
+			return s.validateSubnetMove(id, body)
+		}
+		prefix, err := netip.ParsePrefix(fmt.Sprintf("%s/%s", toString(body["subnet"]), toString(body["mask"])))
+		if err != nil || prefix.Masked() != prefix {
+			return errorf(http.StatusBadRequest, "Invalid subnet %s/%s", toString(body["subnet"]), toString(body["mask"]))
+		}
+		if _, err := s.get("sections", toString(body["sectionId"])); err != nil {
+			return errorf(http.StatusBadRequest, "Section does not exist")
+		}
+		for _, o := range s.list("subnets", func(o object) bool {
+			return o["sectionId"] == body["sectionId"] && toString(o["masterSubnetId"]) == toString(body["masterSubnetId"])
+		}) {
+			if p, err := objectPrefix(o); err == nil && p.Overlaps(prefix) {
+				return errorf(http.StatusConflict, "Subnet overlaps with %s", p)
+			}
+		}
+	case "addresses":
+		if id != "" {
+			return nil
+		}
+		subnet, err := s.get("subnets", toString(body["subnetId"]))
+		if err != nil {
+			return errorf(http.StatusBadRequest, "Invalid subnet Id")
+		}
+		ip, err := netip.ParseAddr(toString(body["ip"]))
+		if err != nil {
+			return errorf(http.StatusBadRequest, "Invalid IP address %s", toString(body["ip"]))
+		}
+		if p, err := objectPrefix(subnet); err != nil || !p.Contains(ip) {
+			return errorf(http.StatusBadRequest, "IP address not in selected subnet")
+		}
+		if len(s.list("addresses", func(o object) bool { return o["ip"] == body["ip"] && o["subnetId"] == body["subnetId"] })) > 0 {
+			return errorf(http.StatusConflict, "IP address %s already exists", body["ip"])
+		}
+	}
+	return nil
+}
+
+// resizeSubnet changes the mask of a subnet, keeping its address. Like
+// PHPIPAM, the resized subnet must still hold all of the subnet's addresses
+// and child subnets, and must not overlap with its siblings.
+func (s *Server) resizeSubnet(id, mask string) (response, error) {
+	subnet, err := s.get("subnets", id)
+	if err != nil {
+		return response{}, err
+	}
+	old, err := objectPrefix(subnet)
+	if err != nil {
+		return response{}, errorf(http.StatusInternalServerError, "Invalid subnet")
+	}
+	bits, err := strconv.Atoi(mask)
+	if err != nil || bits < 0 || bits > old.Addr().BitLen() {
+		return response{}, errorf(http.StatusBadRequest, "Invalid mask")
+	}
+	prefix := netip.PrefixFrom(old.Addr(), bits)
+	if prefix.Masked() != prefix {
+		return response{}, errorf(http.StatusBadRequest, "New subnet not valid for mask %d", bits)
+	}
+	for _, o := range s.list("addresses", func(o object) bool { return o["subnetId"] == id }) {
+		if ip, err := netip.ParseAddr(toString(o["ip"])); err == nil && !prefix.Contains(ip) {
+			return response{}, errorf(http.StatusConflict, "Subnet does not contain IP address %s", ip)
+		}
+	}
+	for _, o := range s.list("subnets", nil) {
+		p, err := objectPrefix(o)
+		switch {
+		case err != nil || o["id"] == id:
+		case o["masterSubnetId"] == id && !prefix.Contains(p.Addr()):
+			return response{}, errorf(http.StatusConflict, "Subnet does not contain child subnet %s", p)
+		case o["sectionId"] == subnet["sectionId"] && toString(o["masterSubnetId"]) == toString(subnet["masterSubnetId"]) && p.Overlaps(prefix):
+			return response{}, errorf(http.StatusConflict, "Subnet overlaps with %s", p)
+		}
+	}
+	subnet["mask"] = strconv.Itoa(bits)
+	subnet["editDate"] = time.Now().Format(timeLayout)
+	return response{Code: http.StatusOK, Message: "Subnet resized"}, nil
+}
+
+// splitSubnet splits a subnet into number subnets of equal size. Like
+// PHPIPAM, the new subnets replace the original one, taking over its
+// addresses, and subnets that have child subnets cannot be split.
+func (s *Server) splitSubnet(id, number string) (response, error) {
+	subnet, err := s.get("subnets", id)
+	if err != nil {
+		return response{}, err
+	}
+	prefix, err := objectPrefix(subnet)
+	if err != nil {
+		return response{}, errorf(http.StatusInternalServerError, "Invalid subnet")
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 2 || n&(n-1) != 0 {
+		return response{}, errorf(http.StatusBadRequest, "Invalid number of subnets")
+	}
+	bits := prefix.Bits()
+	for i := n; i > 1; i >>= 1 {
+		bits++
+	}
+	if bits > prefix.Addr().BitLen() {
+		return response{}, errorf(http.StatusBadRequest, "Subnet too small to be split into %d subnets", n)
+	}
+	if len(s.list("subnets", func(o object) bool { return o["masterSubnetId"] == id })) > 0 {
+		return response{}, errorf(http.StatusConflict, "Subnets with child subnets cannot be split")
+	}
+
+	child := netip.PrefixFrom(prefix.Addr(), bits)
+	var children []object
+	for i := 0; i < n; i++ {
+		o := make(object)
+		for k, v := range subnet {
+			o[k] = v
+		}
+		o["subnet"] = child.Addr().String()
+		o["mask"] = strconv.Itoa(bits)
+		o["editDate"] = time.Now().Format(timeLayout)
+		s.insert("subnets", o)
+		children = append(children, o)
+		child = netip.PrefixFrom(lastAddr(child).Next(), bits)
+	}
+	for _, a := range s.list("addresses", func(o object) bool { return o["subnetId"] == id }) {
+		ip, _ := netip.ParseAddr(toString(a["ip"]))
+		for _, o := range children {
+			if p, _ := objectPrefix(o); p.Contains(ip) {
+				a["subnetId"] = o["id"]
+			}
+		}
+	}
+	n, _ = strconv.Atoi(id)
+	delete(s.objects["subnets"], n)
+	return response{Code: http.StatusOK, Message: "Subnet splitted"}, nil
+}
+
+// validateSubnetMove validates updates that move a subnet to another section
+// or master subnet. The master subnet must be in the subnet's section, and in
+// sections in strict mode, the subnet must not overlap with the other subnets
+// at its new level.
+func (s *Server) validateSubnetMove(id string, body object) error {
+	_, sectionSet := body["sectionId"]
+	_, masterSet := body["masterSubnetId"]
+	if !sectionSet && !masterSet {
+		return nil
+	}
+	subnet, err := s.get("subnets", id)
+	if err != nil {
+		return err
+	}
+	sectionID, masterID := toString(subnet["sectionId"]), toString(subnet["masterSubnetId"])
+	if sectionSet {
+		sectionID = toString(body["sectionId"])
+	}
+	if masterSet {
+		masterID = toString(body["masterSubnetId"])
+	}
+	section, err := s.get("sections", sectionID)
+	if err != nil {
+		return errorf(http.StatusBadRequest, "Section does not exist")
+	}
+	if masterID != "0" {
+		master, err := s.get("subnets", masterID)
+		if err != nil || master["sectionId"] != sectionID {
+			return errorf(http.StatusBadRequest, "Master subnet does not exist in section %s", sectionID)
+		}
+	}
+	if section["strictMode"] != "1" {
+		return nil
+	}
+	prefix, err := objectPrefix(subnet)
+	if err != nil {
+		return errorf(http.StatusInternalServerError, "Invalid subnet")
+	}
+	for _, o := range s.list("subnets", func(o object) bool {
+		return o["id"] != id && o["sectionId"] == sectionID && o["masterSubnetId"] == masterID
+	}) {
+		if p, err := objectPrefix(o); err == nil && p.Overlaps(prefix) {
+			return errorf(http.StatusConflict, "Subnet overlaps with %s", p)
+		}
+	}
+	return nil
+}
+
+// descendants returns the child subnets of a subnet, and their child subnets,
+// recursively.
+func (s *Server) descendants(id string) []object {
+	var out []object
+	for _, o := range s.list("subnets", func(o object) bool { return toString(o["masterSubnetId"]) == id }) {
+		out = append(out, o)
+		out = append(out, s.descendants(toString(o["id"]))...)
+	}
+	return out
+}
+
+// subnetUsage returns the usage of a subnet. Like PHPIPAM, the network and
+// broadcast addresses of IPv4 subnets larger than a /31 are not counted as
+// hosts.
+func (s *Server) subnetUsage(id string) (response, error) {
+	subnet, err := s.get("subnets", id)
+	if err != nil {
+		return response{}, err
+	}
+	prefix, err := objectPrefix(subnet)
+	if err != nil {
+		return response{}, errorf(http.StatusInternalServerError, "Invalid subnet")
+	}
+	used := len(s.list("addresses", func(o object) bool { return o["subnetId"] == id }))
+	max := new(big.Int).Lsh(big.NewInt(1), uint(prefix.Addr().BitLen()-prefix.Bits()))
+	if prefix.Addr().Is4() && prefix.Bits() < 31 {
+		max.Sub(max, big.NewInt(2))
+	}
+	free := new(big.Int).Sub(max, big.NewInt(int64(used)))
+	var freePercent float64
+	if max.Sign() > 0 {
+		freePercent, _ = new(big.Float).Quo(new(big.Float).SetInt(free), new(big.Float).SetInt(max)).Float64()
+		freePercent *= 100
+	}
+	return response{Code: http.StatusOK, Data: object{
+		"used":              strconv.Itoa(used),
+		"maxhosts":          max.String(),
+		"freehosts":         free.String(),
+		"freehosts_percent": strconv.FormatFloat(freePercent, 'f', -1, 64),
+		"Offline_percent":   "0",
+		"Used_percent":      strconv.FormatFloat(100-freePercent, 'f', -1, 64),
+		"Reserved_percent":  "0",
+		"DHCP_percent":      "0",
+	}}, nil
+}
+
+// firstFreeAddress returns the first unused host address in a subnet.
+func (s *Server) firstFreeAddress(id string) (string, error) {
+	subnet, err := s.get("subnets", id)
+	if err != nil {
+		return "", err
+	}
+	prefix, err := objectPrefix(subnet)
+	if err != nil {
+		return "", errorf(http.StatusInternalServerError, "Invalid subnet")
+	}
+	used := make(map[string]bool)
+	for _, o := range s.list("addresses", func(o object) bool { return o["subnetId"] == id }) {
+		used[toString(o["ip"])] = true
+	}
+	last := lastAddr(prefix)
+	ip := prefix.Addr()
+	// The network and broadcast addresses are not usable in IPv4 subnets
+	// larger than a /31.
+	skipEnds := prefix.Addr().Is4() && prefix.Bits() < 31
+	if skipEnds {
+		ip = ip.Next()
+	}
+	for i := 0; i < 65536 && ip.IsValid() && prefix.Contains(ip); i++ {
+		if skipEnds && ip == last {
+			break
+		}
+		if !used[ip.String()] {
+			return ip.String(), nil
+		}
+		ip = ip.Next()
+	}
+	return "", errorf(http.StatusNotFound, "No free addresses found")
+}
+
+// firstFreeSubnet returns the first child prefix of the supplied size that
+// does not overlap with any existing child subnet.
+func (s *Server) firstFreeSubnet(id, mask string) (netip.Prefix, error) {
+	subnet, err := s.get("subnets", id)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	parent, err := objectPrefix(subnet)
+	if err != nil {
+		return netip.Prefix{}, errorf(http.StatusInternalServerError, "Invalid subnet")
+	}
+	bits, err := strconv.Atoi(mask)
+	if err != nil || bits > parent.Addr().BitLen() {
+		return netip.Prefix{}, errorf(http.StatusBadRequest, "Invalid subnet mask")
+	}
+	// Like PHPIPAM, a mask that does not fit inside the parent just yields
+	// no free subnets.
+	if bits <= parent.Bits() {
+		return netip.Prefix{}, errorf(http.StatusNotFound, "No subnets found")
+	}
+	var children []netip.Prefix
+	for _, o := range s.list("subnets", func(o object) bool { return o["masterSubnetId"] == id }) {
+		if p, err := objectPrefix(o); err == nil {
+			children = append(children, p)
+		}
+	}
+	candidate := netip.PrefixFrom(parent.Addr(), bits)
+	for i := 0; i < 65536 && parent.Contains(candidate.Addr()); i++ {
+		free := true
+		for _, c := range children {
+			if c.Overlaps(candidate) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate, nil
+		}
+		next := lastAddr(candidate).Next()
+		if !next.IsValid() {
+			break
+		}
+		candidate = netip.PrefixFrom(next, bits)
+	}
+	return netip.Prefix{}, errorf(http.StatusNotFound, "No subnets found")
+}
+
+// objectPrefix returns the prefix of a subnet object.
+func objectPrefix(o object) (netip.Prefix, error) {
+	return netip.ParsePrefix(fmt.Sprintf("%s/%s", toString(o["subnet"]), toString(o["mask"])))
+}
+
+// lastAddr returns the last address in a prefix.
+func lastAddr(p netip.Prefix) netip.Addr {
+	b := p.Masked().Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 1 << (7 - uint(i%8))
+	}
+	a, _ := netip.AddrFromSlice(b)
+	return a
+}
+
+// stringify converts all scalar values in a request body to strings, the way
+// PHPIPAM returns them with api-stringify-results set. Nested custom fields
+// are flattened into the body.
+func stringify(in object) object {
+	out := make(object)
+	for k, v := range in {
+		if k == "custom_fields" {
+			if m, ok := v.(map[string]interface{}); ok {
+				for ck, cv := range stringify(m) {
+					out[ck] = cv
+				}
+			}
+			continue
+		}
+		switch t := v.(type) {
+		case nil:
+			out[k] = nil
+		case map[string]interface{}, []interface{}:
+			out[k] = t
+		default:
+			out[k] = toString(t)
+		}
+	}
+	return out
+}
+
+// toString returns the string representation of a JSON value.
+func toString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		if t {
+			return "1"
+		}
+		return "0"
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// isNumeric returns true if the string is an integer ID.
+func isNumeric(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
+}
+
+// macDigits returns the hex digits of a MAC address in lower case, without
+// separators.
+func macDigits(s string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", "-", "", ".", "").Replace(s))
+}
@@ -0,0 +1,218 @@
+package fakephpipam
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/sections"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam/session"
+)
+
+func testSession(s *Server) *session.Session {
+	return session.NewSession(phpipam.Config{
+		AppID:    AppID,
+		Endpoint: s.Endpoint(),
+		Username: Username,
+		Password: Password,
+	})
+}
+
+func TestServerLogin(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	sess := testSession(s)
+	sess.Config.Password = "wrong"
+	if _, err := sections.NewController(sess).ListSections(); err == nil {
+		t.Fatalf("Expected error logging in with a bad password")
+	}
+
+	sess = testSession(s)
+	c := sections.NewController(sess)
+	out, err := c.ListSections()
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if len(out) != 2 || out[0].Name != "Customers" || out[1].Name != "IPv6" {
+		t.Fatalf("Expected default sections, got %#v", out)
+	}
+
+	// An expired token should be transparently refreshed by the SDK.
+	s.ExpireToken()
+	if _, err := c.ListSections(); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+}
+
+func TestServerSections(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := sections.NewController(testSession(s))
+
+	if _, err := c.CreateSection(sections.Section{Name: "tf-test", Description: "Test"}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if _, err := c.CreateSection(sections.Section{Name: "tf-test"}); err == nil {
+		t.Fatalf("Expected error creating a duplicate section")
+	}
+
+	section, err := c.GetSectionByName("tf-test")
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if section.ID != 3 || section.Description != "Test" {
+		t.Fatalf("Unexpected section %#v", section)
+	}
+
+	section.Description = "Updated"
+	if err := c.UpdateSection(section); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	section, err = c.GetSectionByID(3)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if section.Description != "Updated" {
+		t.Fatalf("Expected description to be updated, got %#v", section)
+	}
+
+	if err := c.DeleteSection(3); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	_, err = c.GetSectionByName("tf-test")
+	if err == nil || err.Error() != "Error from API (404): Not Found" {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+}
+
+func TestServerSubnetsAndAddresses(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	sess := testSession(s)
+	sc := subnets.NewController(sess)
+	ac := addresses.NewController(sess)
+
+	if _, err := sc.CreateSubnet(subnets.Subnet{SubnetAddress: "10.10.1.0", Mask: 24, SectionID: 1}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if _, err := sc.CreateSubnet(subnets.Subnet{SubnetAddress: "10.10.1.128", Mask: 25, SectionID: 1}); err == nil {
+		t.Fatalf("Expected error creating an overlapping subnet")
+	}
+
+	out, err := sc.GetSubnetsByCIDRAndSection("10.10.1.0/24", 1)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if len(out) != 1 || out[0].ID != 1 {
+		t.Fatalf("Unexpected subnets %#v", out)
+	}
+	_, err = sc.GetSubnetsByCIDRAndSection("10.10.1.0/24", 2)
+	if err == nil || err.Error() != "Error from API (404): No results (filter applied)" {
+		t.Fatalf("Expected filtered 404, got %v", err)
+	}
+
+	ip, err := sc.GetFirstFreeAddress(1)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if ip != "10.10.1.1" {
+		t.Fatalf("Expected 10.10.1.1, got %s", ip)
+	}
+	if _, err := ac.CreateAddress(addresses.Address{SubnetID: 1, IPAddress: ip, Hostname: "host1"}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if _, err := ac.CreateAddress(addresses.Address{SubnetID: 1, IPAddress: "10.10.2.1"}); err == nil {
+		t.Fatalf("Expected error creating an address outside of the subnet")
+	}
+	next, err := ac.CreateFirstFreeAddress(1, addresses.Address{Hostname: "host2"})
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if next != "10.10.1.2" {
+		t.Fatalf("Expected 10.10.1.2, got %s", next)
+	}
+
+	addrs, err := sc.GetAddressesInSubnet(1)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if len(addrs) != 2 || addrs[1].Hostname != "host2" {
+		t.Fatalf("Unexpected addresses %#v", addrs)
+	}
+
+	child, err := sc.CreateFirstFreeSubnet(1, 26, subnets.Subnet{Description: "child"})
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if child != "10.10.1.0/26" {
+		t.Fatalf("Expected 10.10.1.0/26, got %s", child)
+	}
+	next, err = sc.GetFirstFreeSubnet(1, 26)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if next != "10.10.1.64/26" {
+		t.Fatalf("Expected 10.10.1.64/26, got %s", next)
+	}
+
+	// Deleting the subnet removes its addresses and child subnets.
+	if _, err := sc.DeleteSubnet(1); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	_, err = ac.GetAddressesByIP("10.10.1.1")
+	if err == nil || err.Error() != "Error from API (404): Address not found" {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+	_, err = sc.GetSubnetsByCIDR("10.10.1.0/26")
+	if err == nil || err.Error() != "Error from API (404): No subnets found" {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+}
+
+func TestServerCustomFields(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	sess := testSession(s)
+	sc := subnets.NewController(sess)
+	ac := addresses.NewController(sess)
+
+	_, err := ac.GetAddressCustomFieldsSchema()
+	if err == nil || err.Error() != "Error from API (200): No custom fields defined" {
+		t.Fatalf("Expected no custom fields, got %v", err)
+	}
+
+	s.AddCustomField("addresses", "custom_Owner", "varchar(255)")
+	if _, err := sc.CreateSubnet(subnets.Subnet{SubnetAddress: "10.10.1.0", Mask: 24, SectionID: 1}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if _, err := ac.CreateAddress(addresses.Address{SubnetID: 1, IPAddress: "10.10.1.10"}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if _, err := ac.UpdateAddressCustomFields(1, map[string]interface{}{"custom_Owner": "network"}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+
+	expected := map[string]interface{}{"custom_Owner": "network"}
+	actual, err := ac.GetAddressCustomFields(1)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("Expected %#v, got %#v", expected, actual)
+	}
+
+	// Setting a field to null clears it.
+	if _, err := ac.UpdateAddressCustomFields(1, map[string]interface{}{"custom_Owner": nil}); err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	actual, err = ac.GetAddressCustomFields(1)
+	if err != nil {
+		t.Fatalf("Bad: %s", err)
+	}
+	if len(actual) != 0 {
+		t.Fatalf("Expected custom field to be cleared, got %#v", actual)
+	}
+}
@@ -0,0 +1,57 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// The functions in this file convert between PHPIPAM's values and the values
+// of resources implemented with terraform-plugin-framework.
+//
+// PHPIPAM does not tell an empty field from a field that is not set, so empty
+// values are read as null, unless the configuration sets the field to an empty
+// value explicitly. Optional fields that are not computed can then be cleared
+// by removing them from the configuration.
+
+// frameworkString returns the value of a string field read from PHPIPAM, with
+// prior being the value in the state or plan before the read.
+func frameworkString(prior types.String, v string) types.String {
+	if v == "" && (prior.IsNull() || prior.IsUnknown() || prior.ValueString() != "") {
+		return types.StringNull()
+	}
+	return types.StringValue(v)
+}
+
+// frameworkCustomFields returns the custom fields read from PHPIPAM as a map
+// of strings, with prior being the value in the state or plan before the
+// read. Fields with empty values are not set.
+func frameworkCustomFields(ctx context.Context, prior types.Map, fields map[string]interface{}) (types.Map, diag.Diagnostics) {
+	trimMap(fields)
+	if len(fields) == 0 && (prior.IsNull() || prior.IsUnknown() || len(prior.Elements()) > 0) {
+		return types.MapNull(types.StringType), nil
+	}
+	m := make(map[string]string, len(fields))
+	for k, v := range fields {
+		m[k] = fmt.Sprint(v)
+	}
+	return types.MapValueFrom(ctx, types.StringType, m)
+}
+
+// frameworkCustomFieldsUpdate returns the custom fields to send to PHPIPAM
+// to update the fields in old, as read from PHPIPAM, to the fields in the map
+// v. Fields that are not set in v are cleared.
+func frameworkCustomFieldsUpdate(ctx context.Context, v types.Map, old map[string]interface{}) (map[string]interface{}, diag.Diagnostics) {
+	set := make(map[string]string)
+	diags := v.ElementsAs(ctx, &set, true)
+	out := make(map[string]interface{}, len(set)+len(old))
+	for k := range old {
+		out[k] = nil
+	}
+	for k, v := range set {
+		out[k] = v
+	}
+	return out, diags
+}
@@ -0,0 +1,160 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The importers in this file allow resources to be imported by their natural
+// keys, such as the name of a section, in addition to their database IDs.
+// Numeric import IDs are always taken as database IDs, and passed through.
+
+// importNaturalKey returns a schema.StateContextFunc that resolves import IDs
+// that are not numeric with resolve, which returns the database ID of the
+// object that the import ID identifies.
+func importNaturalKey(resolve func(id string, meta interface{}) (int, error)) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		if _, err := strconv.Atoi(d.Id()); err == nil {
+			return []*schema.ResourceData{d}, nil
+		}
+		id, err := resolve(d.Id(), meta)
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(strconv.Itoa(id))
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// importSectionID resolves a section name to its ID.
+func importSectionID(name string, meta interface{}) (int, error) {
+	out, err := meta.(*ProviderPHPIPAMClient).sectionsController.GetSectionByName(name)
+	switch {
+	case err != nil && apiErrorCode(err) == 404:
+		return 0, fmt.Errorf("Cannot import section %q: no section with this name", name)
+	case err != nil:
+		return 0, err
+	}
+	return out.ID, nil
+}
+
+// importL2DomainID resolves an L2 domain name to its ID.
+func importL2DomainID(name string, meta interface{}) (int, error) {
+	out, err := meta.(*ProviderPHPIPAMClient).l2domainsController.GetL2DomainByName(name)
+	if err != nil && apiErrorCode(err) != 404 {
+		return 0, err
+	}
+	if len(out) != 1 {
+		return 0, fmt.Errorf("Cannot import L2 domain %q: %d L2 domains found with this name", name, len(out))
+	}
+	return out[0].ID, nil
+}
+
+// importSubnetID resolves a subnet import ID of the form
+// section_name/subnet_address/subnet_mask to the subnet's ID.
+func importSubnetID(id string, meta interface{}) (int, error) {
+	section, cidr, ok := cutCIDR(id)
+	if !ok || section == "" {
+		return 0, fmt.Errorf("Invalid subnet import ID %q, expected a subnet ID or section_name/subnet_address/subnet_mask", id)
+	}
+	sectionID, err := importSectionID(section, meta)
+	if err != nil {
+		return 0, err
+	}
+	out, err := meta.(*ProviderPHPIPAMClient).subnetsController.GetSubnetsByCIDRAndSection(cidr, sectionID)
+	if err != nil && apiErrorCode(err) != 404 {
+		return 0, err
+	}
+	if len(out) != 1 {
+		return 0, fmt.Errorf("Cannot import subnet %q: %d subnets found with CIDR %s in section %q", id, len(out), cidr, section)
+	}
+	return out[0].ID, nil
+}
+
+// importAddressID resolves an address import ID, which is either an IP
+// address that is unique across subnets, or of the form
+// subnet_address/subnet_mask/ip_address, to the address's ID.
+func importAddressID(id string, meta interface{}) (int, error) {
+	client := meta.(*ProviderPHPIPAMClient)
+	if _, err := netip.ParseAddr(id); err == nil {
+		out, err := client.addressesController.GetAddressesByIP(id)
+		if err != nil && apiErrorCode(err) != 404 {
+			return 0, err
+		}
+		if len(out) != 1 {
+			return 0, fmt.Errorf("Cannot import address %q: %d addresses found with this IP address. Use subnet_address/subnet_mask/ip_address to import an address in a specific subnet", id, len(out))
+		}
+		return out[0].ID, nil
+	}
+
+	i := strings.LastIndex(id, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("Invalid address import ID %q, expected an address ID, an IP address, or subnet_address/subnet_mask/ip_address", id)
+	}
+	cidr, ip := id[:i], id[i+1:]
+	if _, err := netip.ParsePrefix(cidr); err != nil {
+		return 0, fmt.Errorf("Invalid address import ID %q, expected an address ID, an IP address, or subnet_address/subnet_mask/ip_address", id)
+	}
+	subnets, err := client.subnetsController.GetSubnetsByCIDR(cidr)
+	if err != nil && apiErrorCode(err) != 404 {
+		return 0, err
+	}
+	if len(subnets) != 1 {
+		return 0, fmt.Errorf("Cannot import address %q: %d subnets found with CIDR %s", id, len(subnets), cidr)
+	}
+	out, err := client.addressesController.GetAddressesByIpInSubnet(ip, subnets[0].ID)
+	switch {
+	case err != nil && apiErrorCode(err) == 404:
+		return 0, fmt.Errorf("Cannot import address %q: no address %s in subnet %s", id, ip, cidr)
+	case err != nil:
+		return 0, err
+	}
+	return out.ID, nil
+}
+
+// importVLANID resolves a VLAN import ID of the form l2domain_name/number to
+// the VLAN's ID.
+func importVLANID(id string, meta interface{}) (int, error) {
+	i := strings.LastIndex(id, "/")
+	if i < 0 {
+		return 0, fmt.Errorf("Invalid VLAN import ID %q, expected a VLAN ID or l2domain_name/number", id)
+	}
+	number, err := strconv.Atoi(id[i+1:])
+	if err != nil {
+		return 0, fmt.Errorf("Invalid VLAN import ID %q, expected a VLAN ID or l2domain_name/number", id)
+	}
+	domainID, err := importL2DomainID(id[:i], meta)
+	if err != nil {
+		return 0, err
+	}
+	out, err := meta.(*ProviderPHPIPAMClient).vlansController.GetVLANsByNumberAndDomainID(number, domainID)
+	if err != nil && apiErrorCode(err) != 404 {
+		return 0, err
+	}
+	if len(out) != 1 {
+		return 0, fmt.Errorf("Cannot import VLAN %q: %d VLANs found with number %d in L2 domain %q", id, len(out), number, id[:i])
+	}
+	return out[0].ID, nil
+}
+
+// cutCIDR splits s, of the form prefix/address/mask, into the prefix and the
+// CIDR at its end. The prefix may contain slashes itself.
+func cutCIDR(s string) (prefix, cidr string, ok bool) {
+	i := strings.LastIndex(s, "/")
+	if i < 0 {
+		return "", "", false
+	}
+	j := strings.LastIndex(s[:i], "/")
+	if j < 0 {
+		return "", "", false
+	}
+	if _, err := netip.ParsePrefix(s[j+1:]); err != nil {
+		return "", "", false
+	}
+	return s[:j], s[j+1:], true
+}
@@ -0,0 +1,83 @@
+package phpipam
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestImportNaturalKeys(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	subnet := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(subnetID)})
+	address := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.10",
+	})
+	domain := testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name": "tf-test/domain",
+	})
+	domainID, _ := strconv.Atoi(domain.ID)
+	vlan := testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+		"name":         "terraform",
+		"number":       100,
+		"l2_domain_id": domainID,
+	})
+
+	cases := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"phpipam_section", "tf-test", subnet.Attributes["section_id"]},
+		{"phpipam_section", subnet.Attributes["section_id"], subnet.Attributes["section_id"]},
+		{"phpipam_l2domain", "tf-test/domain", domain.ID},
+		{"phpipam_subnet", "tf-test/10.10.1.0/24", subnet.ID},
+		{"phpipam_address", "10.10.1.10", address.ID},
+		{"phpipam_address", "10.10.1.0/24/10.10.1.10", address.ID},
+		{"phpipam_address", address.ID, address.ID},
+		{"phpipam_vlan", "tf-test/domain/100", vlan.ID},
+	}
+	for _, tc := range cases {
+		if state := testFakeImport(t, meta, tc.name, tc.id); state.ID != tc.expected {
+			t.Errorf("%s %s: expected ID %s, got %s", tc.name, tc.id, tc.expected, state.ID)
+		}
+	}
+
+	// An address that exists in several subnets can only be imported with its
+	// subnet.
+	other := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     1,
+		"subnet_address": "10.10.1.0",
+		"subnet_mask":    24,
+	})
+	otherID, _ := strconv.Atoi(other.ID)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  otherID,
+		"ip_address": "10.10.1.10",
+	})
+
+	failures := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"phpipam_section", "missing", "no section with this name"},
+		{"phpipam_subnet", "tf-test/10.10.2.0/24", "0 subnets found with CIDR 10.10.2.0/24"},
+		{"phpipam_subnet", "10.10.1.0/24", "expected a subnet ID or section_name/subnet_address/subnet_mask"},
+		{"phpipam_address", "10.10.1.10", "2 addresses found with this IP address"},
+		{"phpipam_address", "10.10.1.0/24/10.10.1.10", "2 subnets found with CIDR 10.10.1.0/24"},
+		{"phpipam_vlan", "tf-test/domain/101", "0 VLANs found with number 101"},
+		{"phpipam_vlan", "tf-test/domain", "expected a VLAN ID or l2domain_name/number"},
+	}
+	for _, tc := range failures {
+		r := Provider().ResourcesMap[tc.name]
+		_, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: tc.id}), meta)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s %s: expected error %q, got %v", tc.name, tc.id, tc.expected, err)
+		}
+	}
+}
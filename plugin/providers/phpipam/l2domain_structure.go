@@ -1,6 +1,7 @@
 package phpipam
 
 import (
+	"regexp"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -56,6 +57,7 @@ func resourceL2DomainSchema() map[string]*schema.Schema {
 			v.Computed = true
 		}
 	}
+	schema["adopt_existing"] = adoptExistingSchema()
 	return schema
 }
 
@@ -82,6 +84,17 @@ func dataSourceL2DomainSchema() map[string]*schema.Schema {
 	return schema
 }
 
+// dataSourceL2DomainsSchema returns the sub-schema for the l2domains list of
+// the phpipam_l2domains data source. All this function does is set all fields
+// as computed.
+func dataSourceL2DomainsSchema() map[string]*schema.Schema {
+	schema := bareL2DomainSchema()
+	for _, v := range schema {
+		v.Computed = true
+	}
+	return schema
+}
+
 // expandL2Domain returns the l2domains.L2Domain structure for a
 // phpiapm_l2domain resource or data source. Depending on if we are dealing with
 // the resource or data source, extra considerations may need to be taken.
@@ -105,3 +118,57 @@ func flattenL2Domain(l l2domains.L2Domain, d *schema.ResourceData) {
 	d.Set("description", l.Description)
 	d.Set("sections", l.Sections)
 }
+
+// flattenL2DomainMap returns the fields supplied by the input
+// l2domains.L2Domain as a map, in the form used by the l2domains list of the
+// phpipam_l2domains data source.
+func flattenL2DomainMap(l l2domains.L2Domain) map[string]interface{} {
+	return map[string]interface{}{
+		"domain_id":   l.ID,
+		"name":        l.Name,
+		"description": l.Description,
+		"sections":    l.Sections,
+	}
+}
+
+// l2DomainSearch provides the L2 domain search functionality for the
+// phpipam_l2domains data source, returning the L2 domains that match the name,
+// description and custom field filters.
+func l2DomainSearch(d *schema.ResourceData, meta interface{}) ([]l2domains.L2Domain, error) {
+	c := meta.(*ProviderPHPIPAMClient).l2domainsController
+	result := make([]l2domains.L2Domain, 0)
+
+	v, err := c.ListL2Domains()
+	if err != nil && apiErrorCode(err) != 404 {
+		return result, err
+	}
+	for _, r := range v {
+		// Don't trap regex errors here because we should have already validated
+		// the expressions via the ValidateFunc.
+		if expr := d.Get("name_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Name); !matched {
+				continue
+			}
+		}
+		if expr := d.Get("description_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Description); !matched {
+				continue
+			}
+		}
+		if search := d.Get("custom_field_filter").(map[string]interface{}); len(search) > 0 {
+			fields, err := c.GetL2DomainCustomFields(r.ID)
+			if err != nil {
+				return result, err
+			}
+			matched, err := customFieldFilter(fields, search)
+			if err != nil {
+				return result, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
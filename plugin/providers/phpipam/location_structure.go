@@ -0,0 +1,125 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/locations"
+)
+
+// resourceLocationOptionalFields represents all the fields that are optional
+// in the phpipam_location resource. These fields get flagged as Optional, with
+// zero value defaults (the field is not set), in addition to being marked as
+// Computed. Any field not listed here cannot be supplied by the resource and
+// is solely computed.
+var resourceLocationOptionalFields = linearSearchSlice{
+	"description",
+	"address",
+	"latitude",
+	"longitude",
+}
+
+// bareLocationSchema returns a map[string]*schema.Schema with the schema used
+// to represent a PHPIPAM location resource. This output should then be
+// modified so that required and computed fields are set properly for both the
+// data source and the resource.
+func bareLocationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"location_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"name": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"description": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"address": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"latitude": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"longitude": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"custom_fields": &schema.Schema{
+			Type: schema.TypeMap,
+		},
+	}
+}
+
+// resourceLocationSchema returns the schema for the phpipam_location resource.
+// It sets the required and optional fields, the latter defined in
+// resourceLocationOptionalFields, and ensures that all optional and
+// non-configurable fields are computed as well.
+func resourceLocationSchema() map[string]*schema.Schema {
+	schema := bareLocationSchema()
+	for k, v := range schema {
+		switch {
+		// Location name is required
+		case k == "name":
+			v.Required = true
+		case k == "custom_fields":
+			v.Optional = true
+		case resourceLocationOptionalFields.Has(k):
+			v.Optional = true
+			v.Computed = true
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// dataSourceLocationSchema returns the schema for the phpipam_location data
+// source. It sets the searchable fields and sets up the attribute conflicts
+// between location ID and name. It also ensures that all fields are computed
+// as well.
+func dataSourceLocationSchema() map[string]*schema.Schema {
+	schema := bareLocationSchema()
+	for k, v := range schema {
+		switch k {
+		case "location_id":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"name"}
+		case "name":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"location_id"}
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// expandLocation returns the locations.Location structure for a
+// phpipam_location resource or data source. Depending on if we are dealing
+// with the resource or data source, extra considerations may need to be
+// taken.
+func expandLocation(d *schema.ResourceData) locations.Location {
+	l := locations.Location{
+		ID:          d.Get("location_id").(int),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+		Address:     d.Get("address").(string),
+		Latitude:    d.Get("latitude").(string),
+		Longitude:   d.Get("longitude").(string),
+	}
+
+	return l
+}
+
+// flattenLocation sets fields in a *schema.ResourceData with fields supplied
+// by the input locations.Location. This is used in read operations.
+func flattenLocation(l locations.Location, d *schema.ResourceData) {
+	d.SetId(strconv.Itoa(l.ID))
+	d.Set("location_id", l.ID)
+	d.Set("name", l.Name)
+	d.Set("description", l.Description)
+	d.Set("address", l.Address)
+	d.Set("latitude", l.Latitude)
+	d.Set("longitude", l.Longitude)
+}
@@ -0,0 +1,143 @@
+package phpipam
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/nameservers"
+)
+
+// resourceNameserverOptionalFields represents all the fields that are optional
+// in the phpipam_nameserver resource. These fields get flagged as Optional,
+// with zero value defaults (the field is not set), in addition to being marked
+// as Computed. Any field not listed here cannot be supplied by the resource and
+// is solely computed.
+var resourceNameserverOptionalFields = linearSearchSlice{
+	"description",
+	"sections",
+}
+
+// bareNameserverSchema returns a map[string]*schema.Schema with the schema
+// used to represent a PHPIPAM nameserver set. This output should then be
+// modified so that required and computed fields are set properly for both the
+// data source and the resource.
+func bareNameserverSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"nameserver_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"name": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"addresses": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeString},
+		},
+		"description": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"sections": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeInt},
+		},
+		"edit_date": &schema.Schema{
+			Type: schema.TypeString,
+		},
+	}
+}
+
+// resourceNameserverSchema returns the schema for the phpipam_nameserver
+// resource. It sets the required and optional fields, the latter defined in
+// resourceNameserverOptionalFields, and ensures that all optional and
+// non-configurable fields are computed as well.
+func resourceNameserverSchema() map[string]*schema.Schema {
+	schema := bareNameserverSchema()
+	for k, v := range schema {
+		switch {
+		// Name and addresses are required
+		case k == "name" || k == "addresses":
+			v.Required = true
+		case resourceNameserverOptionalFields.Has(k):
+			v.Optional = true
+			v.Computed = true
+		default:
+			v.Computed = true
+		}
+	}
+	// At least one nameserver address needs to be supplied.
+	schema["addresses"].MinItems = 1
+	return schema
+}
+
+// dataSourceNameserverSchema returns the schema for the phpipam_nameserver
+// data source. It sets the searchable fields and sets up the attribute
+// conflicts between nameserver ID and name. It also ensures that all fields
+// are computed as well.
+func dataSourceNameserverSchema() map[string]*schema.Schema {
+	schema := bareNameserverSchema()
+	for k, v := range schema {
+		switch k {
+		case "nameserver_id":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"name"}
+		case "name":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"nameserver_id"}
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// expandNameserver returns the nameservers.Nameserver structure for a
+// phpipam_nameserver resource or data source. The address and section lists
+// are joined into the semicolon-separated strings that PHPIPAM expects.
+func expandNameserver(d *schema.ResourceData) nameservers.Nameserver {
+	var addresses []string
+	for _, v := range d.Get("addresses").([]interface{}) {
+		addresses = append(addresses, v.(string))
+	}
+	var sections []string
+	for _, v := range d.Get("sections").([]interface{}) {
+		sections = append(sections, strconv.Itoa(v.(int)))
+	}
+
+	n := nameservers.Nameserver{
+		ID:          d.Get("nameserver_id").(int),
+		Name:        d.Get("name").(string),
+		Addresses:   strings.Join(addresses, ";"),
+		Description: d.Get("description").(string),
+		Permissions: strings.Join(sections, ";"),
+	}
+
+	return n
+}
+
+// flattenNameserver sets fields in a *schema.ResourceData with fields supplied
+// by the input nameservers.Nameserver. This is used in read operations.
+func flattenNameserver(n nameservers.Nameserver, d *schema.ResourceData) {
+	var addresses []string
+	for _, v := range strings.Split(n.Addresses, ";") {
+		if v != "" {
+			addresses = append(addresses, v)
+		}
+	}
+	var sections []int
+	for _, v := range strings.Split(n.Permissions, ";") {
+		if id, err := strconv.Atoi(v); err == nil {
+			sections = append(sections, id)
+		}
+	}
+
+	d.SetId(strconv.Itoa(n.ID))
+	d.Set("nameserver_id", n.ID)
+	d.Set("name", n.Name)
+	d.Set("addresses", addresses)
+	d.Set("description", n.Description)
+	d.Set("sections", sections)
+	d.Set("edit_date", n.EditDate)
+}
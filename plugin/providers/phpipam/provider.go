@@ -1,7 +1,11 @@
 package phpipam
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 // Provider returns a terraform.ResourceProvider.
@@ -44,13 +48,88 @@ func Provider() *schema.Provider {
 				Default:     false,
 				Description: descriptions["nest_custom_fields"],
 			},
+			"retry": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["retry"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"max_attempts": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  descriptions["retry_max_attempts"],
+						},
+						"min_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "1s",
+							ValidateFunc: validateDuration,
+							Description:  descriptions["retry_min_backoff"],
+						},
+						"max_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "30s",
+							ValidateFunc: validateDuration,
+							Description:  descriptions["retry_max_backoff"],
+						},
+						"retryable_status_codes": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeInt},
+							Description: descriptions["retry_retryable_status_codes"],
+						},
+					},
+				},
+			},
+			"allocation": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["allocation"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"mode": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "local",
+							ValidateFunc: validation.StringInSlice([]string{"local", "verify"}, false),
+							Description:  descriptions["allocation_mode"],
+						},
+						"max_attempts": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      5,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  descriptions["allocation_max_attempts"],
+						},
+						"max_backoff": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "2s",
+							ValidateFunc: validateDuration,
+							Description:  descriptions["allocation_max_backoff"],
+						},
+					},
+				},
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
 			"phpipam_address":            resourcePHPIPAMAddress(),
+			"phpipam_address_tag":        resourcePHPIPAMAddressTag(),
+			"phpipam_address_block":      resourcePHPIPAMAddressBlock(),
+			"phpipam_address_range":      resourcePHPIPAMAddressRange(),
+			"phpipam_device":             resourcePHPIPAMDevice(),
 			"phpipam_section":            resourcePHPIPAMSection(),
 			"phpipam_l2domain":           resourcePHPIPAML2Domain(),
+			"phpipam_location":           resourcePHPIPAMLocation(),
+			"phpipam_nameserver":         resourcePHPIPAMNameserver(),
 			"phpipam_subnet":             resourcePHPIPAMSubnet(),
+			"phpipam_subnet_split":       resourcePHPIPAMSubnetSplit(),
 			"phpipam_vlan":               resourcePHPIPAMVLAN(),
 			"phpipam_first_free_address": resourcePHPIPAMFirstFreeAddress(),
 			"phpipam_first_free_subnet":  resourcePHPIPAMFirstFreeSubnet(),
@@ -58,13 +137,25 @@ func Provider() *schema.Provider {
 
 		DataSourcesMap: map[string]*schema.Resource{
 			"phpipam_address":            dataSourcePHPIPAMAddress(),
+			"phpipam_address_tag":        dataSourcePHPIPAMAddressTag(),
 			"phpipam_addresses":          dataSourcePHPIPAMAddresses(),
+			"phpipam_device":             dataSourcePHPIPAMDevice(),
+			"phpipam_devices":            dataSourcePHPIPAMDevices(),
 			"phpipam_first_free_address": dataSourcePHPIPAMFirstFreeAddress(),
 			"phpipam_section":            dataSourcePHPIPAMSection(),
+			"phpipam_sections":           dataSourcePHPIPAMSections(),
 			"phpipam_l2domain":           dataSourcePHPIPAML2Domain(),
+			"phpipam_l2domains":          dataSourcePHPIPAML2Domains(),
+			"phpipam_location":           dataSourcePHPIPAMLocation(),
+			"phpipam_nameserver":         dataSourcePHPIPAMNameserver(),
+			"phpipam_search":             dataSourcePHPIPAMSearch(),
 			"phpipam_subnet":             dataSourcePHPIPAMSubnet(),
 			"phpipam_subnets":            dataSourcePHPIPAMSubnets(),
+			"phpipam_subnet_tree":        dataSourcePHPIPAMSubnetTree(),
+			"phpipam_subnet_usage":       dataSourcePHPIPAMSubnetUsage(),
 			"phpipam_vlan":               dataSourcePHPIPAMVLAN(),
+			"phpipam_vlans":              dataSourcePHPIPAMVLANs(),
+			"phpipam_vrf":                dataSourcePHPIPAMVRF(),
 			"phpipam_first_free_subnet":  dataSourcePHPIPAMFirstFreeSubnet(),
 		},
 
@@ -84,9 +175,42 @@ func init() {
 			"without verifying the TLS certificate.",
 		"nest_custom_fields": "Whether the API client is configured " +
 			"to nest custom values.",
+		"retry": "Settings for retrying API requests that fail " +
+			"with transient errors. Requests are not retried if this is not set.",
+		"retry_max_attempts": "The maximum number of attempts made " +
+			"for a request, including the first one.",
+		"retry_min_backoff": "The delay before the first retry, as a " +
+			"duration such as \"500ms\". The delay doubles with every retry.",
+		"retry_max_backoff": "The maximum delay between two attempts.",
+		"retry_retryable_status_codes": "The HTTP status codes that " +
+			"are retried. Defaults to 502, 503 and 504.",
+		"allocation": "Settings for allocating free IP addresses " +
+			"and subnets.",
+		"allocation_mode": "How allocations are protected against " +
+			"concurrent allocations. \"local\" only serializes allocations " +
+			"within the provider. \"verify\" reserves every allocation, " +
+			"verifies it against concurrent allocations from other processes, " +
+			"and allocates again on conflict.",
+		"allocation_max_attempts": "The maximum number of allocation " +
+			"attempts when allocations conflict, in verify mode.",
+		"allocation_max_backoff": "The maximum random delay before " +
+			"allocating again after a conflict, in verify mode.",
 	}
 }
 
+// defaultRetryableStatusCodes are the HTTP status codes retried when the
+// retry block does not list any. These are the errors that a reverse proxy
+// in front of PHPIPAM returns while PHPIPAM is unavailable.
+var defaultRetryableStatusCodes = []int{502, 503, 504}
+
+// validateDuration validates that a string is a valid Go duration.
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q must be a duration such as \"1s\": %s", k, err))
+	}
+	return
+}
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	config := Config{
 		AppID:            d.Get("app_id").(string),
@@ -95,6 +219,66 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		Username:         d.Get("username").(string),
 		Insecure:         d.Get("insecure").(bool),
 		NestCustomFields: d.Get("nest_custom_fields").(bool),
+		Retry:            expandRetryConfig(d.Get("retry").([]interface{})),
+		Allocation:       expandAllocationConfig(d.Get("allocation").([]interface{})),
 	}
 	return config.Client()
 }
+
+// expandRetryConfig returns the RetryConfig for the retry block. Requests are
+// made only once if the block is not set.
+func expandRetryConfig(v []interface{}) RetryConfig {
+	if len(v) == 0 || v[0] == nil {
+		return RetryConfig{MaxAttempts: 1}
+	}
+	m := v[0].(map[string]interface{})
+	c := RetryConfig{
+		MaxAttempts:          m["max_attempts"].(int),
+		RetryableStatusCodes: defaultRetryableStatusCodes,
+	}
+	// The durations have been validated already.
+	c.MinBackoff, _ = time.ParseDuration(m["min_backoff"].(string))
+	c.MaxBackoff, _ = time.ParseDuration(m["max_backoff"].(string))
+	if codes := m["retryable_status_codes"].([]interface{}); len(codes) > 0 {
+		c.RetryableStatusCodes = make([]int, len(codes))
+		for i, code := range codes {
+			c.RetryableStatusCodes[i] = code.(int)
+		}
+	}
+	return c
+}
+
+// expandAllocationConfig returns the AllocationConfig for the allocation
+// block. Allocations are only serialized within the provider if the block is
+// not set.
+func expandAllocationConfig(v []interface{}) AllocationConfig {
+	if len(v) == 0 || v[0] == nil {
+		return AllocationConfig{MaxAttempts: 1}
+	}
+	m := v[0].(map[string]interface{})
+	c := AllocationConfig{
+		Verify:      m["mode"].(string) == "verify",
+		MaxAttempts: m["max_attempts"].(int),
+	}
+	// The duration has been validated already.
+	c.MaxBackoff, _ = time.ParseDuration(m["max_backoff"].(string))
+	return c
+}
+
+// DefaultConfig returns the Config for clients that are not configured
+// through Terraform, such as the export mode of the provider binary. Requests
+// are retried with the defaults of the retry block, and the connection
+// settings are read from the environment by Client.
+func DefaultConfig() Config {
+	defaults := make(map[string]interface{})
+	for k, v := range Provider().Schema["retry"].Elem.(*schema.Resource).Schema {
+		defaults[k] = v.Default
+		if v.Type == schema.TypeList {
+			defaults[k] = []interface{}{}
+		}
+	}
+	return Config{
+		Retry:      expandRetryConfig([]interface{}{defaults}),
+		Allocation: expandAllocationConfig(nil),
+	}
+}
@@ -0,0 +1,1051 @@
+package phpipam
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// testAccTest runs an acceptance test case. With TF_ACC set, the case is run
+// by resource.Test, which needs a Terraform binary. Otherwise it is run
+// in-process by a testAccRunner, against the fake PHPIPAM server started by
+// testAccPreCheck, so that the acceptance tests run in a plain go test too.
+func testAccTest(t *testing.T, c resource.TestCase) {
+	t.Helper()
+	if os.Getenv(resource.EnvTfAcc) != "" {
+		resource.Test(t, c)
+		return
+	}
+	r := &testAccRunner{
+		t:         t,
+		instances: make(map[string]*testAccInstance),
+		outputs:   make(map[string]cty.Value),
+	}
+	r.run(c)
+}
+
+// testAccRunner runs the steps of an acceptance test case the way Terraform
+// does, through the provider server of the test case. It supports what the
+// acceptance tests use: resources and data sources with count and
+// depends_on, variables with defaults, outputs, and the length, element and
+// split functions. Each step is applied, checked, and must plan no changes
+// afterwards. Resources are destroyed when the test case finishes.
+type testAccRunner struct {
+	t       *testing.T
+	server  tfprotov5.ProviderServer
+	schemas *tfprotov5.GetProviderSchemaResponse
+
+	// instances holds the state of resource and data source instances, by
+	// address.
+	instances map[string]*testAccInstance
+	// order holds the addresses of resource instances in the order they were
+	// created, so that they are destroyed in reverse order.
+	order   []string
+	outputs map[string]cty.Value
+}
+
+// testAccInstance is the state of a resource or data source instance.
+type testAccInstance struct {
+	data    bool
+	typ     string
+	name    string
+	index   int
+	counted bool
+	value   tftypes.Value
+	private []byte
+}
+
+// stateKey returns the key of the instance in a terraform.State.
+func (i *testAccInstance) stateKey() string {
+	key := i.typ + "." + i.name
+	if i.data {
+		key = "data." + key
+	}
+	if i.counted {
+		key += "." + strconv.Itoa(i.index)
+	}
+	return key
+}
+
+// testAccBlock is a block of a configuration, along with the addresses of
+// the blocks that it depends on.
+type testAccBlock struct {
+	*hclsyntax.Block
+	addr string
+	deps []string
+}
+
+// testAccMetaArguments are the arguments of resources and data sources that
+// are handled by Terraform rather than by the provider.
+var testAccMetaArguments = map[string]bool{
+	"count":      true,
+	"depends_on": true,
+	"provider":   true,
+}
+
+func (r *testAccRunner) run(c resource.TestCase) {
+	t := r.t
+	t.Helper()
+	if c.PreCheck != nil {
+		c.PreCheck()
+	}
+	r.configure(c)
+	defer r.destroy(c)
+
+	for i, step := range c.Steps {
+		if step.PlanOnly || step.ExpectNonEmptyPlan || step.Destroy || step.ImportStateIdFunc != nil || step.ImportStatePersist {
+			t.Fatalf("Step %d/%d: only Config, Check, ExpectError and ImportState are supported without TF_ACC", i+1, len(c.Steps))
+		}
+		if step.ImportState {
+			if err := r.importState(step); err != nil {
+				t.Fatalf("Step %d/%d error: %s", i+1, len(c.Steps), err)
+			}
+			continue
+		}
+		err := r.apply(step.Config, false)
+		switch {
+		case err != nil && step.ExpectError == nil:
+			t.Fatalf("Step %d/%d error: %s", i+1, len(c.Steps), err)
+		case err != nil && !step.ExpectError.MatchString(err.Error()):
+			t.Fatalf("Step %d/%d, expected an error matching %s, got: %s", i+1, len(c.Steps), step.ExpectError, err)
+		case err != nil:
+			continue
+		case step.ExpectError != nil:
+			t.Fatalf("Step %d/%d, expected an error but got none", i+1, len(c.Steps))
+		}
+		if step.Check != nil {
+			if err := step.Check(r.state()); err != nil {
+				t.Fatalf("Step %d/%d error: Check failed: %s", i+1, len(c.Steps), err)
+			}
+		}
+		if err := r.refresh(); err != nil {
+			t.Fatalf("Step %d/%d error: %s", i+1, len(c.Steps), err)
+		}
+		if err := r.apply(step.Config, true); err != nil {
+			t.Fatalf("Step %d/%d error: After applying this test step, the plan was not empty: %s", i+1, len(c.Steps), err)
+		}
+	}
+}
+
+// configure starts the provider server of the test case and configures it
+// from the environment, like an empty provider block does.
+func (r *testAccRunner) configure(c resource.TestCase) {
+	t := r.t
+	t.Helper()
+	ctx := context.Background()
+	factory, ok := c.ProtoV5ProviderFactories["phpipam"]
+	if !ok {
+		t.Fatal("Only ProtoV5ProviderFactories are supported without TF_ACC")
+	}
+	server, err := factory()
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if diags := testProtoDiagsError(schemas.Diagnostics); diags != "" {
+		t.Fatalf("bad: %s", diags)
+	}
+	resp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{
+		Config: testProtoDynamicValue(t, schemas.Provider, map[string]interface{}{}),
+	})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if diags := testProtoDiagsError(resp.Diagnostics); diags != "" {
+		t.Fatalf("bad: %s", diags)
+	}
+	r.server = server
+	r.schemas = schemas
+}
+
+// importState imports the resource of an import step by ID, and checks the
+// imported state. The imported resource is not kept in the state.
+func (r *testAccRunner) importState(step resource.TestStep) error {
+	var inst *testAccInstance
+	for _, v := range r.instances {
+		if v.stateKey() == step.ResourceName {
+			inst = v
+		}
+	}
+	if inst == nil || inst.data {
+		return fmt.Errorf("Resource %s not found in the state", step.ResourceName)
+	}
+	current := r.state().RootModule().Resources[step.ResourceName].Primary
+	id := step.ImportStateId
+	if id == "" {
+		id = current.ID
+	}
+
+	s := r.schemas.ResourceSchemas[inst.typ]
+	resp, err := r.server.ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{TypeName: inst.typ, ID: id})
+	if err != nil {
+		return err
+	}
+	if err := testAccDiagsError(resp.Diagnostics); err != nil {
+		return err
+	}
+	var imported []*terraform.InstanceState
+	for _, v := range resp.ImportedResources {
+		read, err := r.server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+			TypeName:     v.TypeName,
+			CurrentState: v.State,
+			Private:      v.Private,
+		})
+		if err != nil {
+			return err
+		}
+		if err := testAccDiagsError(read.Diagnostics); err != nil {
+			return err
+		}
+		attrs := make(map[string]string)
+		testAccFlatmap(attrs, "", testProtoUnmarshal(r.t, s, read.NewState))
+		imported = append(imported, &terraform.InstanceState{ID: attrs["id"], Attributes: attrs})
+	}
+	if step.ImportStateCheck != nil {
+		if err := step.ImportStateCheck(imported); err != nil {
+			return err
+		}
+	}
+	if !step.ImportStateVerify {
+		return nil
+	}
+
+	if len(imported) != 1 {
+		return fmt.Errorf("Expected one imported resource, got %d", len(imported))
+	}
+	actual := testAccVerifyAttributes(imported[0].Attributes, step.ImportStateVerifyIgnore)
+	expected := testAccVerifyAttributes(current.Attributes, step.ImportStateVerifyIgnore)
+	for k, v := range expected {
+		if actual[k] != v {
+			return fmt.Errorf("ImportStateVerify attributes not equivalent: %s is %q after import, expected %q", k, actual[k], v)
+		}
+	}
+	for k, v := range actual {
+		if _, ok := expected[k]; !ok {
+			return fmt.Errorf("ImportStateVerify attributes not equivalent: %s is %q after import, expected none", k, v)
+		}
+	}
+	return nil
+}
+
+// testAccVerifyAttributes returns the attributes compared by an import step,
+// like resource.Test does: empty containers, timeouts and the ignored
+// attributes are left out.
+func testAccVerifyAttributes(attrs map[string]string, ignore []string) map[string]string {
+	out := make(map[string]string)
+	for k, v := range attrs {
+		if (strings.HasSuffix(k, ".#") || strings.HasSuffix(k, ".%")) && v == "0" {
+			continue
+		}
+		if k == "timeouts" || strings.HasPrefix(k, "timeouts.") {
+			continue
+		}
+		out[k] = v
+		for _, prefix := range ignore {
+			if strings.HasPrefix(k, prefix) {
+				delete(out, k)
+			}
+		}
+	}
+	return out
+}
+
+// destroy destroys the resources in reverse order of creation, and runs the
+// CheckDestroy function of the test case.
+func (r *testAccRunner) destroy(c resource.TestCase) {
+	t := r.t
+	t.Helper()
+	if r.server == nil {
+		return
+	}
+	state := r.state()
+	for i := len(r.order) - 1; i >= 0; i-- {
+		if err := r.destroyInstance(r.order[i]); err != nil {
+			t.Errorf("Error destroying %s: %s", r.order[i], err)
+			return
+		}
+	}
+	if c.CheckDestroy != nil {
+		if err := c.CheckDestroy(state); err != nil {
+			t.Errorf("Check destroy failed: %s", err)
+		}
+	}
+}
+
+// apply applies config. With planOnly set, it only plans it, and returns an
+// error if any resource would change.
+func (r *testAccRunner) apply(config string, planOnly bool) error {
+	blocks, err := testAccParse(config)
+	if err != nil {
+		return err
+	}
+
+	vars := make(map[string]cty.Value)
+	applied := make(map[string]bool)
+	outputs := make(map[string]cty.Value)
+	for _, b := range blocks {
+		switch b.Type {
+		case "variable":
+			attr, ok := b.Body.Attributes["default"]
+			if !ok {
+				return fmt.Errorf("No value for required variable %q", b.Labels[0])
+			}
+			v, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return diags
+			}
+			vars[b.Labels[0]] = v
+		case "output":
+			attr, ok := b.Body.Attributes["value"]
+			if !ok {
+				return fmt.Errorf("Missing value of output %q", b.Labels[0])
+			}
+			v, diags := attr.Expr.Value(r.evalContext(vars, applied, -1))
+			if diags.HasErrors() {
+				return diags
+			}
+			outputs[b.Labels[0]] = v
+		case "resource", "data":
+			if err := r.applyBlock(b, vars, applied, planOnly); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Resources that are no longer configured are destroyed, and data sources
+	// that are no longer configured are forgotten.
+	for i := len(r.order) - 1; i >= 0; i-- {
+		addr := r.order[i]
+		switch {
+		case applied[addr]:
+		case planOnly:
+			return fmt.Errorf("%s would be destroyed", addr)
+		default:
+			if err := r.destroyInstance(addr); err != nil {
+				return err
+			}
+		}
+	}
+	for addr, inst := range r.instances {
+		if inst.data && !applied[addr] {
+			delete(r.instances, addr)
+		}
+	}
+	if !planOnly {
+		r.outputs = outputs
+	}
+	return nil
+}
+
+// applyBlock applies or reads every instance of a resource or data source
+// block.
+func (r *testAccRunner) applyBlock(b *testAccBlock, vars map[string]cty.Value, applied map[string]bool, planOnly bool) error {
+	data := b.Type == "data"
+	typ, name := b.Labels[0], b.Labels[1]
+	schemas := r.schemas.ResourceSchemas
+	if data {
+		schemas = r.schemas.DataSourceSchemas
+	}
+	s, ok := schemas[typ]
+	if !ok {
+		return fmt.Errorf("The provider does not support %s type %q", b.Type, typ)
+	}
+
+	count, counted := 1, false
+	if attr, ok := b.Body.Attributes["count"]; ok {
+		v, diags := attr.Expr.Value(r.evalContext(vars, applied, -1))
+		if diags.HasErrors() {
+			return diags
+		}
+		n, err := convert.Convert(v, cty.Number)
+		if err != nil {
+			return fmt.Errorf("Invalid count argument of %s: %s", b.addr, err)
+		}
+		i, _ := n.AsBigFloat().Int64()
+		count, counted = int(i), true
+	}
+
+	for i := 0; i < count; i++ {
+		inst := &testAccInstance{data: data, typ: typ, name: name, index: i, counted: counted}
+		addr := b.addr
+		if counted {
+			addr = fmt.Sprintf("%s[%d]", addr, i)
+		}
+		ctx := r.evalContext(vars, applied, -1)
+		if counted {
+			ctx = r.evalContext(vars, applied, i)
+		}
+		config, err := testAccConfigValue(s, b, ctx)
+		if err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+		if data {
+			err = r.readDataSource(addr, inst, s, config)
+		} else {
+			err = r.applyResource(addr, inst, s, config, planOnly)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %s", addr, err)
+		}
+		applied[addr] = true
+	}
+	return nil
+}
+
+// readDataSource reads a data source instance.
+func (r *testAccRunner) readDataSource(addr string, inst *testAccInstance, s *tfprotov5.Schema, config tftypes.Value) error {
+	ctx := context.Background()
+	dv := testProtoValue(r.t, s, config)
+	validate, err := r.server.ValidateDataSourceConfig(ctx, &tfprotov5.ValidateDataSourceConfigRequest{TypeName: inst.typ, Config: dv})
+	if err != nil {
+		return err
+	}
+	if err := testAccDiagsError(validate.Diagnostics); err != nil {
+		return err
+	}
+	resp, err := r.server.ReadDataSource(ctx, &tfprotov5.ReadDataSourceRequest{TypeName: inst.typ, Config: dv})
+	if err != nil {
+		return err
+	}
+	if err := testAccDiagsError(resp.Diagnostics); err != nil {
+		return err
+	}
+	inst.value = testProtoUnmarshal(r.t, s, resp.State)
+	r.instances[addr] = inst
+	return nil
+}
+
+// applyResource plans a resource instance, and applies the plan. Resources
+// whose plan requires replacing them are destroyed before they are created
+// again.
+func (r *testAccRunner) applyResource(addr string, inst *testAccInstance, s *tfprotov5.Schema, config tftypes.Value, planOnly bool) error {
+	ctx := context.Background()
+	validate, err := r.server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{
+		TypeName: inst.typ,
+		Config:   testProtoValue(r.t, s, config),
+	})
+	if err != nil {
+		return err
+	}
+	if err := testAccDiagsError(validate.Diagnostics); err != nil {
+		return err
+	}
+
+	prior, ok := r.instances[addr]
+	if !ok {
+		prior = inst
+		prior.value = tftypes.NewValue(s.ValueType(), nil)
+	}
+	planned, replace, private, err := r.plan(prior, s, config)
+	if err != nil {
+		return err
+	}
+	switch {
+	case !prior.value.IsNull() && planned.Equal(prior.value):
+		return nil
+	case planOnly:
+		diff, _ := prior.value.Diff(planned)
+		return fmt.Errorf("%s would change: %v", addr, diff)
+	case !prior.value.IsNull() && len(replace) > 0:
+		if err := r.destroyInstance(addr); err != nil {
+			return err
+		}
+		prior = inst
+		prior.value = tftypes.NewValue(s.ValueType(), nil)
+		if planned, _, private, err = r.plan(prior, s, config); err != nil {
+			return err
+		}
+	}
+
+	resp, err := r.server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       inst.typ,
+		PriorState:     testProtoValue(r.t, s, prior.value),
+		PlannedState:   testProtoValue(r.t, s, planned),
+		Config:         testProtoValue(r.t, s, config),
+		PlannedPrivate: private,
+	})
+	if err != nil {
+		return err
+	}
+	created := prior.value.IsNull()
+	if v := testProtoUnmarshal(r.t, s, resp.NewState); !v.IsNull() {
+		inst.value = v
+		inst.private = resp.Private
+		r.instances[addr] = inst
+		if created {
+			r.order = append(r.order, addr)
+		}
+	}
+	return testAccDiagsError(resp.Diagnostics)
+}
+
+// plan plans the change of a resource instance from its prior state to
+// config. The proposed new state is computed like Terraform does: configured
+// attributes take their configured values, and computed attributes that are
+// not configured keep their prior values.
+func (r *testAccRunner) plan(prior *testAccInstance, s *tfprotov5.Schema, config tftypes.Value) (tftypes.Value, []*tftypes.AttributePath, []byte, error) {
+	var configured, previous map[string]tftypes.Value
+	if err := config.As(&configured); err != nil {
+		return tftypes.Value{}, nil, nil, err
+	}
+	if !prior.value.IsNull() {
+		if err := prior.value.As(&previous); err != nil {
+			return tftypes.Value{}, nil, nil, err
+		}
+	}
+	proposed := make(map[string]tftypes.Value, len(configured))
+	for k, v := range configured {
+		proposed[k] = v
+	}
+	for _, a := range s.Block.Attributes {
+		if a.Computed && configured[a.Name].IsNull() && previous != nil {
+			proposed[a.Name] = previous[a.Name]
+		}
+	}
+
+	resp, err := r.server.PlanResourceChange(context.Background(), &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         prior.typ,
+		PriorState:       testProtoValue(r.t, s, prior.value),
+		ProposedNewState: testProtoValue(r.t, s, tftypes.NewValue(s.ValueType(), proposed)),
+		Config:           testProtoValue(r.t, s, config),
+		PriorPrivate:     prior.private,
+	})
+	if err != nil {
+		return tftypes.Value{}, nil, nil, err
+	}
+	if err := testAccDiagsError(resp.Diagnostics); err != nil {
+		return tftypes.Value{}, nil, nil, err
+	}
+	return testProtoUnmarshal(r.t, s, resp.PlannedState), resp.RequiresReplace, resp.PlannedPrivate, nil
+}
+
+// destroyInstance destroys a resource instance and removes it from the
+// state.
+func (r *testAccRunner) destroyInstance(addr string) error {
+	inst := r.instances[addr]
+	s := r.schemas.ResourceSchemas[inst.typ]
+	null := testProtoValue(r.t, s, tftypes.NewValue(s.ValueType(), nil))
+	resp, err := r.server.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:       inst.typ,
+		PriorState:     testProtoValue(r.t, s, inst.value),
+		PlannedState:   null,
+		Config:         null,
+		PlannedPrivate: inst.private,
+	})
+	if err != nil {
+		return err
+	}
+	if err := testAccDiagsError(resp.Diagnostics); err != nil {
+		return err
+	}
+	delete(r.instances, addr)
+	for i, v := range r.order {
+		if v == addr {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// refresh refreshes the state of every resource instance.
+func (r *testAccRunner) refresh() error {
+	for _, addr := range r.order {
+		inst := r.instances[addr]
+		s := r.schemas.ResourceSchemas[inst.typ]
+		resp, err := r.server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+			TypeName:     inst.typ,
+			CurrentState: testProtoValue(r.t, s, inst.value),
+			Private:      inst.private,
+		})
+		if err != nil {
+			return err
+		}
+		if err := testAccDiagsError(resp.Diagnostics); err != nil {
+			return err
+		}
+		v := testProtoUnmarshal(r.t, s, resp.NewState)
+		if v.IsNull() {
+			return fmt.Errorf("%s no longer exists after refreshing it", addr)
+		}
+		inst.value = v
+		inst.private = resp.Private
+	}
+	return nil
+}
+
+// state returns the current state for the Check and CheckDestroy functions
+// of the test case, with attributes in the flatmap format that they expect.
+func (r *testAccRunner) state() *terraform.State {
+	state := terraform.NewState()
+	ms := state.RootModule()
+	for _, inst := range r.instances {
+		attrs := make(map[string]string)
+		testAccFlatmap(attrs, "", inst.value)
+		ms.Resources[inst.stateKey()] = &terraform.ResourceState{
+			Type: inst.typ,
+			Primary: &terraform.InstanceState{
+				ID:         attrs["id"],
+				Attributes: attrs,
+			},
+		}
+	}
+	for name, v := range r.outputs {
+		out, err := testAccOutputState(v)
+		if err != nil {
+			r.t.Fatalf("Output %s: %s", name, err)
+		}
+		ms.Outputs[name] = out
+	}
+	return state
+}
+
+// evalContext returns the context to evaluate expressions in, with the
+// variables, and the resource and data source instances applied so far. An
+// index of 0 or more is the count.index of a counted instance.
+func (r *testAccRunner) evalContext(vars map[string]cty.Value, applied map[string]bool, index int) *hcl.EvalContext {
+	// Instances are grouped by type and name. Counted instances are a tuple.
+	managed := make(map[string]map[string][]*testAccInstance)
+	data := make(map[string]map[string][]*testAccInstance)
+	for addr, inst := range r.instances {
+		if !applied[addr] {
+			continue
+		}
+		group := managed
+		if inst.data {
+			group = data
+		}
+		if group[inst.typ] == nil {
+			group[inst.typ] = make(map[string][]*testAccInstance)
+		}
+		group[inst.typ][inst.name] = append(group[inst.typ][inst.name], inst)
+	}
+	values := func(group map[string]map[string][]*testAccInstance) map[string]cty.Value {
+		out := make(map[string]cty.Value)
+		for typ, names := range group {
+			objs := make(map[string]cty.Value)
+			for name, insts := range names {
+				if !insts[0].counted {
+					objs[name] = testAccCty(insts[0].value)
+					continue
+				}
+				elems := make([]cty.Value, len(insts))
+				for _, inst := range insts {
+					elems[inst.index] = testAccCty(inst.value)
+				}
+				objs[name] = cty.TupleVal(elems)
+			}
+			out[typ] = cty.ObjectVal(objs)
+		}
+		return out
+	}
+
+	variables := values(managed)
+	variables["var"] = cty.ObjectVal(vars)
+	variables["data"] = cty.ObjectVal(values(data))
+	if index >= 0 {
+		variables["count"] = cty.ObjectVal(map[string]cty.Value{"index": cty.NumberIntVal(int64(index))})
+	}
+	return &hcl.EvalContext{
+		Variables: variables,
+		Functions: map[string]function.Function{
+			"element": stdlib.ElementFunc,
+			"length":  stdlib.LengthFunc,
+			"split":   stdlib.SplitFunc,
+		},
+	}
+}
+
+// testAccParse parses a configuration, and returns its blocks in the order
+// they must be evaluated in: variables first, and every other block after
+// the blocks that it refers to or depends on.
+func testAccParse(config string) ([]*testAccBlock, error) {
+	f, diags := hclsyntax.ParseConfig([]byte(config), "main.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var blocks []*testAccBlock
+	addrs := make(map[string]bool)
+	for _, b := range f.Body.(*hclsyntax.Body).Blocks {
+		tb := &testAccBlock{Block: b}
+		switch {
+		case b.Type == "variable" || b.Type == "output":
+			tb.addr = b.Type + "." + b.Labels[0]
+		case b.Type == "resource":
+			tb.addr = b.Labels[0] + "." + b.Labels[1]
+		case b.Type == "data":
+			tb.addr = "data." + b.Labels[0] + "." + b.Labels[1]
+		case b.Type == "provider" || b.Type == "terraform":
+			continue
+		default:
+			return nil, fmt.Errorf("Blocks of type %q are not supported without TF_ACC", b.Type)
+		}
+		for _, attr := range b.Body.Attributes {
+			traversals := attr.Expr.Variables()
+			if attr.Name == "depends_on" {
+				exprs, diags := hcl.ExprList(attr.Expr)
+				if diags.HasErrors() {
+					return nil, diags
+				}
+				for _, expr := range exprs {
+					traversal, diags := testAccDependsOn(expr)
+					if diags.HasErrors() {
+						return nil, diags
+					}
+					traversals = append(traversals, traversal)
+				}
+			}
+			for _, traversal := range traversals {
+				if dep := testAccTraversalAddr(traversal); dep != "" {
+					tb.deps = append(tb.deps, dep)
+				}
+			}
+		}
+		if len(b.Body.Blocks) > 0 {
+			return nil, fmt.Errorf("%s: nested blocks are not supported without TF_ACC", tb.addr)
+		}
+		blocks = append(blocks, tb)
+		addrs[tb.addr] = true
+	}
+
+	var sorted []*testAccBlock
+	done := make(map[string]bool)
+	for len(sorted) < len(blocks) {
+		progress := false
+		for _, b := range blocks {
+			if done[b.addr] {
+				continue
+			}
+			ready := true
+			for _, dep := range b.deps {
+				if !addrs[dep] {
+					return nil, fmt.Errorf("%s: reference to undeclared %s", b.addr, dep)
+				}
+				ready = ready && done[dep]
+			}
+			if ready {
+				sorted = append(sorted, b)
+				done[b.addr] = true
+				progress = true
+			}
+		}
+		if !progress {
+			return nil, fmt.Errorf("Cycle in the configuration")
+		}
+	}
+	return sorted, nil
+}
+
+// testAccDependsOn returns the traversal of a depends_on entry. Like
+// Terraform, it accepts the quoted references of Terraform 0.11 too.
+func testAccDependsOn(expr hcl.Expression) (hcl.Traversal, hcl.Diagnostics) {
+	if tmpl, ok := expr.(*hclsyntax.TemplateExpr); ok && tmpl.IsStringLiteral() {
+		v, diags := tmpl.Value(nil)
+		if diags.HasErrors() {
+			return nil, diags
+		}
+		return hclsyntax.ParseTraversalAbs([]byte(v.AsString()), tmpl.SrcRange.Filename, tmpl.SrcRange.Start)
+	}
+	return hcl.AbsTraversalForExpr(expr)
+}
+
+// testAccTraversalAddr returns the address of the block that a traversal
+// refers to, or "" if it does not refer to a block.
+func testAccTraversalAddr(traversal hcl.Traversal) string {
+	var names []string
+	for _, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		}
+	}
+	switch {
+	case len(names) < 2:
+		return ""
+	case names[0] == "count":
+		return ""
+	case names[0] == "data" && len(names) >= 3:
+		return strings.Join(names[:3], ".")
+	case names[0] == "var":
+		return "variable." + names[1]
+	}
+	return names[0] + "." + names[1]
+}
+
+// testAccConfigValue evaluates the arguments of a resource or data source
+// block into a value of the object described by s.
+func testAccConfigValue(s *tfprotov5.Schema, b *testAccBlock, ctx *hcl.EvalContext) (tftypes.Value, error) {
+	typ := s.ValueType().(tftypes.Object)
+	attrs := make(map[string]tftypes.Value)
+	for name, at := range typ.AttributeTypes {
+		attrs[name] = tftypes.NewValue(at, nil)
+	}
+	for _, bt := range s.Block.BlockTypes {
+		if bt.Nesting == tfprotov5.SchemaNestedBlockNestingModeList || bt.Nesting == tfprotov5.SchemaNestedBlockNestingModeSet {
+			attrs[bt.TypeName] = tftypes.NewValue(typ.AttributeTypes[bt.TypeName], []tftypes.Value{})
+		}
+	}
+
+	for name, attr := range b.Body.Attributes {
+		if testAccMetaArguments[name] {
+			continue
+		}
+		at, ok := typ.AttributeTypes[name]
+		if !ok {
+			return tftypes.Value{}, fmt.Errorf("An argument named %q is not expected here", name)
+		}
+		v, diags := attr.Expr.Value(ctx)
+		if diags.HasErrors() {
+			return tftypes.Value{}, diags
+		}
+		v, err := convert.Convert(v, testAccCtyType(at))
+		if err != nil {
+			return tftypes.Value{}, fmt.Errorf("Inappropriate value for attribute %q: %s", name, err)
+		}
+		attrs[name] = testAccTfValue(at, v)
+	}
+	return tftypes.NewValue(typ, attrs), nil
+}
+
+// testAccDiagsError returns the errors in diags as a single error, or nil if
+// there are none.
+func testAccDiagsError(diags []*tfprotov5.Diagnostic) error {
+	if err := testProtoDiagsError(diags); err != "" {
+		return fmt.Errorf("%s", err)
+	}
+	return nil
+}
+
+// testAccCtyType returns the cty type of a tftypes type.
+func testAccCtyType(typ tftypes.Type) cty.Type {
+	switch typ := typ.(type) {
+	case tftypes.List:
+		return cty.List(testAccCtyType(typ.ElementType))
+	case tftypes.Set:
+		return cty.Set(testAccCtyType(typ.ElementType))
+	case tftypes.Map:
+		return cty.Map(testAccCtyType(typ.ElementType))
+	case tftypes.Tuple:
+		elems := make([]cty.Type, len(typ.ElementTypes))
+		for i, e := range typ.ElementTypes {
+			elems[i] = testAccCtyType(e)
+		}
+		return cty.Tuple(elems)
+	case tftypes.Object:
+		attrs := make(map[string]cty.Type)
+		for k, a := range typ.AttributeTypes {
+			attrs[k] = testAccCtyType(a)
+		}
+		return cty.Object(attrs)
+	}
+	switch {
+	case typ.Is(tftypes.String):
+		return cty.String
+	case typ.Is(tftypes.Number):
+		return cty.Number
+	case typ.Is(tftypes.Bool):
+		return cty.Bool
+	}
+	return cty.DynamicPseudoType
+}
+
+// testAccCty returns a tftypes value as a cty value.
+func testAccCty(v tftypes.Value) cty.Value {
+	ty := testAccCtyType(v.Type())
+	switch {
+	case !v.IsKnown():
+		return cty.UnknownVal(ty)
+	case v.IsNull():
+		return cty.NullVal(ty)
+	case ty == cty.String:
+		var s string
+		v.As(&s)
+		return cty.StringVal(s)
+	case ty == cty.Number:
+		var n big.Float
+		v.As(&n)
+		return cty.NumberVal(&n)
+	case ty == cty.Bool:
+		var b bool
+		v.As(&b)
+		return cty.BoolVal(b)
+	case ty.IsMapType() || ty.IsObjectType():
+		var m map[string]tftypes.Value
+		v.As(&m)
+		elems := make(map[string]cty.Value, len(m))
+		for k, e := range m {
+			elems[k] = testAccCty(e)
+		}
+		switch {
+		case ty.IsObjectType():
+			return cty.ObjectVal(elems)
+		case len(elems) == 0:
+			return cty.MapValEmpty(ty.ElementType())
+		}
+		return cty.MapVal(elems)
+	}
+	var l []tftypes.Value
+	v.As(&l)
+	elems := make([]cty.Value, len(l))
+	for i, e := range l {
+		elems[i] = testAccCty(e)
+	}
+	switch {
+	case ty.IsTupleType():
+		return cty.TupleVal(elems)
+	case len(elems) == 0 && ty.IsSetType():
+		return cty.SetValEmpty(ty.ElementType())
+	case len(elems) == 0:
+		return cty.ListValEmpty(ty.ElementType())
+	case ty.IsSetType():
+		return cty.SetVal(elems)
+	}
+	return cty.ListVal(elems)
+}
+
+// testAccTfValue returns a cty value, converted to the cty type of typ
+// already, as a tftypes value of type typ.
+func testAccTfValue(typ tftypes.Type, v cty.Value) tftypes.Value {
+	switch {
+	case !v.IsKnown():
+		return tftypes.NewValue(typ, tftypes.UnknownValue)
+	case v.IsNull():
+		return tftypes.NewValue(typ, nil)
+	}
+	switch typ := typ.(type) {
+	case tftypes.List, tftypes.Set, tftypes.Tuple:
+		var elems []tftypes.Value
+		for i, it := 0, v.ElementIterator(); it.Next(); i++ {
+			_, e := it.Element()
+			switch typ := typ.(type) {
+			case tftypes.List:
+				elems = append(elems, testAccTfValue(typ.ElementType, e))
+			case tftypes.Set:
+				elems = append(elems, testAccTfValue(typ.ElementType, e))
+			case tftypes.Tuple:
+				elems = append(elems, testAccTfValue(typ.ElementTypes[i], e))
+			}
+		}
+		return tftypes.NewValue(typ, elems)
+	case tftypes.Map:
+		elems := make(map[string]tftypes.Value)
+		for it := v.ElementIterator(); it.Next(); {
+			k, e := it.Element()
+			elems[k.AsString()] = testAccTfValue(typ.ElementType, e)
+		}
+		return tftypes.NewValue(typ, elems)
+	case tftypes.Object:
+		attrs := make(map[string]tftypes.Value)
+		for k, a := range typ.AttributeTypes {
+			attrs[k] = testAccTfValue(a, v.GetAttr(k))
+		}
+		return tftypes.NewValue(typ, attrs)
+	}
+	switch {
+	case typ.Is(tftypes.String):
+		return tftypes.NewValue(typ, v.AsString())
+	case typ.Is(tftypes.Number):
+		return tftypes.NewValue(typ, v.AsBigFloat())
+	}
+	return tftypes.NewValue(typ, v.True())
+}
+
+// testAccFlatmap adds the attributes of v to attrs in the flatmap format,
+// under prefix. Null values are left out.
+func testAccFlatmap(attrs map[string]string, prefix string, v tftypes.Value) {
+	if !v.IsKnown() || v.IsNull() {
+		return
+	}
+	key := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch typ := v.Type().(type) {
+	case tftypes.Object, tftypes.Map:
+		var m map[string]tftypes.Value
+		v.As(&m)
+		if _, ok := typ.(tftypes.Map); ok {
+			attrs[key("%")] = strconv.Itoa(len(m))
+		}
+		for k, e := range m {
+			testAccFlatmap(attrs, key(k), e)
+		}
+		return
+	case tftypes.List, tftypes.Set, tftypes.Tuple:
+		var l []tftypes.Value
+		v.As(&l)
+		attrs[key("#")] = strconv.Itoa(len(l))
+		for i, e := range l {
+			testAccFlatmap(attrs, key(strconv.Itoa(i)), e)
+		}
+		return
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		v.As(&s)
+		attrs[prefix] = s
+	case v.Type().Is(tftypes.Number):
+		var n big.Float
+		v.As(&n)
+		attrs[prefix] = n.Text('f', -1)
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		v.As(&b)
+		attrs[prefix] = strconv.FormatBool(b)
+	}
+}
+
+// testAccOutputState returns the value of an output in the format of the
+// state that resource.Test builds from the JSON output of Terraform.
+func testAccOutputState(v cty.Value) (*terraform.OutputState, error) {
+	b, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return nil, err
+	}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var value interface{}
+	if err := d.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	out := &terraform.OutputState{Type: "string", Value: value}
+	switch value := value.(type) {
+	case []interface{}:
+		out.Type = "list"
+	case map[string]interface{}:
+		out.Type = "map"
+	case bool:
+		out.Value = strconv.FormatBool(value)
+	case json.Number:
+		out.Value = value.String()
+	}
+	return out, nil
+}
@@ -0,0 +1,134 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// The provider is being moved from terraform-plugin-sdk to
+// terraform-plugin-framework one resource at a time. During the transition,
+// both providers are served together by terraform-plugin-mux: resources that
+// have been moved are implemented by frameworkProvider, and all others by the
+// SDK provider returned by Provider.
+//
+// Only phpipam_vrf has been moved so far. phpipam_subnet and phpipam_address
+// are still implemented with the SDK, which cannot tell unset fields from
+// zero values, and clear removed fields through clearableFields until they
+// are moved as well.
+
+// ProviderServer returns the muxed provider server that serves both the SDK
+// and the framework providers.
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	return newProviderServer(ctx, Provider())
+}
+
+// newProviderServer returns the muxed provider server for the supplied SDK
+// provider. The framework provider shares the SDK provider's client.
+func newProviderServer(ctx context.Context, sdk *schema.Provider) (func() tfprotov5.ProviderServer, error) {
+	server, err := tf5muxserver.NewMuxServer(ctx,
+		// The SDK provider must come first, as the framework provider uses
+		// the client that it configures. The mux server configures providers
+		// one at a time, in this order.
+		sdk.GRPCProvider,
+		providerserver.NewProtocol5(newFrameworkProvider(sdk)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return server.ProviderServer, nil
+}
+
+// frameworkProvider is the part of the provider implemented with
+// terraform-plugin-framework.
+type frameworkProvider struct {
+	// The SDK provider that is served alongside this one.
+	sdk *schema.Provider
+}
+
+// newFrameworkProvider returns the framework provider served alongside the
+// supplied SDK provider.
+func newFrameworkProvider(sdk *schema.Provider) provider.Provider {
+	return &frameworkProvider{sdk: sdk}
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "phpipam"
+}
+
+// Schema returns the schema of the SDK provider, as the mux server requires
+// both providers to have the same schema. The configuration is validated by
+// the SDK provider.
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema.Attributes = make(map[string]fwschema.Attribute)
+	resp.Schema.Blocks = make(map[string]fwschema.Block)
+	for k, v := range p.sdk.Schema {
+		if r, ok := v.Elem.(*schema.Resource); ok {
+			attrs := make(map[string]fwschema.Attribute)
+			for l, w := range r.Schema {
+				attrs[l] = frameworkProviderAttribute(w)
+			}
+			resp.Schema.Blocks[k] = fwschema.ListNestedBlock{
+				Description:  v.Description,
+				NestedObject: fwschema.NestedBlockObject{Attributes: attrs},
+			}
+			continue
+		}
+		resp.Schema.Attributes[k] = frameworkProviderAttribute(v)
+	}
+}
+
+// frameworkProviderAttribute returns the framework equivalent of an attribute
+// of the SDK provider's schema.
+func frameworkProviderAttribute(s *schema.Schema) fwschema.Attribute {
+	switch s.Type {
+	case schema.TypeString:
+		return fwschema.StringAttribute{Optional: s.Optional, Required: s.Required, Sensitive: s.Sensitive, Description: s.Description}
+	case schema.TypeBool:
+		return fwschema.BoolAttribute{Optional: s.Optional, Required: s.Required, Sensitive: s.Sensitive, Description: s.Description}
+	case schema.TypeInt:
+		return fwschema.Int64Attribute{Optional: s.Optional, Required: s.Required, Sensitive: s.Sensitive, Description: s.Description}
+	case schema.TypeList:
+		var elem attr.Type = types.StringType
+		if s.Elem.(*schema.Schema).Type == schema.TypeInt {
+			elem = types.Int64Type
+		}
+		return fwschema.ListAttribute{ElementType: elem, Optional: s.Optional, Required: s.Required, Sensitive: s.Sensitive, Description: s.Description}
+	}
+	panic(fmt.Errorf("Unsupported provider attribute type %s - this is a bug", s.Type))
+}
+
+// Configure passes the client of the SDK provider, which has been configured
+// already, to the framework resources.
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	client, ok := p.sdk.Meta().(*ProviderPHPIPAMClient)
+	if !ok {
+		resp.Diagnostics.AddError("Provider not configured", "The PHPIPAM client has not been configured by the SDK provider. This is a bug in the provider.")
+		return
+	}
+	resp.ResourceData = client
+	resp.DataSourceData = client
+}
+
+// Resources returns the resources that have been moved to the framework. They
+// must be removed from the ResourcesMap of the SDK provider, as the mux server
+// requires each resource to be served by a single provider.
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newVRFResource,
+	}
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
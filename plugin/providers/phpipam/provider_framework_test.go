@@ -0,0 +1,439 @@
+package phpipam
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/fakephpipam"
+)
+
+// The testProto* functions drive resources through the muxed provider server
+// with the same protocol requests that Terraform sends, without needing a
+// Terraform binary. They are the equivalent of the testFake* functions for
+// resources implemented with terraform-plugin-framework, and work with SDK
+// resources as well.
+
+func TestProviderServer(t *testing.T) {
+	server, err := ProviderServer(context.Background())
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	resp, err := server().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if diags := testProtoDiagsError(resp.Diagnostics); diags != "" {
+		t.Fatalf("bad: %s", diags)
+	}
+	// Every resource is served by exactly one of the providers.
+	for _, name := range []string{"phpipam_vrf", "phpipam_subnet", "phpipam_address"} {
+		if _, ok := resp.ResourceSchemas[name]; !ok {
+			t.Errorf("expected resource %s to be served", name)
+		}
+	}
+	if _, ok := Provider().ResourcesMap["phpipam_vrf"]; ok {
+		t.Errorf("expected phpipam_vrf to be served by the framework provider only")
+	}
+}
+
+// testProtoProvider starts a new fake PHPIPAM server for a test, and returns
+// the muxed provider server configured for it, along with the provider
+// client. The fake server is shut down when the test finishes.
+func testProtoProvider(t *testing.T) (tfprotov5.ProviderServer, interface{}, *fakephpipam.Server) {
+	t.Helper()
+	ctx := context.Background()
+	s := fakephpipam.NewServer()
+	t.Cleanup(s.Close)
+	testFakeCustomFields(s)
+
+	sdk := Provider()
+	factory, err := newProviderServer(ctx, sdk)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	server := factory()
+	schemas, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	config := testProtoDynamicValue(t, schemas.Provider, map[string]interface{}{
+		"app_id":   fakephpipam.AppID,
+		"endpoint": s.Endpoint(),
+		"username": fakephpipam.Username,
+		"password": fakephpipam.Password,
+	})
+	resp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: config})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if diags := testProtoDiagsError(resp.Diagnostics); diags != "" {
+		t.Fatalf("bad: %s", diags)
+	}
+	return server, sdk.Meta(), s
+}
+
+// testProtoSchema returns the schema of the named resource.
+func testProtoSchema(t *testing.T, server tfprotov5.ProviderServer, name string) *tfprotov5.Schema {
+	t.Helper()
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	s, ok := resp.ResourceSchemas[name]
+	if !ok {
+		t.Fatalf("no schema for resource %s", name)
+	}
+	return s
+}
+
+// testProtoDiagsError returns the errors in diags as a single string.
+func testProtoDiagsError(diags []*tfprotov5.Diagnostic) string {
+	var errs []string
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			errs = append(errs, strings.TrimSpace(d.Summary+": "+d.Detail))
+		}
+	}
+	return strings.Join(errs, "; ")
+}
+
+// testProtoApply plans and applies raw as the configuration of the named
+// resource, on top of the existing state (a null value for a new resource).
+// It then refreshes the resource and ensures that a new plan is empty, and
+// returns the refreshed state.
+func testProtoApply(t *testing.T, server tfprotov5.ProviderServer, name string, state tftypes.Value, raw map[string]interface{}) tftypes.Value {
+	t.Helper()
+	state, err := testProtoApplyE(t, server, name, state, raw)
+	if err != "" {
+		t.Fatalf("%s: %s", name, err)
+	}
+
+	state = testProtoRefresh(t, server, name, state)
+	planned, err := testProtoPlan(t, server, name, state, raw)
+	if err != "" {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if !planned.Equal(state) {
+		diff, _ := state.Diff(planned)
+		t.Fatalf("%s: expected empty plan after apply, got %v", name, diff)
+	}
+	return state
+}
+
+// testProtoApplyE is like testProtoApply, but returns the apply error instead
+// of failing the test, and does not check the plan afterwards.
+func testProtoApplyE(t *testing.T, server tfprotov5.ProviderServer, name string, state tftypes.Value, raw map[string]interface{}) (tftypes.Value, string) {
+	t.Helper()
+	ctx := context.Background()
+	s := testProtoSchema(t, server, name)
+	planned, err := testProtoPlan(t, server, name, state, raw)
+	if err != "" {
+		return state, err
+	}
+	if !state.IsNull() && planned.Equal(state) {
+		return state, ""
+	}
+	resp, rerr := server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     name,
+		PriorState:   testProtoValue(t, s, state),
+		PlannedState: testProtoValue(t, s, planned),
+		Config:       testProtoDynamicValue(t, s, raw),
+	})
+	if rerr != nil {
+		t.Fatalf("%s: %s", name, rerr)
+	}
+	if err := testProtoDiagsError(resp.Diagnostics); err != "" {
+		return state, err
+	}
+	return testProtoUnmarshal(t, s, resp.NewState), ""
+}
+
+// testProtoPlan validates raw as the configuration of the named resource, and
+// plans it on top of the existing state. The proposed new state is computed
+// like Terraform does: configured attributes take their configured values,
+// and computed attributes that are not configured keep their prior values.
+func testProtoPlan(t *testing.T, server tfprotov5.ProviderServer, name string, state tftypes.Value, raw map[string]interface{}) (tftypes.Value, string) {
+	t.Helper()
+	planned, _, err := testProtoPlanReplace(t, server, name, state, raw)
+	return planned, err
+}
+
+// testProtoPlanReplace is like testProtoPlan, but also returns the names of
+// the attributes whose change requires replacing the resource.
+func testProtoPlanReplace(t *testing.T, server tfprotov5.ProviderServer, name string, state tftypes.Value, raw map[string]interface{}) (tftypes.Value, []string, string) {
+	t.Helper()
+	ctx := context.Background()
+	s := testProtoSchema(t, server, name)
+	config := testProtoDynamicValue(t, s, raw)
+
+	validate, err := server.ValidateResourceTypeConfig(ctx, &tfprotov5.ValidateResourceTypeConfigRequest{TypeName: name, Config: config})
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := testProtoDiagsError(validate.Diagnostics); err != "" {
+		return state, nil, err
+	}
+
+	prior := testProtoAttributes(t, state)
+	proposed := make(map[string]interface{})
+	for _, a := range s.Block.Attributes {
+		switch v, ok := raw[a.Name]; {
+		case ok && v != nil:
+			proposed[a.Name] = v
+		case a.Computed:
+			proposed[a.Name] = prior[a.Name]
+		}
+	}
+	resp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         name,
+		PriorState:       testProtoValue(t, s, state),
+		ProposedNewState: testProtoDynamicValue(t, s, proposed),
+		Config:           config,
+	})
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := testProtoDiagsError(resp.Diagnostics); err != "" {
+		return state, nil, err
+	}
+	var replace []string
+	for _, p := range resp.RequiresReplace {
+		replace = append(replace, p.String())
+	}
+	return testProtoUnmarshal(t, s, resp.PlannedState), replace, ""
+}
+
+// testProtoRefresh refreshes the state of the named resource.
+func testProtoRefresh(t *testing.T, server tfprotov5.ProviderServer, name string, state tftypes.Value) tftypes.Value {
+	t.Helper()
+	s := testProtoSchema(t, server, name)
+	resp, err := server.ReadResource(context.Background(), &tfprotov5.ReadResourceRequest{
+		TypeName:     name,
+		CurrentState: testProtoValue(t, s, state),
+	})
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := testProtoDiagsError(resp.Diagnostics); err != "" {
+		t.Fatalf("%s: %s", name, err)
+	}
+	return testProtoUnmarshal(t, s, resp.NewState)
+}
+
+// testProtoImport imports the named resource by ID and returns its state.
+func testProtoImport(t *testing.T, server tfprotov5.ProviderServer, name, id string) tftypes.Value {
+	t.Helper()
+	s := testProtoSchema(t, server, name)
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{TypeName: name, ID: id})
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := testProtoDiagsError(resp.Diagnostics); err != "" {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if len(resp.ImportedResources) != 1 {
+		t.Fatalf("%s: expected one imported resource, got %d", name, len(resp.ImportedResources))
+	}
+	return testProtoRefresh(t, server, name, testProtoUnmarshal(t, s, resp.ImportedResources[0].State))
+}
+
+// testProtoDestroy destroys the named resource.
+func testProtoDestroy(t *testing.T, server tfprotov5.ProviderServer, name string, state tftypes.Value) {
+	t.Helper()
+	s := testProtoSchema(t, server, name)
+	resp, err := server.ApplyResourceChange(context.Background(), &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     name,
+		PriorState:   testProtoValue(t, s, state),
+		PlannedState: testProtoDynamicValue(t, s, nil),
+		Config:       testProtoDynamicValue(t, s, nil),
+	})
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if err := testProtoDiagsError(resp.Diagnostics); err != "" {
+		t.Fatalf("%s: %s", name, err)
+	}
+}
+
+// testProtoNull returns the null state of the named resource, which is the
+// prior state of a new resource.
+func testProtoNull(t *testing.T, server tfprotov5.ProviderServer, name string) tftypes.Value {
+	t.Helper()
+	return tftypes.NewValue(testProtoSchema(t, server, name).ValueType(), nil)
+}
+
+// testProtoCheckAttrs checks that state has the expected attribute values.
+// Strings, ints, bools and maps of strings are supported, and nil expects a
+// null value.
+func testProtoCheckAttrs(t *testing.T, state tftypes.Value, expected map[string]interface{}) {
+	t.Helper()
+	attrs := testProtoAttributes(t, state)
+	for k, v := range expected {
+		got, ok := attrs[k]
+		switch {
+		case !ok:
+			t.Errorf("expected attribute %s, found none", k)
+		case v == nil && got != nil:
+			t.Errorf("expected %s to be null, got %#v", k, got)
+		case v != nil && !testProtoEqual(v, got):
+			t.Errorf("expected %s to be %#v, got %#v", k, v, got)
+		}
+	}
+}
+
+// testProtoEqual returns true if the expected value of an attribute equals
+// the value read by testProtoAttributes.
+func testProtoEqual(expected, got interface{}) bool {
+	m, ok := expected.(map[string]interface{})
+	if !ok {
+		return expected == got
+	}
+	g, ok := got.(map[string]interface{})
+	if !ok || len(g) != len(m) {
+		return false
+	}
+	for k, v := range m {
+		if g[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// testProtoAttributes returns the top level attributes of an object value as
+// Go values, with nil for null values. A null object has no attributes.
+func testProtoAttributes(t *testing.T, v tftypes.Value) map[string]interface{} {
+	t.Helper()
+	out := make(map[string]interface{})
+	if v.IsNull() {
+		return out
+	}
+	var attrs map[string]tftypes.Value
+	if err := v.As(&attrs); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	for k, a := range attrs {
+		out[k] = testProtoGoValue(t, a)
+	}
+	return out
+}
+
+// testProtoGoValue returns a Go value for v, with nil for null values.
+func testProtoGoValue(t *testing.T, v tftypes.Value) interface{} {
+	t.Helper()
+	if !v.IsKnown() {
+		t.Fatalf("unexpected unknown value %s", v)
+	}
+	if v.IsNull() {
+		return nil
+	}
+	switch {
+	case v.Type().Is(tftypes.String):
+		var s string
+		v.As(&s)
+		return s
+	case v.Type().Is(tftypes.Number):
+		var n big.Float
+		v.As(&n)
+		i, _ := n.Int64()
+		return int(i)
+	case v.Type().Is(tftypes.Bool):
+		var b bool
+		v.As(&b)
+		return b
+	case v.Type().Is(tftypes.Map{}), v.Type().Is(tftypes.Object{}):
+		var m map[string]tftypes.Value
+		v.As(&m)
+		out := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			out[k] = testProtoGoValue(t, e)
+		}
+		return out
+	}
+	var l []tftypes.Value
+	if err := v.As(&l); err != nil {
+		t.Fatalf("unsupported value %s: %s", v, err)
+	}
+	out := make([]interface{}, len(l))
+	for i, e := range l {
+		out[i] = testProtoGoValue(t, e)
+	}
+	return out
+}
+
+// testProtoDynamicValue returns raw as a value of the object described by s,
+// for use in requests. Attributes that are not in raw are null, and nested
+// blocks are empty. A nil raw is a null object.
+func testProtoDynamicValue(t *testing.T, s *tfprotov5.Schema, raw map[string]interface{}) *tfprotov5.DynamicValue {
+	t.Helper()
+	typ := s.ValueType()
+	if raw == nil {
+		return testProtoValue(t, s, tftypes.NewValue(typ, nil))
+	}
+	attrs := make(map[string]tftypes.Value)
+	for k, at := range typ.(tftypes.Object).AttributeTypes {
+		attrs[k] = testProtoNewValue(t, at, raw[k])
+	}
+	for _, b := range s.Block.BlockTypes {
+		if _, ok := raw[b.TypeName]; !ok {
+			attrs[b.TypeName] = tftypes.NewValue(typ.(tftypes.Object).AttributeTypes[b.TypeName], []tftypes.Value{})
+		}
+	}
+	return testProtoValue(t, s, tftypes.NewValue(typ, attrs))
+}
+
+// testProtoNewValue returns the Go value v as a value of type typ.
+func testProtoNewValue(t *testing.T, typ tftypes.Type, v interface{}) tftypes.Value {
+	t.Helper()
+	if v == nil {
+		return tftypes.NewValue(typ, nil)
+	}
+	switch typ := typ.(type) {
+	case tftypes.Map:
+		m := make(map[string]tftypes.Value)
+		for k, e := range v.(map[string]interface{}) {
+			m[k] = testProtoNewValue(t, typ.ElementType, e)
+		}
+		return tftypes.NewValue(typ, m)
+	case tftypes.List:
+		var l []tftypes.Value
+		for _, e := range v.([]interface{}) {
+			l = append(l, testProtoNewValue(t, typ.ElementType, e))
+		}
+		return tftypes.NewValue(typ, l)
+	case tftypes.Object:
+		m := make(map[string]tftypes.Value)
+		for k, at := range typ.AttributeTypes {
+			m[k] = testProtoNewValue(t, at, v.(map[string]interface{})[k])
+		}
+		return tftypes.NewValue(typ, m)
+	}
+	if i, ok := v.(int); ok {
+		return tftypes.NewValue(typ, new(big.Float).SetInt64(int64(i)))
+	}
+	return tftypes.NewValue(typ, v)
+}
+
+// testProtoValue returns v as a value for requests.
+func testProtoValue(t *testing.T, s *tfprotov5.Schema, v tftypes.Value) *tfprotov5.DynamicValue {
+	t.Helper()
+	dv, err := tfprotov5.NewDynamicValue(s.ValueType(), v)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	return &dv
+}
+
+// testProtoUnmarshal returns the value in a response.
+func testProtoUnmarshal(t *testing.T, s *tfprotov5.Schema, dv *tfprotov5.DynamicValue) tftypes.Value {
+	t.Helper()
+	v, err := dv.Unmarshal(s.ValueType())
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	return v
+}
@@ -1,22 +1,39 @@
 package phpipam
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/fakephpipam"
 )
 
 var testAccProvider *schema.Provider
-var testAccProviders map[string]*schema.Provider
+
+// testAccProtoV5ProviderFactories serves testAccProvider through the muxed
+// provider server, as the provider binary does.
+var testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"phpipam": func() (tfprotov5.ProviderServer, error) {
+		server, err := newProviderServer(context.Background(), testAccProvider)
+		if err != nil {
+			return nil, err
+		}
+		return server(), nil
+	},
+}
 
 const envErrMsg = `PHPIPAM_APP_ID, PHPIPAM_ENDPOINT_ADDR, PHPIPAM_PASSWORD, and PHPIPAM_USER_NAME must be set for acceptance tests`
 
 func init() {
 	testAccProvider = Provider()
-	testAccProviders = map[string]*schema.Provider{
-		"phpipam": testAccProvider,
-	}
 }
 
 func TestProvider(t *testing.T) {
@@ -29,7 +46,20 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+// testAccPreCheck checks that a PHPIPAM server is configured for an
+// acceptance test. Unless TF_ACC is set along with PHPIPAM_ENDPOINT_ADDR, it
+// starts a fake PHPIPAM server for the test and points the provider at it.
 func testAccPreCheck(t *testing.T) {
+	if os.Getenv(resource.EnvTfAcc) == "" || os.Getenv("PHPIPAM_ENDPOINT_ADDR") == "" {
+		s := fakephpipam.NewServer()
+		t.Cleanup(s.Close)
+		testFakeCustomFields(s)
+		t.Setenv("PHPIPAM_APP_ID", fakephpipam.AppID)
+		t.Setenv("PHPIPAM_ENDPOINT_ADDR", s.Endpoint())
+		t.Setenv("PHPIPAM_PASSWORD", fakephpipam.Password)
+		t.Setenv("PHPIPAM_USER_NAME", fakephpipam.Username)
+	}
+
 	switch {
 	case os.Getenv("PHPIPAM_APP_ID") == "":
 		t.Fatal(envErrMsg)
@@ -48,6 +78,221 @@ func testAccProviderMeta(t *testing.T) (interface{}, error) {
 	return providerConfigure(d)
 }
 
+// testFakeCustomFields adds the custom fields used by the tests to a fake
+// PHPIPAM server.
+func testFakeCustomFields(s *fakephpipam.Server) {
+	s.AddCustomField("addresses", "custom_CustomTestAddresses", "varchar(255)")
+	s.AddCustomField("addresses", "custom_CustomTestAddresses2", "varchar(255)")
+	s.AddCustomField("subnets", "custom_CustomTestSubnets", "varchar(255)")
+	s.AddCustomField("subnets", "custom_CustomTestSubnets2", "varchar(255)")
+	s.AddCustomField("vlans", "custom_CustomTestVLANs", "varchar(255)")
+}
+
+// testFakeProviderMeta starts a new fake PHPIPAM server for a test, and
+// returns a provider client configured for it. The server is shut down when
+// the test finishes.
+//
+// The testFake* functions drive resources and data sources through the same
+// plan and apply workflow that Terraform uses, without needing a Terraform
+// binary, so that they can run in a plain go test.
+func testFakeProviderMeta(t *testing.T) (interface{}, *fakephpipam.Server) {
+	t.Helper()
+	return testFakeProviderMetaRaw(t, nil)
+}
+
+// testFakeProviderMetaRaw is like testFakeProviderMeta, but adds raw to the
+// provider configuration.
+func testFakeProviderMetaRaw(t *testing.T, raw map[string]interface{}) (interface{}, *fakephpipam.Server) {
+	t.Helper()
+	s := fakephpipam.NewServer()
+	t.Cleanup(s.Close)
+	testFakeCustomFields(s)
+
+	config := map[string]interface{}{
+		"app_id":   fakephpipam.AppID,
+		"endpoint": s.Endpoint(),
+		"username": fakephpipam.Username,
+		"password": fakephpipam.Password,
+	}
+	for k, v := range raw {
+		config[k] = v
+	}
+	d := schema.TestResourceDataRaw(t, Provider().Schema, config)
+	meta, err := providerConfigure(d)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	return meta, s
+}
+
+// testFakeDiagsError returns the errors in diags as a single string.
+func testFakeDiagsError(diags diag.Diagnostics) string {
+	var errs []string
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			errs = append(errs, d.Summary)
+		}
+	}
+	return strings.Join(errs, "; ")
+}
+
+// testFakeApply plans and applies raw as the configuration of the named
+// resource, on top of the existing state (nil for a new resource). It then
+// refreshes the resource and ensures that a new plan is empty, and returns
+// the refreshed state.
+func testFakeApply(t *testing.T, meta interface{}, name string, state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceState {
+	t.Helper()
+	state, err := testFakeApplyE(meta, name, state, raw)
+	if err != "" {
+		t.Fatalf("%s: %s", name, err)
+	}
+
+	state = testFakeRefresh(t, meta, name, state)
+	r := Provider().ResourcesMap[name]
+	diff, derr := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(raw), meta)
+	if derr != nil {
+		t.Fatalf("%s: %s", name, derr)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Fatalf("%s: expected empty plan after apply, got %#v", name, diff.Attributes)
+	}
+	return state
+}
+
+// testFakeApplyE is like testFakeApply, but returns the apply error instead of
+// failing the test, and does not check the plan afterwards.
+func testFakeApplyE(meta interface{}, name string, state *terraform.InstanceState, raw map[string]interface{}) (*terraform.InstanceState, string) {
+	ctx := context.Background()
+	r := Provider().ResourcesMap[name]
+	cfg := terraform.NewResourceConfigRaw(raw)
+	if diags := r.Validate(cfg); diags.HasError() {
+		return state, testFakeDiagsError(diags)
+	}
+	diff, err := r.Diff(ctx, state, cfg, meta)
+	if err != nil {
+		return state, err.Error()
+	}
+	if diff == nil || diff.Empty() {
+		return state, ""
+	}
+	state, diags := r.Apply(ctx, state, diff, meta)
+	if diags.HasError() {
+		return state, testFakeDiagsError(diags)
+	}
+	return state, ""
+}
+
+// testFakeRefresh refreshes the state of the named resource.
+func testFakeRefresh(t *testing.T, meta interface{}, name string, state *terraform.InstanceState) *terraform.InstanceState {
+	t.Helper()
+	r := Provider().ResourcesMap[name]
+	state, diags := r.RefreshWithoutUpgrade(context.Background(), state, meta)
+	if diags.HasError() {
+		t.Fatalf("%s: %s", name, testFakeDiagsError(diags))
+	}
+	return state
+}
+
+// testFakeImport imports the named resource by ID and returns its state.
+func testFakeImport(t *testing.T, meta interface{}, name, id string) *terraform.InstanceState {
+	t.Helper()
+	r := Provider().ResourcesMap[name]
+	data, err := r.Importer.StateContext(context.Background(), r.Data(&terraform.InstanceState{ID: id}), meta)
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+	if len(data) != 1 {
+		t.Fatalf("%s: expected one imported resource, got %d", name, len(data))
+	}
+	return testFakeRefresh(t, meta, name, data[0].State())
+}
+
+// testFakeDestroy destroys the named resource.
+func testFakeDestroy(t *testing.T, meta interface{}, name string, state *terraform.InstanceState) {
+	t.Helper()
+	r := Provider().ResourcesMap[name]
+	if _, diags := r.Apply(context.Background(), state, &terraform.InstanceDiff{Destroy: true}, meta); diags.HasError() {
+		t.Fatalf("%s: %s", name, testFakeDiagsError(diags))
+	}
+}
+
+// testFakeReadDataSource reads the named data source with raw as its
+// configuration, and returns its state.
+func testFakeReadDataSource(t *testing.T, meta interface{}, name string, raw map[string]interface{}) *terraform.InstanceState {
+	t.Helper()
+	state, err := testFakeReadDataSourceE(meta, name, raw)
+	if err != "" {
+		t.Fatalf("data.%s: %s", name, err)
+	}
+	return state
+}
+
+// testFakeReadDataSourceE is like testFakeReadDataSource, but returns the
+// error instead of failing the test.
+func testFakeReadDataSourceE(meta interface{}, name string, raw map[string]interface{}) (*terraform.InstanceState, string) {
+	ctx := context.Background()
+	r := Provider().DataSourcesMap[name]
+	cfg := terraform.NewResourceConfigRaw(raw)
+	if diags := r.Validate(cfg); diags.HasError() {
+		return nil, testFakeDiagsError(diags)
+	}
+	diff, err := r.Diff(ctx, nil, cfg, meta)
+	if err != nil {
+		return nil, err.Error()
+	}
+	state, diags := r.ReadDataApply(ctx, diff, meta)
+	if diags.HasError() {
+		return nil, testFakeDiagsError(diags)
+	}
+	return state, ""
+}
+
+// testFakeCheckAttrs checks that state has the expected attribute values.
+func testFakeCheckAttrs(t *testing.T, state *terraform.InstanceState, expected map[string]string) {
+	t.Helper()
+	if state == nil {
+		t.Fatalf("expected state, got nil")
+	}
+	for k, v := range expected {
+		if state.Attributes[k] != v {
+			t.Errorf("expected %s to be %q, got %q", k, v, state.Attributes[k])
+		}
+	}
+}
+
+// testFakeCheckIDs checks that the list attribute attr in state holds
+// exactly the IDs of the supplied states, in order.
+func testFakeCheckIDs(t *testing.T, state *terraform.InstanceState, attr string, expected ...*terraform.InstanceState) {
+	t.Helper()
+	attrs := map[string]string{attr + ".#": strconv.Itoa(len(expected))}
+	for i, e := range expected {
+		attrs[fmt.Sprintf("%s.%d", attr, i)] = e.ID
+	}
+	testFakeCheckAttrs(t, state, attrs)
+}
+
+// testFakeSubnet creates a subnet in the tf-test section, creating the
+// section first if needed, and returns the ID of the subnet.
+func testFakeSubnet(t *testing.T, meta interface{}, address string, mask int) int {
+	t.Helper()
+	var sectionID int
+	if section, err := meta.(*ProviderPHPIPAMClient).sectionsController.GetSectionByName("tf-test"); err == nil {
+		sectionID = section.ID
+	} else {
+		state := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+			"name": "tf-test",
+		})
+		sectionID, _ = strconv.Atoi(state.ID)
+	}
+	subnet := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": address,
+		"subnet_mask":    mask,
+	})
+	subnetID, _ := strconv.Atoi(subnet.ID)
+	return subnetID
+}
+
 func sectionSweep(sectionName string, t *testing.T) error {
 	meta, err := testAccProviderMeta(t)
 	if err != nil {
@@ -93,3 +338,123 @@ func l2domainSweep(l2domainName string, t *testing.T) error {
 
 	return nil
 }
+
+func vrfSweep(vrfName string, t *testing.T) error {
+	meta, err := testAccProviderMeta(t)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	c := meta.(*ProviderPHPIPAMClient).vrfsController
+	vrfs, err := c.GetVRFsByName(vrfName)
+	switch {
+	case err != nil && err.Error() == "Error from API (404): No results (filter applied)":
+		return nil
+	case err != nil:
+		t.Fatalf("bad: %s", err)
+	}
+
+	for _, v := range vrfs {
+		if _, err := c.DeleteVRF(v.ID); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func deviceSweep(hostname string, t *testing.T) error {
+	meta, err := testAccProviderMeta(t)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	c := meta.(*ProviderPHPIPAMClient).devicesController
+	devices, err := c.GetDevicesByHostname(hostname)
+	switch {
+	case err != nil && err.Error() == "Error from API (404): No results (filter applied)":
+		return nil
+	case err != nil:
+		t.Fatalf("bad: %s", err)
+	}
+
+	for _, v := range devices {
+		if _, err := c.DeleteDevice(v.ID); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func locationSweep(locationName string, t *testing.T) error {
+	meta, err := testAccProviderMeta(t)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	c := meta.(*ProviderPHPIPAMClient).locationsController
+	locations, err := c.GetLocationsByName(locationName)
+	switch {
+	case err != nil && err.Error() == "Error from API (404): No results (filter applied)":
+		return nil
+	case err != nil:
+		t.Fatalf("bad: %s", err)
+	}
+
+	for _, v := range locations {
+		if _, err := c.DeleteLocation(v.ID); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func nameserverSweep(nameserverName string, t *testing.T) error {
+	meta, err := testAccProviderMeta(t)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	c := meta.(*ProviderPHPIPAMClient).nameserversController
+	nameservers, err := c.GetNameserversByName(nameserverName)
+	switch {
+	case err != nil && err.Error() == "Error from API (404): No results (filter applied)":
+		return nil
+	case err != nil:
+		t.Fatalf("bad: %s", err)
+	}
+
+	for _, v := range nameservers {
+		if _, err := c.DeleteNameserver(v.ID); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+	}
+
+	return nil
+}
+
+func addressTagSweep(tagName string, t *testing.T) error {
+	meta, err := testAccProviderMeta(t)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	c := meta.(*ProviderPHPIPAMClient).tagsController
+	tags, err := c.GetTagsByName(tagName)
+	switch {
+	case err != nil && err.Error() == "Error from API (404): No results (filter applied)":
+		return nil
+	case err != nil:
+		t.Fatalf("bad: %s", err)
+	}
+
+	for _, v := range tags {
+		if _, err := c.DeleteTag(v.ID); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+	}
+
+	return nil
+}
@@ -1,11 +1,14 @@
 package phpipam
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
 	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
 )
 
@@ -16,13 +19,14 @@ import (
 // read workflow is identical for both the resource and the data source.
 func resourcePHPIPAMAddress() *schema.Resource {
 	return &schema.Resource{
-		Create: resourcePHPIPAMAddressCreate,
-		Read:   dataSourcePHPIPAMAddressRead,
-		Update: resourcePHPIPAMAddressUpdate,
-		Delete: resourcePHPIPAMAddressDelete,
-		Schema: resourceAddressSchema(),
+		Create:        resourcePHPIPAMAddressCreate,
+		Read:          dataSourcePHPIPAMAddressRead,
+		Update:        resourcePHPIPAMAddressUpdate,
+		Delete:        resourcePHPIPAMAddressDelete,
+		CustomizeDiff: resourcePHPIPAMAddressCustomizeDiff,
+		Schema:        resourceAddressSchema(),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importNaturalKey(importAddressID),
 		},
 	}
 }
@@ -31,24 +35,8 @@ func resourcePHPIPAMAddressCreate(d *schema.ResourceData, meta interface{}) erro
 	client := meta.(*ProviderPHPIPAMClient)
 	c := client.addressesController
 
-	// Get the next free address if no IP is specified.
-	if d.Get("ip_address").(string) == "" {
-		// By default Terraform runs operations in parallel. Protect the
-		// GetFirstFreeAddress and CreateAddress operations with a lock so they are
-		// not run concurrently.
-		client.addressAllocationLock.Lock()
-		defer client.addressAllocationLock.Unlock()
-
-		subnet_c := client.subnetsController
-		out, err := subnet_c.GetFirstFreeAddress(d.Get("subnet_id").(int))
-		if err != nil {
-			return err
-		}
-		if out == "" {
-			return errors.New("Subnet has no free IP addresses")
-		}
-
-		d.Set("ip_address", out)
+	if err := resolveAddressStateTag(d, meta); err != nil {
+		return err
 	}
 
 	in := expandAddress(d)
@@ -56,8 +44,79 @@ func resourcePHPIPAMAddressCreate(d *schema.ResourceData, meta interface{}) erro
 	// Assert the ID field here is empty. If this is not empty the request will fail.
 	in.ID = 0
 
-	if _, err := c.CreateAddress(in); err != nil {
-		return err
+	// Adopt the existing address with the same IP address in the subnet, if
+	// requested.
+	if in.IPAddress != "" && d.Get("adopt_existing").(bool) {
+		out, err := c.GetAddressesByIpInSubnet(in.IPAddress, in.SubnetID)
+		switch {
+		case err == nil:
+			d.SetId(strconv.Itoa(out.ID))
+			d.Set("address_id", out.ID)
+			return resourcePHPIPAMAddressUpdate(d, meta)
+		case apiErrorCode(err) != 404:
+			return err
+		}
+	}
+
+	// Reuse the address with the same allocation key, if there is one, so
+	// that the address is recreated with the same IP address.
+	if in.IPAddress == "" && d.Get("allocation_key").(string) != "" {
+		existing, err := addressByAllocationKey(d, meta)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			log.Printf("[DEBUG] Reusing IP address %s, ID %d, with the same %s", existing.IPAddress, existing.ID, d.Get("allocation_key").(string))
+			d.SetId(strconv.Itoa(existing.ID))
+			d.Set("ip_address", existing.IPAddress)
+			in.ID = existing.ID
+			in.SubnetID = 0
+			if _, err := c.UpdateAddress(in); err != nil {
+				return err
+			}
+			if clear := resourceAddressClearableFields.updates(d); len(clear) > 0 {
+				if _, err := c.UpdateAddressFields(existing.ID, clear); err != nil {
+					return err
+				}
+			}
+			if customFields, ok := d.GetOk("custom_fields"); ok {
+				if _, err := c.UpdateAddressCustomFields(existing.ID, customFields.(map[string]interface{})); err != nil {
+					return err
+				}
+			}
+			if err := resourceAddressClearableFields.setConfigured(d); err != nil {
+				return err
+			}
+			return dataSourcePHPIPAMAddressRead(d, meta)
+		}
+	}
+
+	if in.IPAddress != "" {
+		if _, err := c.CreateAddress(in); err != nil {
+			return err
+		}
+	} else {
+		// Allocate the next free address if no IP is specified. By default
+		// Terraform runs operations in parallel, so the GetFirstFreeAddress and
+		// CreateAddress operations are protected against concurrent
+		// allocations.
+		out, err := client.allocateAddress(in.SubnetID, in, func(in addresses.Address) (string, error) {
+			out, err := client.subnetsController.GetFirstFreeAddress(in.SubnetID)
+			if err != nil {
+				return "", err
+			}
+			if out == "" {
+				return "", errors.New("Subnet has no free IP addresses")
+			}
+			in.IPAddress = out
+			_, err = c.CreateAddress(in)
+			return out, err
+		})
+		if err != nil {
+			return err
+		}
+		in.IPAddress = out
+		d.Set("ip_address", out)
 	}
 
 	// If we have custom fields, set them now. We need to get the IP address's ID
@@ -79,11 +138,18 @@ func resourcePHPIPAMAddressCreate(d *schema.ResourceData, meta interface{}) erro
 		}
 	}
 
+	if err := resourceAddressClearableFields.setConfigured(d); err != nil {
+		return err
+	}
 	return dataSourcePHPIPAMAddressRead(d, meta)
 }
 
 func resourcePHPIPAMAddressUpdate(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*ProviderPHPIPAMClient).addressesController
+	if err := resolveAddressStateTag(d, meta); err != nil {
+		return err
+	}
+
 	in := expandAddress(d)
 
 	// IPAddress and SubnetID need to be removed for update requests.
@@ -92,21 +158,64 @@ func resourcePHPIPAMAddressUpdate(d *schema.ResourceData, meta interface{}) erro
 	if _, err := c.UpdateAddress(in); err != nil {
 		return err
 	}
+	// UpdateAddress omits empty values, so fields that are cleared are sent
+	// separately.
+	if clear := resourceAddressClearableFields.updates(d); len(clear) > 0 {
+		if _, err := c.UpdateAddressFields(in.ID, clear); err != nil {
+			return err
+		}
+	}
 
 	if err := updateCustomFields(d, c); err != nil {
 		return err
 	}
 
+	if err := resourceAddressClearableFields.setConfigured(d); err != nil {
+		return err
+	}
 	return dataSourcePHPIPAMAddressRead(d, meta)
 }
 
+// resourcePHPIPAMAddressCustomizeDiff plans clearing the optional fields that
+// are removed from the configuration, see resourceAddressClearableFields.
+func resourcePHPIPAMAddressCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	return resourceAddressClearableFields.customizeDiff(d)
+}
+
 func resourcePHPIPAMAddressDelete(d *schema.ResourceData, meta interface{}) error {
 	c := meta.(*ProviderPHPIPAMClient).addressesController
 	in := expandAddress(d)
 
+	// With keep_on_destroy, the address is kept, so that it is reused through
+	// its allocation key when the resource is created again. When the
+	// resource is replaced with create_before_destroy, the new resource has
+	// already reused the address by the time the old one is destroyed.
+	if d.Get("keep_on_destroy").(bool) {
+		log.Printf("[DEBUG] Keeping IP address ID %d, as keep_on_destroy is set", in.ID)
+		d.SetId("")
+		return nil
+	}
+
 	if _, err := c.DeleteAddress(in.ID, phpipam.BoolIntString(d.Get("remove_dns_on_delete").(bool))); err != nil {
 		return err
 	}
 	d.SetId("")
 	return nil
 }
+
+// resolveAddressStateTag looks up the ID of the tag supplied in state_tag, if
+// it has been set or changed, and sets it in state_tag_id so that it is picked
+// up by expandAddress.
+func resolveAddressStateTag(d *schema.ResourceData, meta interface{}) error {
+	name := d.Get("state_tag").(string)
+	if name == "" || !d.HasChange("state_tag") {
+		return nil
+	}
+
+	id, err := addressTagIDByName(meta.(*ProviderPHPIPAMClient).tagsController, name)
+	if err != nil {
+		return err
+	}
+	d.Set("state_tag_id", id)
+	return nil
+}
@@ -0,0 +1,139 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+)
+
+// resourcePHPIPAMAddressBlock returns the resource structure for the
+// phpipam_address_block resource, which allocates several free IP addresses
+// in a subnet at once.
+//
+// The ID of the resource is the comma-separated list of its address IDs,
+// which is also the format used to import a block.
+func resourcePHPIPAMAddressBlock() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourcePHPIPAMAddressBlockCreate,
+		Read:          resourcePHPIPAMAddressBlockRead,
+		Update:        resourcePHPIPAMAddressBlockUpdate,
+		Delete:        resourcePHPIPAMAddressBlockDelete,
+		Schema:        resourceAddressBlockSchema(),
+		CustomizeDiff: resourcePHPIPAMAddressBlockCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourcePHPIPAMAddressBlockCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderPHPIPAMClient)
+	in := expandAddressBlock(d)
+
+	block, err := client.allocateAddressBlock(in.SubnetID, d.Get("address_count").(int), d.Get("contiguous").(bool), in)
+	if err != nil {
+		return err
+	}
+	flattenAddressBlock(block, d)
+
+	if err := resourceAddressBlockClearableFields.setConfigured(d); err != nil {
+		return err
+	}
+	return resourcePHPIPAMAddressBlockRead(d, meta)
+}
+
+func resourcePHPIPAMAddressBlockRead(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	ids, err := addressBlockIDs(d.Id())
+	if err != nil {
+		return err
+	}
+
+	var block []addresses.Address
+	for _, id := range ids {
+		out, err := c.GetAddressByID(id)
+		switch {
+		case err != nil && apiErrorCode(err) == 404:
+			log.Printf("[WARN] IP address ID %d of address block %s not found", id, d.Id())
+			continue
+		case err != nil:
+			return err
+		}
+		block = append(block, out)
+	}
+
+	if len(block) == 0 {
+		log.Printf("[WARN] Address block %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	// If some addresses have been deleted outside of Terraform, the block
+	// shrinks, which forces it to be replaced with a full one.
+	flattenAddressBlock(block, d)
+	return nil
+}
+
+func resourcePHPIPAMAddressBlockUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	in := expandAddressBlock(d)
+
+	// IPAddress and SubnetID need to be removed for update requests.
+	in.SubnetID = 0
+	// UpdateAddress omits empty values, so fields that are cleared are sent
+	// separately.
+	clear := resourceAddressBlockClearableFields.updates(d)
+	if d.HasChanges("description", "owner", "note") || len(clear) > 0 {
+		for _, v := range d.Get("address_ids").([]interface{}) {
+			in.ID = v.(int)
+			if _, err := c.UpdateAddress(in); err != nil {
+				return fmt.Errorf("Could not update IP address ID %d of address block: %s", in.ID, err)
+			}
+			if len(clear) == 0 {
+				continue
+			}
+			if _, err := c.UpdateAddressFields(in.ID, clear); err != nil {
+				return fmt.Errorf("Could not update IP address ID %d of address block: %s", in.ID, err)
+			}
+		}
+	}
+
+	if err := resourceAddressBlockClearableFields.setConfigured(d); err != nil {
+		return err
+	}
+	return resourcePHPIPAMAddressBlockRead(d, meta)
+}
+
+// resourcePHPIPAMAddressBlockCustomizeDiff plans clearing the optional fields
+// that are removed from the configuration, see
+// resourceAddressBlockClearableFields, and forces a new block when contiguous
+// changes.
+//
+// Imported blocks do not know whether their addresses were allocated
+// contiguously, so they take the configured value without being replaced.
+func resourcePHPIPAMAddressBlockCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := resourceAddressBlockClearableFields.customizeDiff(d); err != nil {
+		return err
+	}
+	if d.Id() == "" || !d.HasChange("contiguous") {
+		return nil
+	}
+	if raw := d.GetRawState(); raw.IsNull() || raw.GetAttr("contiguous").IsNull() {
+		return nil
+	}
+	return d.ForceNew("contiguous")
+}
+
+func resourcePHPIPAMAddressBlockDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+
+	for _, v := range d.Get("address_ids").([]interface{}) {
+		if _, err := c.DeleteAddress(v.(int), false); err != nil && apiErrorCode(err) != 404 {
+			return err
+		}
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,235 @@
+package phpipam
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/fakephpipam"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+)
+
+func TestResourcePHPIPAMAddressBlock(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	for _, ip := range []string{"10.10.1.2", "10.10.1.5"} {
+		testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+			"subnet_id":  subnetID,
+			"ip_address": ip,
+		})
+	}
+
+	config := map[string]interface{}{
+		"subnet_id":     subnetID,
+		"address_count": 3,
+		"description":   "Terraform test address block",
+	}
+	state := testFakeApply(t, meta, "phpipam_address_block", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_id":      strconv.Itoa(subnetID),
+		"address_count":  "3",
+		"description":    "Terraform test address block",
+		"ip_addresses.#": "3",
+		"ip_addresses.0": "10.10.1.1",
+		"ip_addresses.1": "10.10.1.3",
+		"ip_addresses.2": "10.10.1.4",
+		"address_ids.0":  "3",
+		"address_ids.2":  "5",
+	})
+	if state.ID != "3,4,5" {
+		t.Fatalf("expected ID 3,4,5, got %s", state.ID)
+	}
+
+	config["description"] = "Terraform test address block, step 2"
+	config["owner"] = "tf-test"
+	state = testFakeApply(t, meta, "phpipam_address_block", state, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"description": "Terraform test address block, step 2",
+		"owner":       "tf-test",
+	})
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	if a, err := c.GetAddressByID(5); err != nil || a.Owner != "tf-test" {
+		t.Fatalf("expected all addresses to be updated, got %v, %v", a, err)
+	}
+
+	imported := testFakeImport(t, meta, "phpipam_address_block", state.ID)
+	testFakeCheckAttrs(t, imported, map[string]string{
+		"subnet_id":      strconv.Itoa(subnetID),
+		"address_count":  "3",
+		"ip_addresses.1": "10.10.1.3",
+	})
+
+	// An address deleted outside of Terraform shrinks the block, which forces
+	// it to be replaced.
+	if _, err := c.DeleteAddress(4, false); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	refreshed := testFakeRefresh(t, meta, "phpipam_address_block", state)
+	testFakeCheckAttrs(t, refreshed, map[string]string{
+		"address_count":  "2",
+		"ip_addresses.1": "10.10.1.4",
+	})
+
+	testFakeDestroy(t, meta, "phpipam_address_block", refreshed)
+	if _, err := c.GetAddressesByIP("10.10.1.1"); err == nil || err.Error() != "Error from API (404): Address not found" {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+}
+
+func TestResourcePHPIPAMAddressBlockContiguous(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 29)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.3",
+	})
+
+	config := map[string]interface{}{
+		"subnet_id":     subnetID,
+		"address_count": 3,
+		"contiguous":    true,
+	}
+	state := testFakeApply(t, meta, "phpipam_address_block", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"ip_addresses.#": "3",
+		"ip_addresses.0": "10.10.1.4",
+		"ip_addresses.2": "10.10.1.6",
+	})
+
+	config["address_count"] = 4
+	_, err := testFakeApplyE(meta, "phpipam_address_block", nil, config)
+	if !strings.Contains(err, "does not have 4 contiguous free IP addresses") {
+		t.Fatalf("expected no contiguous free addresses error, got %q", err)
+	}
+}
+
+func TestResourcePHPIPAMAddressBlockContiguousReplace(t *testing.T) {
+	server, meta, _ := testProtoProvider(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 29)
+
+	config := map[string]interface{}{
+		"subnet_id":     subnetID,
+		"address_count": 2,
+	}
+	state := testProtoApply(t, server, "phpipam_address_block", testProtoNull(t, server, "phpipam_address_block"), config)
+
+	// Changing contiguous forces a new block.
+	config["contiguous"] = true
+	_, replace, err := testProtoPlanReplace(t, server, "phpipam_address_block", state, config)
+	if err != "" {
+		t.Fatalf("bad: %s", err)
+	}
+	if !strings.Contains(strings.Join(replace, ","), `"contiguous"`) {
+		t.Fatalf("expected contiguous to require replacement, got %v", replace)
+	}
+
+	// Imported blocks take the configured value without being replaced.
+	imported := testProtoImport(t, server, "phpipam_address_block", testProtoAttributes(t, state)["id"].(string))
+	_, replace, err = testProtoPlanReplace(t, server, "phpipam_address_block", imported, config)
+	if err != "" {
+		t.Fatalf("bad: %s", err)
+	}
+	if len(replace) != 0 {
+		t.Fatalf("expected no replacement after import, got %v", replace)
+	}
+	state = testProtoApply(t, server, "phpipam_address_block", imported, config)
+	testProtoCheckAttrs(t, state, map[string]interface{}{
+		"contiguous": true,
+	})
+}
+
+func TestResourcePHPIPAMAddressBlockClearFields(t *testing.T) {
+	// Removed fields are only planned through the provider protocol, which
+	// supplies the configuration to the plan.
+	server, meta, _ := testProtoProvider(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+
+	config := map[string]interface{}{
+		"subnet_id":     subnetID,
+		"address_count": 2,
+		"description":   "Terraform test address block",
+		"owner":         "tf-test",
+		"note":          "Terraform test note",
+	}
+	state := testProtoApply(t, server, "phpipam_address_block", testProtoNull(t, server, "phpipam_address_block"), config)
+
+	// Removing the fields from the configuration clears them on every
+	// address.
+	delete(config, "description")
+	delete(config, "owner")
+	delete(config, "note")
+	state = testProtoApply(t, server, "phpipam_address_block", state, config)
+	testProtoCheckAttrs(t, state, map[string]interface{}{
+		"description": "",
+		"owner":       "",
+		"note":        "",
+	})
+	for _, id := range testProtoAttributes(t, state)["address_ids"].([]interface{}) {
+		out, err := c.GetAddressByID(id.(int))
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		if out.Description != "" || out.Owner != "" || out.Note != "" {
+			t.Fatalf("expected fields to be cleared, got %#v", out)
+		}
+	}
+}
+
+func TestResourcePHPIPAMAddressBlockRollback(t *testing.T) {
+	meta, s := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	var posts int
+	s.OnRequest(func(r *http.Request) {
+		if r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/addresses/") {
+			if posts++; posts == 3 {
+				s.FailNext(fakephpipam.Failure{Code: 400, Message: "Invalid owner"})
+			}
+		}
+	})
+	_, err := testFakeApplyE(meta, "phpipam_address_block", nil, map[string]interface{}{
+		"subnet_id":     subnetID,
+		"address_count": 5,
+	})
+	if err != "Error from API (400): Invalid owner" {
+		t.Fatalf("expected create error, got %q", err)
+	}
+
+	addrs, aerr := meta.(*ProviderPHPIPAMClient).subnetsController.GetAddressesInSubnet(subnetID)
+	if aerr == nil || apiErrorCode(aerr) != 404 {
+		t.Fatalf("expected partial reservations to be deleted, got %v, %v", addrs, aerr)
+	}
+}
+
+func TestFreeAddresses(t *testing.T) {
+	existing := []addresses.Address{{IPAddress: "10.0.0.1"}, {IPAddress: "10.0.0.3"}}
+	cases := []struct {
+		cidr       string
+		n          int
+		contiguous bool
+		expected   string
+	}{
+		{"10.0.0.0/29", 3, false, "10.0.0.2,10.0.0.4,10.0.0.5"},
+		{"10.0.0.0/29", 3, true, "10.0.0.4,10.0.0.5,10.0.0.6"},
+		{"10.0.0.0/29", 4, false, "10.0.0.2,10.0.0.4,10.0.0.5,10.0.0.6"},
+		{"10.0.0.0/29", 5, false, ""},
+		{"10.0.0.0/29", 4, true, ""},
+		{"10.0.0.0/31", 2, false, "10.0.0.0,10.0.0.1"},
+		{"2001:db8::/126", 4, true, "2001:db8::,2001:db8::1,2001:db8::2,2001:db8::3"},
+	}
+	for _, tc := range cases {
+		var e []addresses.Address
+		if strings.HasPrefix(tc.cidr, "10.0.0.0/29") {
+			e = existing
+		}
+		out, err := freeAddresses(tc.cidr, e, tc.n, tc.contiguous)
+		switch {
+		case tc.expected == "" && err == nil:
+			t.Errorf("%s, %d: expected error, got %v", tc.cidr, tc.n, out)
+		case tc.expected != "" && strings.Join(out, ",") != tc.expected:
+			t.Errorf("%s, %d: expected %s, got %v, %v", tc.cidr, tc.n, tc.expected, out, err)
+		}
+	}
+}
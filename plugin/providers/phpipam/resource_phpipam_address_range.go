@@ -0,0 +1,230 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+)
+
+// resourcePHPIPAMAddressRange returns the resource structure for the
+// phpipam_address_range resource, which manages every address from a start
+// to an end address in a subnet as a unit, such as a DHCP scope.
+//
+// The ID of the resource is subnetID:start-end, which is also the format used
+// to import a range.
+func resourcePHPIPAMAddressRange() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourcePHPIPAMAddressRangeCreate,
+		Read:          resourcePHPIPAMAddressRangeRead,
+		Update:        resourcePHPIPAMAddressRangeUpdate,
+		Delete:        resourcePHPIPAMAddressRangeDelete,
+		CustomizeDiff: resourcePHPIPAMAddressRangeCustomizeDiff,
+		Schema:        resourceAddressRangeSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourcePHPIPAMAddressRangeCreate(d *schema.ResourceData, meta interface{}) error {
+	if err := resolveAddressStateTag(d, meta); err != nil {
+		return err
+	}
+	in := expandAddressRange(d)
+	start, end := d.Get("start").(string), d.Get("end").(string)
+
+	created, err := createAddressRangeAddresses(meta, in, start, end, d.Get("hostname_template").(string), nil)
+	if err != nil {
+		return err
+	}
+	d.SetId(addressRangeID(in.SubnetID, start, end))
+	d.Set("address_ids", addressRangeIDs(nil, created))
+
+	if err := resourceAddressRangeClearableFields.setConfigured(d); err != nil {
+		return err
+	}
+	return resourcePHPIPAMAddressRangeRead(d, meta)
+}
+
+func resourcePHPIPAMAddressRangeRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*ProviderPHPIPAMClient)
+	subnetID, start, end, err := parseAddressRangeID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	list, err := client.subnetsController.GetAddressesInSubnet(subnetID)
+	if err != nil && apiErrorCode(err) != 404 {
+		return err
+	}
+	list = addressesInRange(list, start, end)
+	// Only the addresses that the range has created belong to it, so that
+	// addresses created by others in the range, such as in place of
+	// addresses deleted outside of Terraform, are neither managed nor
+	// deleted by the range. Recreating the missing addresses then fails with
+	// a conflict instead. Imported ranges have no address IDs yet, and take
+	// every address in the range.
+	if owned := d.Get("address_ids").([]interface{}); len(owned) > 0 {
+		list = addressesWithIDs(list, owned)
+	}
+	if len(list) == 0 {
+		log.Printf("[WARN] Address range %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	// If some addresses have been deleted outside of Terraform, the range
+	// shrinks, and resourcePHPIPAMAddressRangeCustomizeDiff plans to create
+	// them again.
+	flattenAddressRange(subnetID, start, end, list, d)
+
+	if list[0].Tag != 0 {
+		tag, err := client.tagsController.GetTagByID(list[0].Tag)
+		if err != nil {
+			return err
+		}
+		d.Set("state_tag", tag.Type)
+	} else {
+		d.Set("state_tag", "")
+	}
+	return nil
+}
+
+func resourcePHPIPAMAddressRangeUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	if err := resolveAddressStateTag(d, meta); err != nil {
+		return err
+	}
+	in := expandAddressRange(d)
+	start, end := d.Get("start").(string), d.Get("end").(string)
+
+	// The addresses in the range are only known before the update, as
+	// resourcePHPIPAMAddressRangeCustomizeDiff marks them as computed when
+	// addresses are missing.
+	oldIPs, _ := d.GetChange("ip_addresses")
+	oldIDs, _ := d.GetChange("address_ids")
+	byIP := make(map[string]int)
+	present := make(map[string]bool)
+	for i, v := range oldIPs.([]interface{}) {
+		byIP[v.(string)] = oldIDs.([]interface{})[i].(int)
+		present[v.(string)] = true
+	}
+
+	// Create the addresses that have been deleted outside of Terraform first,
+	// so that they are created with the new fields.
+	template := d.Get("hostname_template").(string)
+	ids := oldIDs.([]interface{})
+	if d.HasChange("address_count") {
+		created, err := createAddressRangeAddresses(meta, in, start, end, template, present)
+		if err != nil {
+			return err
+		}
+		ids = addressRangeIDs(ids, created)
+	}
+	d.Set("address_ids", ids)
+
+	// IPAddress and SubnetID need to be removed for update requests.
+	in.SubnetID = 0
+	// UpdateAddress omits empty values, so fields that are cleared are sent
+	// separately.
+	clear := resourceAddressRangeClearableFields.updates(d)
+	if d.HasChanges("description", "owner", "note", "state_tag", "hostname_template") || len(clear) > 0 {
+		ips, err := addressRange(start, end)
+		if err != nil {
+			return err
+		}
+		for n, ip := range ips {
+			id, ok := byIP[ip.String()]
+			if !ok {
+				continue
+			}
+			in.ID = id
+			in.Hostname = addressRangeHostname(template, n+1)
+			if _, err := c.UpdateAddress(in); err != nil {
+				return fmt.Errorf("Could not update IP address %s of address range: %s", ip, err)
+			}
+			if len(clear) == 0 {
+				continue
+			}
+			if _, err := c.UpdateAddressFields(id, clear); err != nil {
+				return fmt.Errorf("Could not update IP address %s of address range: %s", ip, err)
+			}
+		}
+	}
+
+	if err := resourceAddressRangeClearableFields.setConfigured(d); err != nil {
+		return err
+	}
+	return resourcePHPIPAMAddressRangeRead(d, meta)
+}
+
+func resourcePHPIPAMAddressRangeDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+
+	for _, v := range d.Get("address_ids").([]interface{}) {
+		if _, err := c.DeleteAddress(v.(int), false); err != nil && apiErrorCode(err) != 404 {
+			return err
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourcePHPIPAMAddressRangeCustomizeDiff plans clearing the optional fields
+// that are removed from the configuration, see
+// resourceAddressRangeClearableFields, and plans to create the addresses of a
+// range that have been deleted outside of Terraform, by setting address_count
+// back to the size of the range.
+func resourcePHPIPAMAddressRangeCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if err := resourceAddressRangeClearableFields.customizeDiff(d); err != nil {
+		return err
+	}
+	if d.Id() == "" || d.HasChanges("subnet_id", "start", "end") {
+		return nil
+	}
+	ips, err := addressRange(d.Get("start").(string), d.Get("end").(string))
+	if err != nil {
+		return err
+	}
+	if d.Get("address_count").(int) != len(ips) {
+		if err := d.SetNew("address_count", len(ips)); err != nil {
+			return err
+		}
+		for _, k := range []string{"ip_addresses", "address_ids", "hostnames"} {
+			if err := d.SetNewComputed(k); err != nil {
+				return err
+			}
+		}
+	}
+	if d.HasChange("hostname_template") {
+		return d.SetNewComputed("hostnames")
+	}
+	return nil
+}
+
+// createAddressRangeAddresses creates the addresses of the range from start to
+// end in the subnet of in, skipping those in present, and returns them. Host
+// names are set from template, by their position in the range.
+func createAddressRangeAddresses(meta interface{}, in addresses.Address, start, end, template string, present map[string]bool) ([]addresses.Address, error) {
+	client := meta.(*ProviderPHPIPAMClient)
+	subnet, err := client.subnetsController.GetSubnetByID(in.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	all, err := addressRangeInSubnet(fmt.Sprintf("%s/%d", subnet.SubnetAddress, subnet.Mask), start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var ips, hostnames []string
+	for n, ip := range all {
+		if present[ip] {
+			continue
+		}
+		ips = append(ips, ip)
+		hostnames = append(hostnames, addressRangeHostname(template, n+1))
+	}
+	return client.createAddressRange(in.SubnetID, ips, hostnames, in)
+}
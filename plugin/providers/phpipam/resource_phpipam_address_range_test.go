@@ -0,0 +1,213 @@
+package phpipam
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestResourcePHPIPAMAddressRange(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	config := map[string]interface{}{
+		"subnet_id":         subnetID,
+		"start":             "10.10.1.100",
+		"end":               "10.10.1.103",
+		"description":       "DHCP scope",
+		"hostname_template": "dhcp-{n}",
+	}
+	state := testFakeApply(t, meta, "phpipam_address_range", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_id":      strconv.Itoa(subnetID),
+		"state_tag":      "Reserved",
+		"state_tag_id":   "3",
+		"description":    "DHCP scope",
+		"address_count":  "4",
+		"ip_addresses.0": "10.10.1.100",
+		"ip_addresses.3": "10.10.1.103",
+		"hostnames.0":    "dhcp-1",
+		"hostnames.3":    "dhcp-4",
+		"address_ids.#":  "4",
+	})
+	if expected := strconv.Itoa(subnetID) + ":10.10.1.100-10.10.1.103"; state.ID != expected {
+		t.Fatalf("expected ID %s, got %s", expected, state.ID)
+	}
+
+	config["state_tag"] = "DHCP"
+	config["hostname_template"] = "pool-{n}"
+	state = testFakeApply(t, meta, "phpipam_address_range", state, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"state_tag":   "DHCP",
+		"hostnames.1": "pool-2",
+	})
+
+	imported := testFakeImport(t, meta, "phpipam_address_range", state.ID)
+	testFakeCheckAttrs(t, imported, map[string]string{
+		"subnet_id":      strconv.Itoa(subnetID),
+		"start":          "10.10.1.100",
+		"end":            "10.10.1.103",
+		"address_count":  "4",
+		"ip_addresses.2": "10.10.1.102",
+		"state_tag":      "DHCP",
+	})
+
+	// An address deleted outside of Terraform is created again.
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	id, _ := strconv.Atoi(state.Attributes["address_ids.1"])
+	if _, err := c.DeleteAddress(id, false); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	state = testFakeRefresh(t, meta, "phpipam_address_range", state)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"address_count": "3",
+	})
+	state = testFakeApply(t, meta, "phpipam_address_range", state, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"address_count":  "4",
+		"ip_addresses.1": "10.10.1.101",
+		"hostnames.1":    "pool-2",
+		"state_tag":      "DHCP",
+	})
+
+	testFakeDestroy(t, meta, "phpipam_address_range", state)
+	addrs, err := meta.(*ProviderPHPIPAMClient).subnetsController.GetAddressesInSubnet(subnetID)
+	if err == nil || apiErrorCode(err) != 404 {
+		t.Fatalf("expected all addresses to be deleted, got %v, %v", addrs, err)
+	}
+}
+
+func TestResourcePHPIPAMAddressRangeConflict(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.102",
+	})
+
+	_, err := testFakeApplyE(meta, "phpipam_address_range", nil, map[string]interface{}{
+		"subnet_id": subnetID,
+		"start":     "10.10.1.100",
+		"end":       "10.10.1.103",
+	})
+	if !strings.Contains(err, "conflicts with existing IP addresses: 10.10.1.102") {
+		t.Fatalf("expected conflict error, got %q", err)
+	}
+	addrs, _ := meta.(*ProviderPHPIPAMClient).subnetsController.GetAddressesInSubnet(subnetID)
+	if len(addrs) != 1 {
+		t.Fatalf("expected no addresses to be created, got %v", addrs)
+	}
+
+	_, err = testFakeApplyE(meta, "phpipam_address_range", nil, map[string]interface{}{
+		"subnet_id": subnetID,
+		"start":     "10.10.1.250",
+		"end":       "10.10.1.255",
+	})
+	if !strings.Contains(err, "is not within the host addresses of subnet 10.10.1.0/24") {
+		t.Fatalf("expected subnet error, got %q", err)
+	}
+}
+
+func TestResourcePHPIPAMAddressRangeForeignAddress(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	config := map[string]interface{}{
+		"subnet_id": subnetID,
+		"start":     "10.10.1.100",
+		"end":       "10.10.1.102",
+	}
+	state := testFakeApply(t, meta, "phpipam_address_range", nil, config)
+
+	// An address created by others in place of an address of the range that
+	// was deleted outside of Terraform does not become part of the range.
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	id, _ := strconv.Atoi(state.Attributes["address_ids.1"])
+	if _, err := c.DeleteAddress(id, false); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	foreign := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.101",
+	})
+	state = testFakeRefresh(t, meta, "phpipam_address_range", state)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"address_count":  "2",
+		"address_ids.#":  "2",
+		"ip_addresses.1": "10.10.1.102",
+	})
+
+	// Recreating the missing address fails, and destroying the range leaves
+	// the foreign address alone.
+	_, err := testFakeApplyE(meta, "phpipam_address_range", state, config)
+	if !strings.Contains(err, "conflicts with existing IP addresses: 10.10.1.101 (address ID "+foreign.ID+")") {
+		t.Fatalf("expected conflict error, got %q", err)
+	}
+	testFakeDestroy(t, meta, "phpipam_address_range", state)
+	addrs, _ := meta.(*ProviderPHPIPAMClient).subnetsController.GetAddressesInSubnet(subnetID)
+	if len(addrs) != 1 || strconv.Itoa(addrs[0].ID) != foreign.ID {
+		t.Fatalf("expected only the foreign address to be left, got %v", addrs)
+	}
+}
+
+func TestResourcePHPIPAMAddressRangeClearFields(t *testing.T) {
+	// Removed fields are only planned through the provider protocol, which
+	// supplies the configuration to the plan.
+	server, meta, _ := testProtoProvider(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+
+	config := map[string]interface{}{
+		"subnet_id":   subnetID,
+		"start":       "10.10.1.100",
+		"end":         "10.10.1.101",
+		"description": "DHCP scope",
+		"owner":       "tf-test",
+		"note":        "Terraform test note",
+	}
+	state := testProtoApply(t, server, "phpipam_address_range", testProtoNull(t, server, "phpipam_address_range"), config)
+
+	// Removing the fields from the configuration clears them on every
+	// address.
+	delete(config, "description")
+	delete(config, "owner")
+	delete(config, "note")
+	state = testProtoApply(t, server, "phpipam_address_range", state, config)
+	testProtoCheckAttrs(t, state, map[string]interface{}{
+		"description": "",
+		"owner":       "",
+		"note":        "",
+	})
+	for _, id := range testProtoAttributes(t, state)["address_ids"].([]interface{}) {
+		out, err := c.GetAddressByID(id.(int))
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		if out.Description != "" || out.Owner != "" || out.Note != "" {
+			t.Fatalf("expected fields to be cleared, got %#v", out)
+		}
+	}
+}
+
+func TestParseAddressRangeID(t *testing.T) {
+	cases := []struct {
+		id       string
+		expected string
+	}{
+		{"3:10.0.0.10-10.0.0.20", "3 10.0.0.10 10.0.0.20"},
+		{"4:2001:db8::10-2001:db8::1f", "4 2001:db8::10 2001:db8::1f"},
+		{"3:10.0.0.20-10.0.0.10", ""},
+		{"3:10.0.0.10", ""},
+		{"10.0.0.10-10.0.0.20", ""},
+		{"3:10.0.0.10-2001:db8::1", ""},
+	}
+	for _, tc := range cases {
+		subnetID, start, end, err := parseAddressRangeID(tc.id)
+		out := strconv.Itoa(subnetID) + " " + start + " " + end
+		switch {
+		case tc.expected == "" && err == nil:
+			t.Errorf("%s: expected error, got %s", tc.id, out)
+		case tc.expected != "" && out != tc.expected:
+			t.Errorf("%s: expected %s, got %s, %v", tc.id, tc.expected, out, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePHPIPAMAddressTag returns the resource structure for the
+// phpipam_address_tag resource.
+//
+// Note that we use the data source read function here to pull down data, as
+// read workflow is identical for both the resource and the data source.
+func resourcePHPIPAMAddressTag() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePHPIPAMAddressTagCreate,
+		Read:   dataSourcePHPIPAMAddressTagRead,
+		Update: resourcePHPIPAMAddressTagUpdate,
+		Delete: resourcePHPIPAMAddressTagDelete,
+		Schema: resourceAddressTagSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourcePHPIPAMAddressTagCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).tagsController
+	in := expandAddressTag(d)
+
+	// Assert the ID field here is empty. If this is not empty the request will fail.
+	in.ID = 0
+
+	if _, err := c.CreateTag(in); err != nil {
+		return err
+	}
+
+	// The API does not return the ID of the new tag, so look it up by name.
+	tags, err := c.GetTagsByName(in.Type)
+	if err != nil {
+		return fmt.Errorf("Could not read address tag after creating: %s", err)
+	}
+
+	if len(tags) != 1 {
+		return errors.New("Address tag either missing or multiple results returned by reading tag after creation")
+	}
+
+	d.SetId(strconv.Itoa(tags[0].ID))
+	d.Set("tag_id", tags[0].ID)
+
+	return dataSourcePHPIPAMAddressTagRead(d, meta)
+}
+
+func resourcePHPIPAMAddressTagUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).tagsController
+	in := expandAddressTag(d)
+
+	if _, err := c.UpdateTag(in); err != nil {
+		return err
+	}
+
+	return dataSourcePHPIPAMAddressTagRead(d, meta)
+}
+
+func resourcePHPIPAMAddressTagDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).tagsController
+	in := expandAddressTag(d)
+
+	if d.Get("locked").(bool) {
+		return fmt.Errorf("Address tag %s is a built-in tag and cannot be deleted", in.Type)
+	}
+
+	if _, err := c.DeleteTag(in.ID); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,135 @@
+package phpipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourcePHPIPAMAddressTagResourceName = "phpipam_address_tag.tag"
+const testAccResourcePHPIPAMAddressTagName = "tf-test-tag"
+const testAccResourcePHPIPAMAddressTagConfig = `
+resource "phpipam_address_tag" "tag" {
+  name             = "tf-test-tag"
+  show_tag         = true
+  background_color = "#ff8800"
+  foreground_color = "#ffffff"
+}
+`
+
+const testAccResourcePHPIPAMAddressTagAddressConfig = `
+resource "phpipam_address_tag" "tag" {
+  name             = "tf-test-tag"
+  show_tag         = true
+  background_color = "#ff8800"
+  foreground_color = "#000000"
+  compress         = true
+  update_on_scan   = true
+}
+
+resource "phpipam_section" "section" {
+  name        = "tf-test"
+  description = "Terraform test section"
+}
+
+resource "phpipam_subnet" "subnet" {
+  section_id     = phpipam_section.section.section_id
+  subnet_address = "10.10.1.0"
+  subnet_mask    = 24
+}
+
+resource "phpipam_address" "address" {
+  subnet_id  = phpipam_subnet.subnet.subnet_id
+  ip_address = "10.10.1.10"
+  hostname   = "tf-test.cust1.local"
+  state_tag  = phpipam_address_tag.tag.name
+}
+`
+
+func TestAccResourcePHPIPAMAddressTag(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			sectionSweep("tf-test", t)
+			addressTagSweep("tf-test-tag", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMAddressTagDeleted,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMAddressTagConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMAddressTagCreated,
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "name", "tf-test-tag"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "show_tag", "true"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "background_color", "#ff8800"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "foreground_color", "#ffffff"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "compress", "false"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "locked", "false"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMAddressTagAddressConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMAddressTagCreated,
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "foreground_color", "#000000"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "compress", "true"),
+					resource.TestCheckResourceAttr("phpipam_address_tag.tag", "update_on_scan", "true"),
+					resource.TestCheckResourceAttr("phpipam_address.address", "state_tag", "tf-test-tag"),
+					resource.TestCheckResourceAttrPair("phpipam_address_tag.tag", "tag_id", "phpipam_address.address", "state_tag_id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "phpipam_address_tag.tag",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourcePHPIPAMAddressTagCreated(s *terraform.State) error {
+	r, ok := s.RootModule().Resources[testAccResourcePHPIPAMAddressTagResourceName]
+	if !ok {
+		return fmt.Errorf("Resource name %s could not be found", testAccResourcePHPIPAMAddressTagResourceName)
+	}
+	if r.Primary.ID == "" {
+		return errors.New("No ID is set")
+	}
+
+	id, _ := strconv.Atoi(r.Primary.ID)
+
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).tagsController
+	if _, err := c.GetTagByID(id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func testAccCheckResourcePHPIPAMAddressTagDeleted(s *terraform.State) error {
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).tagsController
+	_, err := c.GetTagsByName(testAccResourcePHPIPAMAddressTagName)
+	switch {
+	case err == nil:
+		return errors.New("Expected error, got none")
+	case err != nil && err.Error() != "Error from API (404): No results (filter applied)":
+		return fmt.Errorf("Expected 404, got %s", err)
+	}
+
+	return nil
+}
+
+func TestResourcePHPIPAMAddressTagLocked(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	locked := testFakeImport(t, meta, "phpipam_address_tag", "1")
+	r := Provider().ResourcesMap["phpipam_address_tag"]
+	if _, diags := r.Apply(context.Background(), locked, &terraform.InstanceDiff{Destroy: true}, meta); !diags.HasError() {
+		t.Fatal("Expected error deleting locked tag, got none")
+	}
+}
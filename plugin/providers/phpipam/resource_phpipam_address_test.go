@@ -3,7 +3,10 @@ package phpipam
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -104,13 +107,13 @@ resource "phpipam_address" "address" {
 `
 
 func TestAccResourcePHPIPAMAddress(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMAddressDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMAddressDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMAddressConfig,
@@ -121,18 +124,24 @@ func TestAccResourcePHPIPAMAddress(t *testing.T) {
 					resource.TestCheckResourceAttr("phpipam_address.address", "hostname", "tf-test.cust1.local"),
 				),
 			},
+			resource.TestStep{
+				ResourceName:            "phpipam_address.address",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopt_existing", "configured_fields", "remove_dns_on_delete"},
+			},
 		},
 	})
 }
 
 func TestAccResourcePHPIPAMOptionalAddress(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMAddressDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMAddressDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMOptionalAddressConfig,
@@ -147,13 +156,13 @@ func TestAccResourcePHPIPAMOptionalAddress(t *testing.T) {
 }
 
 func TestAccResourcePHPIPAMAddress_CustomFields(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMAddressDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMAddressDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMAddressCustomFieldConfig,
@@ -209,3 +218,296 @@ func testAccCheckResourcePHPIPAMAddressDeleted(s *terraform.State) error {
 
 	return nil
 }
+
+func TestResourcePHPIPAMAddressClearFields(t *testing.T) {
+	// Removed fields are only planned through the provider protocol, which
+	// supplies the configuration to the plan.
+	server, meta, _ := testProtoProvider(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	fields := map[string]interface{}{
+		"description": "Terraform test address",
+		"hostname":    "tf-test.cust1.local",
+		"mac_address": "00:11:22:33:44:55",
+		"is_gateway":  true,
+	}
+	empty := map[string]interface{}{
+		"description": "",
+		"hostname":    "",
+		"mac_address": "",
+		"is_gateway":  false,
+	}
+	config := map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.10",
+		"state_tag":  "Reserved",
+	}
+	for k, v := range fields {
+		config[k] = v
+	}
+	state := testProtoApply(t, server, "phpipam_address", testProtoNull(t, server, "phpipam_address"), config)
+	testProtoCheckAttrs(t, state, fields)
+	id := testProtoAttributes(t, state)["address_id"].(int)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+
+	// Removing the fields from the configuration clears them. Fields that are
+	// not clearable, such as the state tag, keep their values.
+	for k := range fields {
+		delete(config, k)
+	}
+	delete(config, "state_tag")
+	state = testProtoApply(t, server, "phpipam_address", state, config)
+	testProtoCheckAttrs(t, state, empty)
+	testProtoCheckAttrs(t, state, map[string]interface{}{
+		"state_tag": "Reserved",
+	})
+	out, err := c.GetAddressByID(id)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if out.Description != "" || out.Hostname != "" || out.MACAddress != "" || out.IsGateway {
+		t.Fatalf("expected fields to be cleared, got %#v", out)
+	}
+
+	// Setting the fields to their zero values clears them as well.
+	for k, v := range fields {
+		config[k] = v
+	}
+	state = testProtoApply(t, server, "phpipam_address", state, config)
+	testProtoCheckAttrs(t, state, fields)
+	for k, v := range empty {
+		config[k] = v
+	}
+	state = testProtoApply(t, server, "phpipam_address", state, config)
+	testProtoCheckAttrs(t, state, empty)
+	out, err = c.GetAddressByID(id)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if out.Description != "" || out.Hostname != "" || out.MACAddress != "" || out.IsGateway {
+		t.Fatalf("expected fields to be cleared, got %#v", out)
+	}
+}
+
+func TestResourcePHPIPAMAddressKeepUnconfiguredFields(t *testing.T) {
+	server, meta, _ := testProtoProvider(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	check := func(id int) {
+		t.Helper()
+		out, err := c.GetAddressByID(id)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		if out.Hostname != "scanned.cust1.local" || out.MACAddress != "00:11:22:33:44:55" {
+			t.Fatalf("expected fields set outside Terraform to be kept, got %#v", out)
+		}
+	}
+
+	// Fields set outside Terraform, such as by scans, which have never been
+	// configured, are kept when the address is updated.
+	config := map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.10",
+	}
+	state := testProtoApply(t, server, "phpipam_address", testProtoNull(t, server, "phpipam_address"), config)
+	id := testProtoAttributes(t, state)["address_id"].(int)
+	if _, err := c.UpdateAddressFields(id, map[string]interface{}{
+		"hostname": "scanned.cust1.local",
+		"mac":      "00:11:22:33:44:55",
+	}); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	state = testProtoRefresh(t, server, "phpipam_address", state)
+	config["description"] = "Terraform test address"
+	testProtoApply(t, server, "phpipam_address", state, config)
+	check(id)
+
+	// Adopting the address keeps them as well.
+	testProtoApply(t, server, "phpipam_address", testProtoNull(t, server, "phpipam_address"), map[string]interface{}{
+		"subnet_id":      subnetID,
+		"ip_address":     "10.10.1.10",
+		"adopt_existing": true,
+	})
+	check(id)
+}
+
+// testAddressPath matches the path of a request for a single address by ID.
+var testAddressPath = regexp.MustCompile(`/addresses/([0-9]+)/$`)
+
+func TestResourcePHPIPAMAddressAllocationKey(t *testing.T) {
+	meta, server := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id": subnetID,
+		"hostname":  "other.cust1.local",
+	})
+
+	config := map[string]interface{}{
+		"subnet_id":       subnetID,
+		"hostname":        "vm1.cust1.local",
+		"description":     "Terraform test address",
+		"allocation_key":  "hostname",
+		"keep_on_destroy": true,
+	}
+	state := testFakeApply(t, meta, "phpipam_address", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"ip_address": "10.10.1.2",
+	})
+
+	// With the state lost, the address with the same host name is reused and
+	// updated, instead of allocating the next free address.
+	config["description"] = "Terraform test address, recreated"
+	recreated := testFakeApply(t, meta, "phpipam_address", nil, config)
+	testFakeCheckAttrs(t, recreated, map[string]string{
+		"address_id":  state.Attributes["address_id"],
+		"ip_address":  "10.10.1.2",
+		"description": "Terraform test address, recreated",
+	})
+
+	// With keep_on_destroy, destroying the resource keeps the address, so
+	// that it is reused when the resource is created again.
+	testFakeDestroy(t, meta, "phpipam_address", recreated)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	if _, err := c.GetAddressesByIP("10.10.1.2"); err != nil {
+		t.Fatalf("expected the address to be kept, got %s", err)
+	}
+	recreated = testFakeApply(t, meta, "phpipam_address", nil, config)
+	testFakeCheckAttrs(t, recreated, map[string]string{
+		"address_id": state.Attributes["address_id"],
+		"ip_address": "10.10.1.2",
+	})
+
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":      subnetID,
+		"hostname":       "vm2.cust1.local",
+		"allocation_key": "hostname",
+	})
+	byMAC := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":      subnetID,
+		"mac_address":    "00:1a:2b:3c:4d:5e",
+		"allocation_key": "mac_address",
+	})
+	testFakeCheckAttrs(t, byMAC, map[string]string{
+		"ip_address": "10.10.1.4",
+	})
+	reused := testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":      subnetID,
+		"mac_address":    "001A.2B3C.4D5E",
+		"allocation_key": "mac_address",
+	})
+	testFakeCheckAttrs(t, reused, map[string]string{
+		"address_id": byMAC.Attributes["address_id"],
+	})
+
+	// Custom fields are filtered by PHPIPAM, rather than read for every
+	// address in the subnet.
+	byCustomField := map[string]interface{}{
+		"subnet_id":      subnetID,
+		"allocation_key": "custom_CustomTestAddresses",
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestAddresses": "vm-uuid-1",
+		},
+	}
+	first := testFakeApply(t, meta, "phpipam_address", nil, byCustomField)
+	var reads int
+	server.OnRequest(func(r *http.Request) {
+		if m := testAddressPath.FindStringSubmatch(r.URL.Path); m != nil && m[1] != first.ID {
+			reads++
+		}
+	})
+	second := testFakeApply(t, meta, "phpipam_address", nil, byCustomField)
+	server.OnRequest(nil)
+	testFakeCheckAttrs(t, second, map[string]string{
+		"address_id": first.Attributes["address_id"],
+		"ip_address": first.Attributes["ip_address"],
+	})
+	if reads != 0 {
+		t.Fatalf("expected no reads of other addresses, got %d", reads)
+	}
+
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.200",
+		"hostname":   "vm2.cust1.local",
+	})
+	if _, err := testFakeApplyE(meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":      subnetID,
+		"hostname":       "vm2.cust1.local",
+		"allocation_key": "hostname",
+	}); !strings.Contains(err, "2 addresses found with hostname \"vm2.cust1.local\"") {
+		t.Fatalf("expected an ambiguous allocation key error, got %q", err)
+	}
+	if _, err := testFakeApplyE(meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":      subnetID,
+		"allocation_key": "hostname",
+	}); !strings.Contains(err, "allocation_key is hostname, but hostname is not set") {
+		t.Fatalf("expected a missing allocation key error, got %q", err)
+	}
+}
+
+func TestResourcePHPIPAMAddressAllocationKeyReplace(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	config := map[string]interface{}{
+		"subnet_id":       subnetID,
+		"hostname":        "vm1.cust1.local",
+		"allocation_key":  "hostname",
+		"keep_on_destroy": true,
+	}
+	old := testFakeApply(t, meta, "phpipam_address", nil, config)
+
+	// With create_before_destroy, the new resource reuses the address first,
+	// and destroying the old resource afterwards keeps it.
+	config["description"] = "Terraform test address, replaced"
+	replacement := testFakeApply(t, meta, "phpipam_address", nil, config)
+	testFakeDestroy(t, meta, "phpipam_address", old)
+	state := testFakeRefresh(t, meta, "phpipam_address", replacement)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"address_id":  old.Attributes["address_id"],
+		"ip_address":  old.Attributes["ip_address"],
+		"description": "Terraform test address, replaced",
+	})
+
+	// Destroying the resource first, then creating it again, gets back the
+	// same address as well.
+	testFakeDestroy(t, meta, "phpipam_address", state)
+	config["description"] = "Terraform test address, recreated"
+	state = testFakeApply(t, meta, "phpipam_address", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"address_id": old.Attributes["address_id"],
+		"ip_address": old.Attributes["ip_address"],
+	})
+
+	// Without keep_on_destroy, destroying the resource deletes the address,
+	// even with an allocation key.
+	delete(config, "keep_on_destroy")
+	state = testFakeApply(t, meta, "phpipam_address", state, config)
+	testFakeDestroy(t, meta, "phpipam_address", state)
+	if _, err := c.GetAddressesByIP(old.Attributes["ip_address"]); err == nil || apiErrorCode(err) != 404 {
+		t.Fatalf("expected the address to be deleted, got %v", err)
+	}
+}
+
+func TestResourcePHPIPAMAddressAdoptExisting(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	config := map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.10",
+		"hostname":   "tf-test.cust1.local",
+	}
+	state := testFakeApply(t, meta, "phpipam_address", nil, config)
+
+	config["hostname"] = "tf-test-adopted.cust1.local"
+	config["state_tag"] = "Used"
+	config["adopt_existing"] = true
+	adopted := testFakeApply(t, meta, "phpipam_address", nil, config)
+	testFakeCheckAttrs(t, adopted, map[string]string{
+		"address_id": state.ID,
+		"hostname":   "tf-test-adopted.cust1.local",
+		"state_tag":  "Used",
+	})
+}
@@ -0,0 +1,88 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePHPIPAMDevice returns the resource structure for the phpipam_device
+// resource.
+//
+// Note that we use the data source read function here to pull down data, as
+// read workflow is identical for both the resource and the data source.
+func resourcePHPIPAMDevice() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePHPIPAMDeviceCreate,
+		Read:   dataSourcePHPIPAMDeviceRead,
+		Update: resourcePHPIPAMDeviceUpdate,
+		Delete: resourcePHPIPAMDeviceDelete,
+		Schema: resourceDeviceSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourcePHPIPAMDeviceCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).devicesController
+	in := expandDevice(d)
+
+	// Assert the ID field here is empty. If this is not empty the request will fail.
+	in.ID = 0
+
+	if _, err := c.CreateDevice(in); err != nil {
+		return err
+	}
+
+	// The API does not return the ID of the new device, so look it up by host
+	// name.
+	devices, err := c.GetDevicesByHostname(in.Hostname)
+	if err != nil {
+		return fmt.Errorf("Could not read device after creating: %s", err)
+	}
+
+	if len(devices) != 1 {
+		return errors.New("Device either missing or multiple results returned by reading device after creation")
+	}
+
+	d.SetId(strconv.Itoa(devices[0].ID))
+	d.Set("device_id", devices[0].ID)
+
+	// If we have custom fields, set them now.
+	if customFields, ok := d.GetOk("custom_fields"); ok {
+		if _, err := c.UpdateDeviceCustomFields(devices[0].ID, customFields.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+
+	return dataSourcePHPIPAMDeviceRead(d, meta)
+}
+
+func resourcePHPIPAMDeviceUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).devicesController
+	in := expandDevice(d)
+
+	if _, err := c.UpdateDevice(in); err != nil {
+		return err
+	}
+
+	if err := updateCustomFields(d, c); err != nil {
+		return err
+	}
+
+	return dataSourcePHPIPAMDeviceRead(d, meta)
+}
+
+func resourcePHPIPAMDeviceDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).devicesController
+	in := expandDevice(d)
+
+	if _, err := c.DeleteDevice(in.ID); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
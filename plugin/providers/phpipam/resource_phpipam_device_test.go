@@ -0,0 +1,114 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourcePHPIPAMDeviceResourceName = "phpipam_device.device"
+const testAccResourcePHPIPAMDeviceHostname = "tf-test-device"
+const testAccResourcePHPIPAMDeviceConfig = `
+resource "phpipam_device" "device" {
+  hostname    = "tf-test-device"
+  ip_address  = "10.10.1.245"
+  description = "Terraform test device"
+}
+`
+
+const testAccResourcePHPIPAMDeviceAddressConfig = `
+resource "phpipam_section" "section" {
+  name        = "tf-test"
+  description = "Terraform test section"
+}
+
+resource "phpipam_subnet" "subnet" {
+  section_id     = phpipam_section.section.section_id
+  subnet_address = "10.10.1.0"
+  subnet_mask    = 24
+}
+
+resource "phpipam_device" "device" {
+  hostname    = "tf-test-device"
+  ip_address  = "10.10.1.245"
+  description = "Terraform test device, step 2"
+  sections    = phpipam_section.section.section_id
+}
+
+resource "phpipam_address" "address" {
+  subnet_id  = phpipam_subnet.subnet.subnet_id
+  ip_address = "10.10.1.245"
+  device_id  = phpipam_device.device.device_id
+}
+`
+
+func TestAccResourcePHPIPAMDevice(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			sectionSweep("tf-test", t)
+			deviceSweep("tf-test-device", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMDeviceDeleted,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMDeviceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMDeviceCreated,
+					resource.TestCheckResourceAttr("phpipam_device.device", "hostname", "tf-test-device"),
+					resource.TestCheckResourceAttr("phpipam_device.device", "ip_address", "10.10.1.245"),
+					resource.TestCheckResourceAttr("phpipam_device.device", "description", "Terraform test device"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMDeviceAddressConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMDeviceCreated,
+					resource.TestCheckResourceAttr("phpipam_device.device", "description", "Terraform test device, step 2"),
+					resource.TestCheckResourceAttrPair("phpipam_device.device", "device_id", "phpipam_address.address", "device_id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "phpipam_device.device",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourcePHPIPAMDeviceCreated(s *terraform.State) error {
+	r, ok := s.RootModule().Resources[testAccResourcePHPIPAMDeviceResourceName]
+	if !ok {
+		return fmt.Errorf("Resource name %s could not be found", testAccResourcePHPIPAMDeviceResourceName)
+	}
+	if r.Primary.ID == "" {
+		return errors.New("No ID is set")
+	}
+
+	id, _ := strconv.Atoi(r.Primary.ID)
+
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).devicesController
+	if _, err := c.GetDeviceByID(id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func testAccCheckResourcePHPIPAMDeviceDeleted(s *terraform.State) error {
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).devicesController
+	_, err := c.GetDevicesByHostname(testAccResourcePHPIPAMDeviceHostname)
+	switch {
+	case err == nil:
+		return errors.New("Expected error, got none")
+	case err != nil && err.Error() != "Error from API (404): No results (filter applied)":
+		return fmt.Errorf("Expected 404, got %s", err)
+	}
+
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
 )
 
 // resourcePHPIPAMAddress returns the resource structure for the phpipam_address
@@ -119,11 +120,14 @@ func resourcePHPIPAMFirstFreeAddressCreate(d *schema.ResourceData, meta interfac
 	d.Set("subnet_id", nil)
 
 	// Get address controller and start address creation
-	c := meta.(*ProviderPHPIPAMClient).addressesController
+	client := meta.(*ProviderPHPIPAMClient)
+	c := client.addressesController
 
 	in := expandAddress(d)
 
-	out, err := c.CreateFirstFreeAddress(subnet_id, in)
+	out, err := client.allocateAddress(subnet_id, in, func(in addresses.Address) (string, error) {
+		return c.CreateFirstFreeAddress(subnet_id, in)
+	})
 	if err != nil {
 		return err
 	}
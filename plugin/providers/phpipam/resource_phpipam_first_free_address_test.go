@@ -0,0 +1,45 @@
+package phpipam
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestResourcePHPIPAMFirstFreeAddress(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.1.1",
+	})
+
+	config := map[string]interface{}{
+		"subnet_id":   subnetID,
+		"description": "Terraform test first free address",
+		"hostname":    "tf-test.cust1.local",
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestAddresses": "terraform-test",
+		},
+	}
+	state := testFakeApply(t, meta, "phpipam_first_free_address", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_id":   strconv.Itoa(subnetID),
+		"ip_address":  "10.10.1.2",
+		"description": "Terraform test first free address",
+		"hostname":    "tf-test.cust1.local",
+		"custom_fields.custom_CustomTestAddresses": "terraform-test",
+	})
+
+	config["description"] = "Terraform test first free address, step 2"
+	state = testFakeApply(t, meta, "phpipam_first_free_address", state, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"ip_address":  "10.10.1.2",
+		"description": "Terraform test first free address, step 2",
+	})
+
+	testFakeDestroy(t, meta, "phpipam_first_free_address", state)
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	if _, err := c.GetAddressesByIP("10.10.1.2"); err == nil || err.Error() != "Error from API (404): Address not found" {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
 )
 
 // resourcePHPIPAMAddress returns the resource structure for the phpipam_address
@@ -34,21 +36,26 @@ func resourcePHPIPAMFirstFreeSubnetCreate(ctx context.Context, d *schema.Resourc
 	d.Set("subnet_id", nil)
 	subnet_mask := d.Get("subnet_mask").(int)
 	// Get address controller and start address creation
-	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	client := meta.(*ProviderPHPIPAMClient)
+	c := client.subnetsController
 
-	in := expandSubnet(d, meta.(*ProviderPHPIPAMClient).NestCustomFields)
+	in := expandSubnet(d, client.NestCustomFields)
 
-	out, err := c.CreateFirstFreeSubnet(subnet_id, subnet_mask, in)
+	out, err := client.allocateSubnet(subnet_id, in, func(in subnets.Subnet) (string, error) {
+		return c.CreateFirstFreeSubnet(subnet_id, subnet_mask, in)
+	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	d.Set("subnet_address", out)
+	// The API returns the CIDR of the new subnet.
+	netAndMask := strings.Split(out, "/")
+	d.Set("subnet_address", netAndMask[0])
 
 	if !meta.(*ProviderPHPIPAMClient).NestCustomFields {
 		// If we have custom fields, set them now. We need to get the IP address's ID
 		// beforehand.
 		if customFields, ok := d.GetOk("custom_fields"); ok {
-			addrs, err := c.GetSubnetsByCIDR(fmt.Sprintf("%s/%d", out, in.Mask))
+			addrs, err := c.GetSubnetsByCIDR(out)
 			if err != nil {
 				return diag.FromErr(fmt.Errorf("Could not read IP address after creating: %s", err))
 			}
@@ -0,0 +1,75 @@
+package phpipam
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourcePHPIPAMFirstFreeSubnet(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	parentID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	parent := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(parentID)})
+	sectionID, _ := strconv.Atoi(parent.Attributes["section_id"])
+	testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":       sectionID,
+		"subnet_address":   "10.10.1.0",
+		"subnet_mask":      26,
+		"master_subnet_id": parentID,
+	})
+
+	config := map[string]interface{}{
+		"parent_subnet_id": parentID,
+		"subnet_mask":      26,
+		"description":      "Terraform test first free subnet",
+	}
+	state := testFakeApply(t, meta, "phpipam_first_free_subnet", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_address":   "10.10.1.64",
+		"subnet_mask":      "26",
+		"master_subnet_id": strconv.Itoa(parentID),
+		"description":      "Terraform test first free subnet",
+	})
+
+	config["description"] = "Terraform test first free subnet, step 2"
+	state = testFakeApply(t, meta, "phpipam_first_free_subnet", state, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_address": "10.10.1.64",
+		"description":    "Terraform test first free subnet, step 2",
+	})
+
+	testFakeDestroy(t, meta, "phpipam_first_free_subnet", state)
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	if _, err := c.GetSubnetsByCIDR("10.10.1.64/26"); err == nil || err.Error() != "Error from API (404): No subnets found" {
+		t.Fatalf("Expected 404, got %v", err)
+	}
+}
+
+func TestResourcePHPIPAMFirstFreeSubnetCustomFields(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	parentID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+
+	// The API returns the new subnet as a CIDR, which is used to look the
+	// subnet up and set its custom fields.
+	state := testFakeApply(t, meta, "phpipam_first_free_subnet", nil, map[string]interface{}{
+		"parent_subnet_id": parentID,
+		"subnet_mask":      26,
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestSubnets": "terraform-test",
+		},
+	})
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_address":                         "10.10.1.0",
+		"custom_fields.custom_CustomTestSubnets": "terraform-test",
+	})
+
+	id, _ := strconv.Atoi(state.ID)
+	fields, err := meta.(*ProviderPHPIPAMClient).subnetsController.GetSubnetCustomFields(id)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if fields["custom_CustomTestSubnets"] != "terraform-test" {
+		t.Fatalf("expected custom field to be set in PHPIPAM, got %v", fields)
+	}
+}
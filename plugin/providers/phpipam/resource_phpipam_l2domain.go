@@ -1,6 +1,11 @@
 package phpipam
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 // resourcePHPIPAML2Domain returns the resource structure for the phpipam_l2domain
 // resource.
@@ -15,7 +20,7 @@ func resourcePHPIPAML2Domain() *schema.Resource {
 		Delete: resourcePHPIPAML2DomainDelete,
 		Schema: resourceL2DomainSchema(),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importNaturalKey(importL2DomainID),
 		},
 	}
 }
@@ -27,6 +32,23 @@ func resourcePHPIPAML2DomainCreate(d *schema.ResourceData, meta interface{}) err
 	// Assert the ID field here is empty. If this is not empty the request will fail.
 	in.ID = 0
 
+	// Adopt the existing L2 domain with the same name, if requested.
+	if d.Get("adopt_existing").(bool) {
+		out, err := c.GetL2DomainByName(in.Name)
+		if err != nil && apiErrorCode(err) != 404 {
+			return err
+		}
+		switch len(out) {
+		case 0:
+		case 1:
+			d.SetId(strconv.Itoa(out[0].ID))
+			d.Set("domain_id", out[0].ID)
+			return resourcePHPIPAML2DomainUpdate(d, meta)
+		default:
+			return adoptExistingError("L2 domain", fmt.Sprintf("name %q", in.Name), len(out))
+		}
+	}
+
 	if _, err := c.CreateL2Domain(in); err != nil {
 		return err
 	}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/l2domains"
 )
 
 const testAccResourcePHPIPAML2DomainResourceName = "phpipam_l2domain.l2domain"
@@ -20,13 +21,13 @@ resource "phpipam_l2domain" "l2domain" {
 `
 
 func TestAccResourcePHPIPAML2Domain(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			l2domainSweep("tf-test-l2domain", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAML2DomainDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAML2DomainDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAML2DomainConfig,
@@ -36,6 +37,12 @@ func TestAccResourcePHPIPAML2Domain(t *testing.T) {
 					resource.TestCheckResourceAttr("phpipam_l2domain.l2domain", "description", "Terraform test l2domain"),
 				),
 			},
+			resource.TestStep{
+				ResourceName:            "phpipam_l2domain.l2domain",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopt_existing"},
+			},
 		},
 	})
 }
@@ -70,3 +77,54 @@ func testAccCheckResourcePHPIPAML2DomainDeleted(s *terraform.State) error {
 
 	return nil
 }
+
+func TestResourcePHPIPAML2DomainAdoptExisting(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	state := testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name":        "tf-test",
+		"description": "Terraform test L2 domain",
+	})
+	adopted := testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name":           "tf-test",
+		"description":    "Terraform test L2 domain, adopted",
+		"adopt_existing": true,
+	})
+	testFakeCheckAttrs(t, adopted, map[string]string{
+		"domain_id":   state.ID,
+		"description": "Terraform test L2 domain, adopted",
+	})
+}
+
+func TestResourcePHPIPAML2DomainImport(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	c := meta.(*ProviderPHPIPAMClient).l2domainsController
+	if _, err := c.CreateL2Domain(l2domains.L2Domain{
+		Name:        testAccResourcePHPIPAML2DomainName,
+		Description: "Terraform test l2domain",
+	}); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	out, err := c.GetL2DomainByName(testAccResourcePHPIPAML2DomainName)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	id := strconv.Itoa(out[0].ID)
+
+	// An imported L2 domain only has its ID, which is read as the domain ID.
+	state := testFakeImport(t, meta, "phpipam_l2domain", id)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"domain_id":   id,
+		"name":        testAccResourcePHPIPAML2DomainName,
+		"description": "Terraform test l2domain",
+	})
+
+	// Applying the configuration afterwards keeps the imported domain.
+	state = testFakeApply(t, meta, "phpipam_l2domain", state, map[string]interface{}{
+		"name":        testAccResourcePHPIPAML2DomainName,
+		"description": "Terraform test l2domain",
+	})
+	if state.ID != id {
+		t.Fatalf("expected L2 domain ID %s to be kept, got %s", id, state.ID)
+	}
+}
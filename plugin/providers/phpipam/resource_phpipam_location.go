@@ -0,0 +1,88 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePHPIPAMLocation returns the resource structure for the
+// phpipam_location resource.
+//
+// Note that we use the data source read function here to pull down data, as
+// read workflow is identical for both the resource and the data source.
+func resourcePHPIPAMLocation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePHPIPAMLocationCreate,
+		Read:   dataSourcePHPIPAMLocationRead,
+		Update: resourcePHPIPAMLocationUpdate,
+		Delete: resourcePHPIPAMLocationDelete,
+		Schema: resourceLocationSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourcePHPIPAMLocationCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).locationsController
+	in := expandLocation(d)
+
+	// Assert the ID field here is empty. If this is not empty the request will fail.
+	in.ID = 0
+
+	if _, err := c.CreateLocation(in); err != nil {
+		return err
+	}
+
+	// The API does not return the ID of the new location, so look it up by
+	// name.
+	locations, err := c.GetLocationsByName(in.Name)
+	if err != nil {
+		return fmt.Errorf("Could not read location after creating: %s", err)
+	}
+
+	if len(locations) != 1 {
+		return errors.New("Location either missing or multiple results returned by reading location after creation")
+	}
+
+	d.SetId(strconv.Itoa(locations[0].ID))
+	d.Set("location_id", locations[0].ID)
+
+	// If we have custom fields, set them now.
+	if customFields, ok := d.GetOk("custom_fields"); ok {
+		if _, err := c.UpdateLocationCustomFields(locations[0].ID, customFields.(map[string]interface{})); err != nil {
+			return err
+		}
+	}
+
+	return dataSourcePHPIPAMLocationRead(d, meta)
+}
+
+func resourcePHPIPAMLocationUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).locationsController
+	in := expandLocation(d)
+
+	if _, err := c.UpdateLocation(in); err != nil {
+		return err
+	}
+
+	if err := updateCustomFields(d, c); err != nil {
+		return err
+	}
+
+	return dataSourcePHPIPAMLocationRead(d, meta)
+}
+
+func resourcePHPIPAMLocationDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).locationsController
+	in := expandLocation(d)
+
+	if _, err := c.DeleteLocation(in.ID); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,114 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourcePHPIPAMLocationResourceName = "phpipam_location.location"
+const testAccResourcePHPIPAMLocationName = "tf-test-location"
+const testAccResourcePHPIPAMLocationConfig = `
+resource "phpipam_location" "location" {
+  name        = "tf-test-location"
+  description = "Terraform test location"
+  address     = "1 Example Street"
+  latitude    = "46.0569"
+  longitude   = "14.5058"
+}
+`
+
+const testAccResourcePHPIPAMLocationSubnetConfig = `
+resource "phpipam_location" "location" {
+  name        = "tf-test-location"
+  description = "Terraform test location, step 2"
+  address     = "1 Example Street"
+  latitude    = "46.0569"
+  longitude   = "14.5058"
+}
+
+resource "phpipam_section" "section" {
+  name        = "tf-test"
+  description = "Terraform test section"
+}
+
+resource "phpipam_subnet" "subnet" {
+  section_id     = phpipam_section.section.section_id
+  subnet_address = "10.10.1.0"
+  subnet_mask    = 24
+  location_id    = phpipam_location.location.location_id
+}
+`
+
+func TestAccResourcePHPIPAMLocation(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			sectionSweep("tf-test", t)
+			locationSweep("tf-test-location", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMLocationDeleted,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMLocationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMLocationCreated,
+					resource.TestCheckResourceAttr("phpipam_location.location", "name", "tf-test-location"),
+					resource.TestCheckResourceAttr("phpipam_location.location", "description", "Terraform test location"),
+					resource.TestCheckResourceAttr("phpipam_location.location", "address", "1 Example Street"),
+					resource.TestCheckResourceAttr("phpipam_location.location", "latitude", "46.0569"),
+					resource.TestCheckResourceAttr("phpipam_location.location", "longitude", "14.5058"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMLocationSubnetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMLocationCreated,
+					resource.TestCheckResourceAttr("phpipam_location.location", "description", "Terraform test location, step 2"),
+					resource.TestCheckResourceAttrPair("phpipam_location.location", "location_id", "phpipam_subnet.subnet", "location_id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "phpipam_location.location",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourcePHPIPAMLocationCreated(s *terraform.State) error {
+	r, ok := s.RootModule().Resources[testAccResourcePHPIPAMLocationResourceName]
+	if !ok {
+		return fmt.Errorf("Resource name %s could not be found", testAccResourcePHPIPAMLocationResourceName)
+	}
+	if r.Primary.ID == "" {
+		return errors.New("No ID is set")
+	}
+
+	id, _ := strconv.Atoi(r.Primary.ID)
+
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).locationsController
+	if _, err := c.GetLocationByID(id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func testAccCheckResourcePHPIPAMLocationDeleted(s *terraform.State) error {
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).locationsController
+	_, err := c.GetLocationsByName(testAccResourcePHPIPAMLocationName)
+	switch {
+	case err == nil:
+		return errors.New("Expected error, got none")
+	case err != nil && err.Error() != "Error from API (404): No results (filter applied)":
+		return fmt.Errorf("Expected 404, got %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourcePHPIPAMNameserver returns the resource structure for the
+// phpipam_nameserver resource.
+//
+// Note that we use the data source read function here to pull down data, as
+// read workflow is identical for both the resource and the data source.
+func resourcePHPIPAMNameserver() *schema.Resource {
+	return &schema.Resource{
+		Create: resourcePHPIPAMNameserverCreate,
+		Read:   dataSourcePHPIPAMNameserverRead,
+		Update: resourcePHPIPAMNameserverUpdate,
+		Delete: resourcePHPIPAMNameserverDelete,
+		Schema: resourceNameserverSchema(),
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+	}
+}
+
+func resourcePHPIPAMNameserverCreate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).nameserversController
+	in := expandNameserver(d)
+
+	// Assert the ID field here is empty. If this is not empty the request will fail.
+	in.ID = 0
+
+	if _, err := c.CreateNameserver(in); err != nil {
+		return err
+	}
+
+	// The API does not return the ID of the new nameserver set, so look it up
+	// by name.
+	nameservers, err := c.GetNameserversByName(in.Name)
+	if err != nil {
+		return fmt.Errorf("Could not read nameserver after creating: %s", err)
+	}
+
+	if len(nameservers) != 1 {
+		return errors.New("Nameserver either missing or multiple results returned by reading nameserver after creation")
+	}
+
+	d.SetId(strconv.Itoa(nameservers[0].ID))
+	d.Set("nameserver_id", nameservers[0].ID)
+
+	return dataSourcePHPIPAMNameserverRead(d, meta)
+}
+
+func resourcePHPIPAMNameserverUpdate(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).nameserversController
+	in := expandNameserver(d)
+
+	if _, err := c.UpdateNameserver(in); err != nil {
+		return err
+	}
+
+	return dataSourcePHPIPAMNameserverRead(d, meta)
+}
+
+func resourcePHPIPAMNameserverDelete(d *schema.ResourceData, meta interface{}) error {
+	c := meta.(*ProviderPHPIPAMClient).nameserversController
+	in := expandNameserver(d)
+
+	if _, err := c.DeleteNameserver(in.ID); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,114 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourcePHPIPAMNameserverResourceName = "phpipam_nameserver.nameserver"
+const testAccResourcePHPIPAMNameserverName = "tf-test-nameserver"
+const testAccResourcePHPIPAMNameserverConfig = `
+resource "phpipam_nameserver" "nameserver" {
+  name        = "tf-test-nameserver"
+  addresses   = ["10.10.0.53", "10.10.1.53"]
+  description = "Terraform test nameserver"
+}
+`
+
+const testAccResourcePHPIPAMNameserverSubnetConfig = `
+resource "phpipam_section" "section" {
+  name        = "tf-test"
+  description = "Terraform test section"
+}
+
+resource "phpipam_nameserver" "nameserver" {
+  name        = "tf-test-nameserver"
+  addresses   = ["10.10.0.53"]
+  description = "Terraform test nameserver, step 2"
+  sections    = [phpipam_section.section.section_id]
+}
+
+resource "phpipam_subnet" "subnet" {
+  section_id     = phpipam_section.section.section_id
+  subnet_address = "10.10.1.0"
+  subnet_mask    = 24
+  nameserver_id  = phpipam_nameserver.nameserver.nameserver_id
+}
+`
+
+func TestAccResourcePHPIPAMNameserver(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			sectionSweep("tf-test", t)
+			nameserverSweep("tf-test-nameserver", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMNameserverDeleted,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMNameserverConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMNameserverCreated,
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "name", "tf-test-nameserver"),
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "description", "Terraform test nameserver"),
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "addresses.#", "2"),
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "addresses.0", "10.10.0.53"),
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "addresses.1", "10.10.1.53"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMNameserverSubnetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMNameserverCreated,
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "description", "Terraform test nameserver, step 2"),
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "addresses.#", "1"),
+					resource.TestCheckResourceAttr("phpipam_nameserver.nameserver", "sections.#", "1"),
+					resource.TestCheckResourceAttrPair("phpipam_nameserver.nameserver", "sections.0", "phpipam_section.section", "section_id"),
+					resource.TestCheckResourceAttrPair("phpipam_nameserver.nameserver", "nameserver_id", "phpipam_subnet.subnet", "nameserver_id"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "phpipam_nameserver.nameserver",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourcePHPIPAMNameserverCreated(s *terraform.State) error {
+	r, ok := s.RootModule().Resources[testAccResourcePHPIPAMNameserverResourceName]
+	if !ok {
+		return fmt.Errorf("Resource name %s could not be found", testAccResourcePHPIPAMNameserverResourceName)
+	}
+	if r.Primary.ID == "" {
+		return errors.New("No ID is set")
+	}
+
+	id, _ := strconv.Atoi(r.Primary.ID)
+
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).nameserversController
+	if _, err := c.GetNameserverByID(id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func testAccCheckResourcePHPIPAMNameserverDeleted(s *terraform.State) error {
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).nameserversController
+	_, err := c.GetNameserversByName(testAccResourcePHPIPAMNameserverName)
+	switch {
+	case err == nil:
+		return errors.New("Expected error, got none")
+	case err != nil && err.Error() != "Error from API (404): No results (filter applied)":
+		return fmt.Errorf("Expected 404, got %s", err)
+	}
+
+	return nil
+}
@@ -1,6 +1,10 @@
 package phpipam
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 // resourcePHPIPAMSection returns the resource structure for the phpipam_section
 // resource.
@@ -15,7 +19,7 @@ func resourcePHPIPAMSection() *schema.Resource {
 		Delete: resourcePHPIPAMSectionDelete,
 		Schema: resourceSectionSchema(),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importNaturalKey(importSectionID),
 		},
 	}
 }
@@ -27,6 +31,19 @@ func resourcePHPIPAMSectionCreate(d *schema.ResourceData, meta interface{}) erro
 	// Assert the ID field here is empty. If this is not empty the request will fail.
 	in.ID = 0
 
+	// Adopt the existing section with the same name, if requested.
+	if d.Get("adopt_existing").(bool) {
+		out, err := c.GetSectionByName(in.Name)
+		switch {
+		case err == nil:
+			d.SetId(strconv.Itoa(out.ID))
+			d.Set("section_id", out.ID)
+			return resourcePHPIPAMSectionUpdate(d, meta)
+		case apiErrorCode(err) != 404:
+			return err
+		}
+	}
+
 	if _, err := c.CreateSection(in); err != nil {
 		return err
 	}
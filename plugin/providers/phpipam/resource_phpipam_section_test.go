@@ -20,10 +20,10 @@ resource "phpipam_section" "section" {
 `
 
 func TestAccResourcePHPIPAMSection(t *testing.T) {
-	resource.Test(t, resource.TestCase{
-		PreCheck:     func() { testAccPreCheck(t) },
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMSectionDeleted,
+	testAccTest(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMSectionDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMSectionConfig,
@@ -33,6 +33,12 @@ func TestAccResourcePHPIPAMSection(t *testing.T) {
 					resource.TestCheckResourceAttr("phpipam_section.section", "description", "Terraform test section"),
 				),
 			},
+			resource.TestStep{
+				ResourceName:            "phpipam_section.section",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopt_existing"},
+			},
 		},
 	})
 }
@@ -67,3 +73,21 @@ func testAccCheckResourcePHPIPAMSectionDeleted(s *terraform.State) error {
 
 	return nil
 }
+
+func TestResourcePHPIPAMSectionAdoptExisting(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	state := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name":        "tf-test",
+		"description": "Terraform test section",
+	})
+	adopted := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name":           "tf-test",
+		"description":    "Terraform test section, adopted",
+		"adopt_existing": true,
+	})
+	testFakeCheckAttrs(t, adopted, map[string]string{
+		"section_id":  state.ID,
+		"description": "Terraform test section, adopted",
+	})
+}
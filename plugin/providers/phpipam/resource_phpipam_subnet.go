@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/netip"
 	"strconv"
 	"strings"
 
@@ -23,9 +24,10 @@ func resourcePHPIPAMSubnet() *schema.Resource {
 		ReadContext:   dataSourcePHPIPAMSubnetRead,
 		UpdateContext: resourcePHPIPAMSubnetUpdate,
 		DeleteContext: resourcePHPIPAMSubnetDelete,
+		CustomizeDiff: resourcePHPIPAMSubnetCustomizeDiff,
 		Schema:        resourceSubnetSchema(),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importNaturalKey(importSubnetID),
 		},
 	}
 }
@@ -37,6 +39,25 @@ func resourcePHPIPAMSubnetCreate(ctx context.Context, d *schema.ResourceData, me
 	// Assert the ID field here is empty. If this is not empty the request will fail.
 	in.ID = 0
 
+	// Adopt the existing subnet with the same CIDR in the section, if
+	// requested.
+	if _, ok := d.GetOk("subnet_address"); ok && d.Get("adopt_existing").(bool) {
+		cidr := fmt.Sprintf("%s/%d", in.SubnetAddress, in.Mask)
+		out, err := c.GetSubnetsByCIDRAndSection(cidr, in.SectionID)
+		if err != nil && apiErrorCode(err) != 404 {
+			return diag.FromErr(err)
+		}
+		switch len(out) {
+		case 0:
+		case 1:
+			d.SetId(strconv.Itoa(out[0].ID))
+			d.Set("subnet_id", out[0].ID)
+			return resourcePHPIPAMSubnetUpdate(ctx, d, meta)
+		default:
+			return diag.FromErr(adoptExistingError("subnet", fmt.Sprintf("CIDR %s in section ID %d", cidr, in.SectionID), len(out)))
+		}
+	}
+
 	if _, ok := d.GetOk("subnet_address"); ok {
 		if _, err := c.CreateSubnet(in); err != nil {
 			return diag.FromErr(err)
@@ -85,21 +106,49 @@ func resourcePHPIPAMSubnetCreate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	if err := resourceSubnetClearableFields.setConfigured(d); err != nil {
+		return diag.FromErr(err)
+	}
 	return dataSourcePHPIPAMSubnetRead(ctx, d, meta)
 }
 
 func resourcePHPIPAMSubnetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*ProviderPHPIPAMClient).subnetsController
 	in := expandSubnet(d, meta.(*ProviderPHPIPAMClient).NestCustomFields)
+
+	// Moving the subnet to another section also moves its child subnets, and
+	// needs its master subnet to be updated at the same time. Subnets adopted
+	// on create have been looked up by section and CIDR, so these cannot
+	// change for new resources.
+	if d.HasChange("section_id") && !d.IsNewResource() {
+		if err := moveSubnet(meta.(*ProviderPHPIPAMClient), in.ID, in.SectionID, in.MasterSubnetID); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	// Mask changes that get here can be made in place, see
+	// resourcePHPIPAMSubnetCustomizeDiff.
+	if d.HasChange("subnet_mask") && !d.IsNewResource() {
+		if _, err := c.ResizeSubnet(in.ID, d.Get("subnet_mask").(int)); err != nil {
+			return diag.FromErr(fmt.Errorf("Could not resize subnet: %s", err))
+		}
+	}
+
 	// Remove the CIDR fields from the request, as these fields being present
 	// implies that the subnet will be either split or renamed, which is not
-	// supported by UpdateSubnet. These are implemented in the API but not in the
-	// SDK, so support may be added at a later time.
+	// supported by UpdateSubnet. Resizing is handled above.
 	in.SubnetAddress = ""
 	in.Mask = 0
 	if _, err := c.UpdateSubnet(in); err != nil {
 		return diag.FromErr(err)
 	}
+	// UpdateSubnet omits empty values, so fields that are cleared are sent
+	// separately.
+	if clear := resourceSubnetClearableFields.updates(d); len(clear) > 0 {
+		if _, err := c.UpdateSubnetFields(in.ID, clear); err != nil {
+			return diag.FromErr(err)
+		}
+	}
 
 	if !meta.(*ProviderPHPIPAMClient).NestCustomFields {
 		if err := updateCustomFields(d, c); err != nil {
@@ -107,6 +156,9 @@ func resourcePHPIPAMSubnetUpdate(ctx context.Context, d *schema.ResourceData, me
 		}
 	}
 
+	if err := resourceSubnetClearableFields.setConfigured(d); err != nil {
+		return diag.FromErr(err)
+	}
 	return dataSourcePHPIPAMSubnetRead(ctx, d, meta)
 }
 
@@ -120,3 +172,148 @@ func resourcePHPIPAMSubnetDelete(ctx context.Context, d *schema.ResourceData, me
 	d.SetId("")
 	return nil
 }
+
+// resourcePHPIPAMSubnetCustomizeDiff forces a new subnet when subnet_mask
+// changes, unless the subnet can be resized in place: the new mask must be
+// shorter, so that the resized subnet contains the old range, and the subnet
+// address must still be the network address with the new mask.
+//
+// It also plans clearing the optional fields that are removed from the
+// configuration, see resourceSubnetClearableFields, and validates moves to
+// another section, see resourcePHPIPAMSubnetCustomizeDiffMove.
+func resourcePHPIPAMSubnetCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" && d.HasChange("subnet_mask") {
+		o, n := d.GetChange("subnet_mask")
+		if !subnetResizable(d.Get("subnet_address").(string), o.(int), n.(int)) {
+			if err := d.ForceNew("subnet_mask"); err != nil {
+				return err
+			}
+		}
+	}
+	if err := resourceSubnetClearableFields.customizeDiff(d); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	return resourcePHPIPAMSubnetCustomizeDiffMove(d, meta)
+}
+
+// resourcePHPIPAMSubnetCustomizeDiffMove plans moves of a subnet to another
+// section. A subnet that is moved without a master_subnet_id in the
+// configuration becomes a top-level subnet of the new section, as its master
+// subnet stays behind. The move is validated when planning, so that a move
+// that PHPIPAM would reject fails early, before anything is changed.
+func resourcePHPIPAMSubnetCustomizeDiffMove(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.HasChange("section_id") || !d.NewValueKnown("section_id") || !d.NewValueKnown("master_subnet_id") {
+		return nil
+	}
+	if raw := d.GetRawConfig(); (raw.IsNull() && !d.HasChange("master_subnet_id")) || (!raw.IsNull() && raw.GetAttr("master_subnet_id").IsNull()) {
+		if err := d.SetNew("master_subnet_id", 0); err != nil {
+			return err
+		}
+	}
+	if d.HasChange("subnet_address") || d.HasChange("subnet_mask") {
+		// The subnet is replaced or resized, and is validated when applying.
+		return nil
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	return validateSubnetMove(meta.(*ProviderPHPIPAMClient), id, fmt.Sprintf("%s/%d", d.Get("subnet_address").(string), d.Get("subnet_mask").(int)), d.Get("section_id").(int), d.Get("master_subnet_id").(int))
+}
+
+// subnetResizable returns whether the subnet address/oldMask can be resized
+// in place to address/newMask, keeping all of its addresses.
+func subnetResizable(address string, oldMask, newMask int) bool {
+	ip, err := netip.ParseAddr(address)
+	if err != nil || newMask >= oldMask || newMask < 0 {
+		return false
+	}
+	p, err := ip.Prefix(newMask)
+	return err == nil && p.Addr() == ip
+}
+
+// validateSubnetMove validates that the subnet with the supplied ID and CIDR
+// can be moved to the section sectionID, under the master subnet masterID, or
+// at the top level of the section if masterID is 0. The master subnet must be
+// in the section and contain the subnet. If the section is in strict mode, the
+// subnet must not overlap with the subnets at its new level either.
+func validateSubnetMove(client *ProviderPHPIPAMClient, id int, cidr string, sectionID, masterID int) error {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		return fmt.Errorf("Invalid subnet %s: %s", cidr, err)
+	}
+	section, err := client.sectionsController.GetSectionByID(sectionID)
+	if err != nil {
+		return fmt.Errorf("Could not read section %d to move subnet %s to: %s", sectionID, cidr, err)
+	}
+
+	if masterID != 0 {
+		master, err := client.subnetsController.GetSubnetByID(masterID)
+		if err != nil {
+			return fmt.Errorf("Could not read master subnet %d to move subnet %s to: %s", masterID, cidr, err)
+		}
+		if master.SectionID != sectionID {
+			return fmt.Errorf("Cannot move subnet %s to section %q: master subnet %s/%d (ID %d) is in another section. Set master_subnet_id to a subnet in section %q, or remove it to move the subnet to the top level of the section", cidr, section.Name, master.SubnetAddress, master.Mask, masterID, section.Name)
+		}
+		if p, err := netip.ParsePrefix(fmt.Sprintf("%s/%d", master.SubnetAddress, master.Mask)); err == nil && (p.Bits() >= prefix.Bits() || !p.Contains(prefix.Addr())) {
+			return fmt.Errorf("Cannot move subnet %s to section %q: it is not contained in master subnet %s", cidr, section.Name, p)
+		}
+	}
+
+	if !section.StrictMode {
+		return nil
+	}
+	others, err := client.sectionsController.GetSubnetsInSection(sectionID)
+	if err != nil && apiErrorCode(err) != 404 {
+		return fmt.Errorf("Could not read subnets of section %d to move subnet %s to: %s", sectionID, cidr, err)
+	}
+	for _, v := range others {
+		if v.ID == id || v.MasterSubnetID != masterID {
+			continue
+		}
+		if p, err := netip.ParsePrefix(fmt.Sprintf("%s/%d", v.SubnetAddress, v.Mask)); err == nil && p.Overlaps(prefix) {
+			return fmt.Errorf("Cannot move subnet %s to section %q: it overlaps with subnet %s (ID %d), and the section is in strict mode", cidr, section.Name, p, v.ID)
+		}
+	}
+	return nil
+}
+
+// moveSubnet moves the subnet with the supplied ID to the section sectionID,
+// under the master subnet masterID, or at the top level of the section if
+// masterID is 0, after validating the move with validateSubnetMove. The
+// subnet's child subnets are moved along with it. Addresses belong to their
+// subnet, and follow it.
+func moveSubnet(client *ProviderPHPIPAMClient, id, sectionID, masterID int) error {
+	c := client.subnetsController
+	subnet, err := c.GetSubnetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := validateSubnetMove(client, id, fmt.Sprintf("%s/%d", subnet.SubnetAddress, subnet.Mask), sectionID, masterID); err != nil {
+		return err
+	}
+
+	if _, err := c.MoveSubnet(id, map[string]int{"sectionId": sectionID, "masterSubnetId": masterID}); err != nil {
+		return fmt.Errorf("Could not move subnet %s/%d: %s", subnet.SubnetAddress, subnet.Mask, err)
+	}
+
+	children := []int{id}
+	for len(children) > 0 {
+		parent := children[0]
+		children = children[1:]
+		out, err := c.GetSubnetsInSubnet(parent)
+		if err != nil && apiErrorCode(err) != 404 {
+			return fmt.Errorf("Could not read child subnets of subnet ID %d to move them: %s", parent, err)
+		}
+		for _, v := range out {
+			if _, err := c.MoveSubnet(v.ID, map[string]int{"sectionId": sectionID}); err != nil {
+				return fmt.Errorf("Could not move child subnet %s/%d: %s", v.SubnetAddress, v.Mask, err)
+			}
+			children = append(children, v.ID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+// resourcePHPIPAMSubnetSplit returns the resource structure for the
+// phpipam_subnet_split resource, which splits a subnet into a number of
+// subnets of equal size.
+//
+// PHPIPAM replaces the split subnet with the new subnets, which take over its
+// addresses. A split cannot be undone through the API, so destroying the
+// resource only removes it from the state, and leaves the subnets in place.
+// As the split subnet is gone afterwards, it is looked up by its section and
+// CIDR when splitting it, and only the new subnets are read later on.
+func resourcePHPIPAMSubnetSplit() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePHPIPAMSubnetSplitCreate,
+		ReadContext:   resourcePHPIPAMSubnetSplitRead,
+		DeleteContext: resourcePHPIPAMSubnetSplitDelete,
+		Schema:        resourceSubnetSplitSchema(),
+	}
+}
+
+func resourcePHPIPAMSubnetSplitCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	sectionID := d.Get("section_id").(int)
+	cidr := fmt.Sprintf("%s/%d", d.Get("subnet_address").(string), d.Get("subnet_mask").(int))
+	number := d.Get("number").(int)
+
+	out, err := c.GetSubnetsByCIDRAndSection(cidr, sectionID)
+	switch {
+	case err != nil && apiErrorCode(err) == 404:
+		return diag.FromErr(fmt.Errorf("No subnet found with CIDR %s in section ID %d", cidr, sectionID))
+	case err != nil:
+		return diag.FromErr(err)
+	case len(out) != 1:
+		return diag.FromErr(fmt.Errorf("%d subnets found with CIDR %s in section ID %d", len(out), cidr, sectionID))
+	}
+	subnet := out[0]
+	cidrs, err := splitSubnetCIDRs(subnet.SubnetAddress, int(subnet.Mask), number)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if _, err := c.SplitSubnet(subnet.ID, number); err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The API does not return the IDs of the new subnets, so look them up by
+	// CIDR. They are matched on their parent, which is the parent of the split
+	// subnet, or the split subnet itself if PHPIPAM nests them in it.
+	var children []subnets.Subnet
+	for _, cidr := range cidrs {
+		out, err := c.GetSubnetsByCIDRAndSection(cidr, subnet.SectionID)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("Could not read subnet %s after splitting: %s", cidr, err))
+		}
+		var found bool
+		for _, v := range out {
+			if v.MasterSubnetID == subnet.MasterSubnetID || v.MasterSubnetID == subnet.ID {
+				children = append(children, v)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return diag.FromErr(fmt.Errorf("Could not find subnet %s after splitting", cidr))
+		}
+	}
+
+	d.SetId(strconv.Itoa(subnet.ID))
+	d.Set("subnet_id", subnet.ID)
+	flattenSubnetSplit(children, d)
+
+	return resourcePHPIPAMSubnetSplitRead(ctx, d, meta)
+}
+
+func resourcePHPIPAMSubnetSplitRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+
+	var children []subnets.Subnet
+	for _, v := range d.Get("subnet_ids").([]interface{}) {
+		out, err := c.GetSubnetByID(v.(int))
+		switch {
+		case err != nil && apiErrorCode(err) == 404:
+			log.Printf("[WARN] Subnet ID %d of subnet split %s not found", v.(int), d.Id())
+			continue
+		case err != nil:
+			return diag.FromErr(err)
+		}
+		children = append(children, out)
+	}
+
+	if len(children) == 0 {
+		log.Printf("[WARN] Subnets of subnet split %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	flattenSubnetSplit(children, d)
+	return nil
+}
+
+func resourcePHPIPAMSubnetSplitDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	log.Printf("[DEBUG] Removing subnet split %s from state, leaving subnets %v in place", d.Id(), d.Get("subnet_cidrs"))
+	d.SetId("")
+	return nil
+}
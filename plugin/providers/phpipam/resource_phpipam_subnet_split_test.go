@@ -0,0 +1,98 @@
+package phpipam
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourcePHPIPAMSubnetSplit(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.2.0", 24)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.2.200",
+	})
+
+	// The documented configuration looks the section up by name, and the
+	// subnet by its CIDR.
+	section := testFakeReadDataSource(t, meta, "phpipam_section", map[string]interface{}{
+		"name": "tf-test",
+	})
+	config := map[string]interface{}{
+		"section_id":     section.Attributes["section_id"],
+		"subnet_address": "10.10.2.0",
+		"subnet_mask":    24,
+		"number":         4,
+	}
+	state := testFakeApply(t, meta, "phpipam_subnet_split", nil, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_id":      strconv.Itoa(subnetID),
+		"subnet_ids.#":   "4",
+		"subnet_cidrs.#": "4",
+		"subnet_cidrs.0": "10.10.2.0/26",
+		"subnet_cidrs.1": "10.10.2.64/26",
+		"subnet_cidrs.3": "10.10.2.192/26",
+	})
+
+	// The addresses of the split subnet are moved to the new subnets.
+	c := meta.(*ProviderPHPIPAMClient).addressesController
+	addrs, err := c.GetAddressesByIP("10.10.2.200")
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if strconv.Itoa(addrs[0].SubnetID) != state.Attributes["subnet_ids.3"] {
+		t.Fatalf("expected address to be in subnet %s, got %d", state.Attributes["subnet_ids.3"], addrs[0].SubnetID)
+	}
+
+	// The split subnet is gone, but the configuration still plans without
+	// changes once its inputs are read again.
+	section = testFakeReadDataSource(t, meta, "phpipam_section", map[string]interface{}{
+		"name": "tf-test",
+	})
+	config["section_id"] = section.Attributes["section_id"]
+	state = testFakeApply(t, meta, "phpipam_subnet_split", state, config)
+
+	// Destroying the split leaves the subnets in place.
+	testFakeDestroy(t, meta, "phpipam_subnet_split", state)
+	lastID, _ := strconv.Atoi(state.Attributes["subnet_ids.3"])
+	if _, err := meta.(*ProviderPHPIPAMClient).subnetsController.GetSubnetByID(lastID); err != nil {
+		t.Fatalf("expected subnet to be left in place, got %s", err)
+	}
+}
+
+func TestResourcePHPIPAMSubnetSplitInvalid(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.2.0", 30)
+	subnet := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(subnetID)})
+	sectionID := subnet.Attributes["section_id"]
+
+	cases := []struct {
+		number int
+		err    string
+	}{
+		{3, "must be a power of two"},
+		{8, "too small to be split into 8 subnets"},
+	}
+	if _, err := testFakeApplyE(meta, "phpipam_subnet_split", nil, map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": "10.10.9.0",
+		"subnet_mask":    24,
+		"number":         2,
+	}); !strings.Contains(err, "No subnet found with CIDR 10.10.9.0/24") {
+		t.Fatalf("expected a not found error, got %q", err)
+	}
+	for _, tc := range cases {
+		_, err := testFakeApplyE(meta, "phpipam_subnet_split", nil, map[string]interface{}{
+			"section_id":     sectionID,
+			"subnet_address": "10.10.2.0",
+			"subnet_mask":    30,
+			"number":         tc.number,
+		})
+		if !strings.Contains(err, tc.err) {
+			t.Fatalf("%d: expected %q, got %q", tc.number, tc.err, err)
+		}
+	}
+}
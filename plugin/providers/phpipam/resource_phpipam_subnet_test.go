@@ -1,6 +1,7 @@
 package phpipam
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -66,13 +67,13 @@ resource "phpipam_subnet" "subnet" {
 `
 
 func TestAccResourcePHPIPAMSubnet(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMSubnetDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMSubnetDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMSubnetConfig,
@@ -83,18 +84,24 @@ func TestAccResourcePHPIPAMSubnet(t *testing.T) {
 					resource.TestCheckResourceAttr("phpipam_subnet.subnet", "description", "Terraform test subnet"),
 				),
 			},
+			resource.TestStep{
+				ResourceName:            "phpipam_subnet.subnet",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopt_existing", "configured_fields"},
+			},
 		},
 	})
 }
 
 func TestAccResourcePHPIPAMSubnet_CustomFields(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			sectionSweep("tf-test", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMSubnetDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMSubnetDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMSubnetCustomFieldConfig,
@@ -163,3 +170,299 @@ func testAccCheckResourcePHPIPAMSubnetDeleted(s *terraform.State) error {
 
 	return nil
 }
+
+func TestResourcePHPIPAMSubnetClearFields(t *testing.T) {
+	// Removed fields are only planned through the provider protocol, which
+	// supplies the configuration to the plan.
+	server, meta, _ := testProtoProvider(t)
+	section := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name": "tf-test",
+	})
+	sectionID, _ := strconv.Atoi(section.Attributes["section_id"])
+	vlan := testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+		"name":   "tf-test-vlan",
+		"number": 1000,
+	})
+	vlanID, _ := strconv.Atoi(vlan.ID)
+
+	fields := map[string]interface{}{
+		"description":           "Terraform test subnet",
+		"vlan_id":               vlanID,
+		"show_name":             true,
+		"utilization_threshold": 80,
+	}
+	empty := map[string]interface{}{
+		"description":           "",
+		"vlan_id":               0,
+		"show_name":             false,
+		"utilization_threshold": 0,
+	}
+	config := map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": "10.10.2.0",
+		"subnet_mask":    24,
+	}
+	for k, v := range fields {
+		config[k] = v
+	}
+	state := testProtoApply(t, server, "phpipam_subnet", testProtoNull(t, server, "phpipam_subnet"), config)
+	testProtoCheckAttrs(t, state, fields)
+	id := testProtoAttributes(t, state)["subnet_id"].(int)
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+
+	// Removing the fields from the configuration clears them.
+	for k := range fields {
+		delete(config, k)
+	}
+	state = testProtoApply(t, server, "phpipam_subnet", state, config)
+	testProtoCheckAttrs(t, state, empty)
+	out, err := c.GetSubnetByID(id)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if out.Description != "" || out.VLANID != 0 || out.ShowName || out.Threshold != 0 {
+		t.Fatalf("expected fields to be cleared, got %#v", out)
+	}
+
+	// Setting the fields to their zero values clears them as well.
+	for k, v := range fields {
+		config[k] = v
+	}
+	state = testProtoApply(t, server, "phpipam_subnet", state, config)
+	testProtoCheckAttrs(t, state, fields)
+	for k, v := range empty {
+		config[k] = v
+	}
+	state = testProtoApply(t, server, "phpipam_subnet", state, config)
+	testProtoCheckAttrs(t, state, empty)
+	out, err = c.GetSubnetByID(id)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if out.Description != "" || out.VLANID != 0 || out.ShowName || out.Threshold != 0 {
+		t.Fatalf("expected fields to be cleared, got %#v", out)
+	}
+}
+
+func TestResourcePHPIPAMSubnetKeepUnconfiguredFields(t *testing.T) {
+	server, meta, _ := testProtoProvider(t)
+	section := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name": "tf-test",
+	})
+	sectionID, _ := strconv.Atoi(section.Attributes["section_id"])
+	c := meta.(*ProviderPHPIPAMClient).subnetsController
+	check := func(id int) {
+		t.Helper()
+		out, err := c.GetSubnetByID(id)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		if out.Description != "Set outside Terraform" || !out.ShowName {
+			t.Fatalf("expected fields set outside Terraform to be kept, got %#v", out)
+		}
+	}
+	outside := map[string]interface{}{
+		"description": "Set outside Terraform",
+		"showName":    "1",
+	}
+
+	// Fields set outside Terraform, which have never been configured, are
+	// kept when the subnet is updated.
+	config := map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": "10.10.2.0",
+		"subnet_mask":    24,
+	}
+	state := testProtoApply(t, server, "phpipam_subnet", testProtoNull(t, server, "phpipam_subnet"), config)
+	id := testProtoAttributes(t, state)["subnet_id"].(int)
+	if _, err := c.UpdateSubnetFields(id, outside); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	state = testProtoRefresh(t, server, "phpipam_subnet", state)
+	config["utilization_threshold"] = 80
+	state = testProtoApply(t, server, "phpipam_subnet", state, config)
+	check(id)
+
+	// States without configured_fields, such as those of subnets applied
+	// with earlier versions of the provider, do not plan any change.
+	attrs := testProtoAttributes(t, state)
+	attrs["configured_fields"] = nil
+	upgraded := testProtoNewValue(t, state.Type(), attrs)
+	planned, err := testProtoPlan(t, server, "phpipam_subnet", upgraded, config)
+	if err != "" {
+		t.Fatalf("bad: %s", err)
+	}
+	if !planned.Equal(upgraded) {
+		diff, _ := upgraded.Diff(planned)
+		t.Fatalf("expected empty plan, got %v", diff)
+	}
+
+	// Adopting the subnet keeps them as well.
+	testProtoApply(t, server, "phpipam_subnet", testProtoNull(t, server, "phpipam_subnet"), map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": "10.10.2.0",
+		"subnet_mask":    24,
+		"adopt_existing": true,
+	})
+	check(id)
+}
+
+func TestResourcePHPIPAMSubnetResize(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.2.0", 25)
+	state := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(subnetID)})
+	sectionID, _ := strconv.Atoi(state.Attributes["section_id"])
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  subnetID,
+		"ip_address": "10.10.2.10",
+	})
+
+	cases := []struct {
+		address     string
+		mask        int
+		requiresNew bool
+	}{
+		{"10.10.2.0", 24, false},
+		{"10.10.2.0", 26, true},
+		{"10.10.2.0", 23, false},
+	}
+	for _, tc := range cases {
+		config := map[string]interface{}{
+			"section_id":     sectionID,
+			"subnet_address": tc.address,
+			"subnet_mask":    tc.mask,
+		}
+		r := Provider().ResourcesMap["phpipam_subnet"]
+		diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+		if err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+		if diff.RequiresNew() != tc.requiresNew {
+			t.Fatalf("%s/%d: expected RequiresNew %t, got %t", tc.address, tc.mask, tc.requiresNew, diff.RequiresNew())
+		}
+		if tc.requiresNew {
+			continue
+		}
+		state = testFakeApply(t, meta, "phpipam_subnet", state, config)
+		testFakeCheckAttrs(t, state, map[string]string{
+			"subnet_id":   strconv.Itoa(subnetID),
+			"subnet_mask": strconv.Itoa(tc.mask),
+		})
+	}
+
+	// A subnet that is not the network address with the new mask cannot be
+	// resized in place.
+	other := testFakeSubnet(t, meta, "10.10.4.128", 25)
+	otherState := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(other)})
+	r := Provider().ResourcesMap["phpipam_subnet"]
+	diff, err := r.Diff(context.Background(), otherState, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"section_id":     sectionID,
+		"subnet_address": "10.10.4.128",
+		"subnet_mask":    24,
+	}), meta)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if !diff.RequiresNew() {
+		t.Fatal("expected a misaligned resize to require a new subnet")
+	}
+}
+
+func TestResourcePHPIPAMSubnetMove(t *testing.T) {
+	meta, s := testFakeProviderMeta(t)
+	client := meta.(*ProviderPHPIPAMClient)
+	parentID := testFakeSubnet(t, meta, "10.20.0.0", 24)
+	parent := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(parentID)})
+	sectionID, _ := strconv.Atoi(parent.Attributes["section_id"])
+
+	// A subnet nested in the parent, with a child subnet and an address of its
+	// own.
+	config := map[string]interface{}{
+		"section_id":       sectionID,
+		"master_subnet_id": parentID,
+		"subnet_address":   "10.20.0.0",
+		"subnet_mask":      25,
+	}
+	state := testFakeApply(t, meta, "phpipam_subnet", nil, config)
+	subnetID, _ := strconv.Atoi(state.ID)
+	child := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":       sectionID,
+		"master_subnet_id": subnetID,
+		"subnet_address":   "10.20.0.0",
+		"subnet_mask":      26,
+	})
+	childID, _ := strconv.Atoi(child.ID)
+	testFakeApply(t, meta, "phpipam_address", nil, map[string]interface{}{
+		"subnet_id":  childID,
+		"ip_address": "10.20.0.10",
+	})
+
+	// Moving the subnet to a section that has an overlapping subnet at the
+	// same level fails when planning, as the section is in strict mode.
+	s.Insert("subnets", map[string]interface{}{
+		"subnet":         "10.20.0.0",
+		"mask":           "23",
+		"sectionId":      "1",
+		"masterSubnetId": "0",
+	})
+	delete(config, "master_subnet_id")
+	config["section_id"] = 1
+	r := Provider().ResourcesMap["phpipam_subnet"]
+	if _, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta); err == nil || !strings.Contains(err.Error(), "strict mode") {
+		t.Fatalf("expected a strict mode error, got %v", err)
+	}
+
+	// So does a master subnet that is in the old section.
+	config["section_id"] = 2
+	config["master_subnet_id"] = childID
+	if _, err := testFakeApplyE(meta, "phpipam_subnet", state, config); !strings.Contains(err, "another section") {
+		t.Fatalf("expected a master subnet error, got %q", err)
+	}
+	delete(config, "master_subnet_id")
+
+	// Moving the subnet to a section without overlapping subnets moves it to
+	// the top level of the section, along with its child subnet and address.
+	diff, err := r.Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), meta)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if diff.RequiresNew() {
+		t.Fatal("expected the subnet to be moved in place")
+	}
+	state = testFakeApply(t, meta, "phpipam_subnet", state, config)
+	testFakeCheckAttrs(t, state, map[string]string{
+		"subnet_id":        strconv.Itoa(subnetID),
+		"section_id":       "2",
+		"master_subnet_id": "0",
+	})
+	moved, err := client.subnetsController.GetSubnetByID(childID)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if moved.SectionID != 2 || moved.MasterSubnetID != subnetID {
+		t.Fatalf("expected child subnet in section 2 under subnet %d, got section %d under subnet %d", subnetID, moved.SectionID, moved.MasterSubnetID)
+	}
+	addrs, err := client.subnetsController.GetAddressesInSubnet(childID)
+	if err != nil || len(addrs) != 1 {
+		t.Fatalf("expected the address to stay in the child subnet, got %v, %v", addrs, err)
+	}
+}
+
+func TestResourcePHPIPAMSubnetAdoptExisting(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+	subnetID := testFakeSubnet(t, meta, "10.10.1.0", 24)
+	existing := testFakeRefresh(t, meta, "phpipam_subnet", &terraform.InstanceState{ID: strconv.Itoa(subnetID)})
+
+	adopted := testFakeApply(t, meta, "phpipam_subnet", nil, map[string]interface{}{
+		"section_id":     existing.Attributes["section_id"],
+		"subnet_address": "10.10.1.0",
+		"subnet_mask":    24,
+		"description":    "Terraform test subnet, adopted",
+		"adopt_existing": true,
+	})
+	testFakeCheckAttrs(t, adopted, map[string]string{
+		"subnet_id":   strconv.Itoa(subnetID),
+		"subnet_mask": "24",
+		"description": "Terraform test subnet, adopted",
+	})
+}
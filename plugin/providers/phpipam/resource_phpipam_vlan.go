@@ -22,7 +22,7 @@ func resourcePHPIPAMVLAN() *schema.Resource {
 		Delete: resourcePHPIPAMVLANDelete,
 		Schema: resourceVLANSchema(),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importNaturalKey(importVLANID),
 		},
 	}
 }
@@ -35,12 +35,29 @@ func resourcePHPIPAMVLANCreate(d *schema.ResourceData, meta interface{}) error {
 	in.ID = 0
 
 	var check_vlans []vlans.VLAN
+	domainID := in.DomainID
 	switch {
 	case in.Number != 0 && in.DomainID != 0:
 		check_vlans, _ = c.GetVLANsByNumberAndDomainID(in.Number, in.DomainID)
+	// PHPIPAM creates VLANs without an L2 domain in the default domain, so
+	// only VLANs in that domain are adopted, rather than VLANs with the same
+	// number in unrelated domains.
+	case in.Number != 0 && d.Get("adopt_existing").(bool):
+		domainID = defaultL2DomainID
+		check_vlans, _ = c.GetVLANsByNumberAndDomainID(in.Number, domainID)
 	case in.Number != 0:
 		check_vlans, _ = c.GetVLANsByNumber(in.Number)
 	}
+	// Adopt the existing VLAN with the same number and L2 domain, if
+	// requested, instead of failing.
+	if d.Get("adopt_existing").(bool) && len(check_vlans) > 1 {
+		return adoptExistingError("VLAN", fmt.Sprintf("number %d and l2_domain_id %d", in.Number, domainID), len(check_vlans))
+	}
+	if d.Get("adopt_existing").(bool) && len(check_vlans) == 1 {
+		d.SetId(strconv.Itoa(check_vlans[0].ID))
+		d.Set("vlan_id", check_vlans[0].ID)
+		return resourcePHPIPAMVLANUpdate(d, meta)
+	}
 	if len(check_vlans) != 0 {
 		return fmt.Errorf("VLAN with number: %d and l2_domain_id: %d already exists. Can't create VLAN", in.Number, in.DomainID)
 	}
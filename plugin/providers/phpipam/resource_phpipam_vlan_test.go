@@ -9,6 +9,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/vlans"
 )
 
 const testAccResourcePHPIPAMVLANName = "phpipam_vlan.vlan"
@@ -50,21 +51,27 @@ resource "phpipam_vlan" "vlan" {
 `
 
 const testAccResourcePHPIPAMVLANCustomFieldUpdateConfig = `
+resource "phpipam_l2domain" "tf-test-l2domain" {
+  name        = "tf-test-l2domain"
+  description = "Terraform test l2domain"
+}
+
 resource "phpipam_vlan" "vlan" {
-  name        = "terraform"
-  number      = 1999
-  description = "Terraform test vlan (custom field), step 2"
+  name         = "terraform"
+  number       = 1999
+  description  = "Terraform test vlan (custom field), step 2"
+  l2_domain_id = phpipam_l2domain.tf-test-l2domain.domain_id
 }
 `
 
 func TestAccResourcePHPIPAMVLAN(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			l2domainSweep("tf-test-l2domain", t)
 		},
-		Providers:    testAccProviders,
-		CheckDestroy: testAccCheckResourcePHPIPAMVLANDeleted,
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMVLANDeleted,
 		Steps: []resource.TestStep{
 			resource.TestStep{
 				Config: testAccResourcePHPIPAMVLANConfig,
@@ -75,44 +82,49 @@ func TestAccResourcePHPIPAMVLAN(t *testing.T) {
 					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "description", "Terraform test vlan"),
 				),
 			},
+			resource.TestStep{
+				ResourceName:            "phpipam_vlan.vlan",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"adopt_existing"},
+			},
 		},
 	})
 }
 
-/*
-	func TestAccResourcePHPIPAMVLAN_CustomFields(t *testing.T) {
-		resource.Test(t, resource.TestCase{
-	                PreCheck:  func() {
-	                        testAccPreCheck(t)
-	                        l2domainSweep("tf-test-l2domain", t)
-	                        },
-			Providers:    testAccProviders,
-			CheckDestroy: testAccCheckResourcePHPIPAMVLANDeleted,
-			Steps: []resource.TestStep{
-				resource.TestStep{
-					Config: testAccResourcePHPIPAMVLANCustomFieldConfig,
-					Check: resource.ComposeTestCheckFunc(
-						testAccCheckResourcePHPIPAMVLANCreated,
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "name", "terraform"),
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "number", "1999"),
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "description", "Terraform test vlan (custom field)"),
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "custom_fields.custom_CustomTestVLANs", "terraform-test"),
-					),
-				},
-				resource.TestStep{
-					Config: testAccResourcePHPIPAMVLANCustomFieldUpdateConfig,
-					Check: resource.ComposeTestCheckFunc(
-						testAccCheckResourcePHPIPAMVLANCreated,
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "name", "terraform"),
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "number", "1999"),
-						resource.TestCheckResourceAttr("phpipam_vlan.vlan", "description", "Terraform test vlan (custom field), step 2"),
-						resource.TestCheckNoResourceAttr("phpipam_vlan.vlan", "custom_fields.custom_CustomTestVLANs"),
-					),
-				},
+func TestAccResourcePHPIPAMVLAN_CustomFields(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			l2domainSweep("tf-test-l2domain", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMVLANDeleted,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMVLANCustomFieldConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMVLANCreated,
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "name", "terraform"),
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "number", "1999"),
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "description", "Terraform test vlan (custom field)"),
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "custom_fields.custom_CustomTestVLANs", "terraform-test"),
+				),
 			},
-		})
-	}
-*/
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMVLANCustomFieldUpdateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMVLANCreated,
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "name", "terraform"),
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "number", "1999"),
+					resource.TestCheckResourceAttr("phpipam_vlan.vlan", "description", "Terraform test vlan (custom field), step 2"),
+					resource.TestCheckNoResourceAttr("phpipam_vlan.vlan", "custom_fields.custom_CustomTestVLANs"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckResourcePHPIPAMVLANCreated(s *terraform.State) error {
 	r, ok := s.RootModule().Resources[testAccResourcePHPIPAMVLANName]
 	if !ok {
@@ -158,3 +170,64 @@ func testAccCheckResourcePHPIPAMVLANDeleted(s *terraform.State) error {
 
 	return nil
 }
+
+func TestResourcePHPIPAMVLANAdoptExisting(t *testing.T) {
+	meta, _ := testFakeProviderMeta(t)
+
+	config := map[string]interface{}{
+		"name":         "terraform",
+		"number":       testAccResourcePHPIPAMVLANNumber,
+		"l2_domain_id": 1,
+		"description":  "Terraform test vlan",
+	}
+	state := testFakeApply(t, meta, "phpipam_vlan", nil, config)
+
+	config["description"] = "Terraform test vlan, adopted"
+	if _, err := testFakeApplyE(meta, "phpipam_vlan", nil, config); !strings.Contains(err, "already exists") {
+		t.Fatalf("expected duplicate VLAN error, got %q", err)
+	}
+
+	config["adopt_existing"] = true
+	config["custom_fields"] = map[string]interface{}{
+		"custom_CustomTestVLANs": "terraform-test",
+	}
+	adopted := testFakeApply(t, meta, "phpipam_vlan", nil, config)
+	testFakeCheckAttrs(t, adopted, map[string]string{
+		"vlan_id":                              state.ID,
+		"description":                          "Terraform test vlan, adopted",
+		"custom_fields.custom_CustomTestVLANs": "terraform-test",
+	})
+
+	// Without l2_domain_id, only VLANs in the default L2 domain are adopted,
+	// not VLANs with the same number in other domains.
+	domain := testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name": "tf-test-domain",
+	})
+	testFakeApply(t, meta, "phpipam_vlan", nil, map[string]interface{}{
+		"name":         "other",
+		"number":       testAccResourcePHPIPAMVLANNumber,
+		"l2_domain_id": domain.ID,
+	})
+	config = map[string]interface{}{
+		"name":           "terraform",
+		"number":         testAccResourcePHPIPAMVLANNumber,
+		"adopt_existing": true,
+	}
+	adopted = testFakeApply(t, meta, "phpipam_vlan", nil, config)
+	testFakeCheckAttrs(t, adopted, map[string]string{
+		"vlan_id": state.ID,
+	})
+
+	// Several matches, which PHPIPAM allows, fail instead of adopting one of
+	// them.
+	c := meta.(*ProviderPHPIPAMClient).vlansController
+	for i := 0; i < 2; i++ {
+		if _, err := c.CreateVLAN(vlans.VLAN{Name: "duplicate", Number: testAccResourcePHPIPAMVLANNumber + 1, DomainID: 1}); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+	}
+	config["number"] = testAccResourcePHPIPAMVLANNumber + 1
+	if _, err := testFakeApplyE(meta, "phpipam_vlan", nil, config); !strings.Contains(err, "2 objects found with number") {
+		t.Fatalf("expected several matches error, got %q", err)
+	}
+}
@@ -0,0 +1,278 @@
+package phpipam
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/vrfs"
+)
+
+// vrfResource is the phpipam_vrf resource, implemented with
+// terraform-plugin-framework.
+//
+// The optional fields are not computed: removing one from the configuration
+// clears it in PHPIPAM.
+type vrfResource struct {
+	client *ProviderPHPIPAMClient
+}
+
+// vrfResourceModel is the state of a phpipam_vrf resource. It is compatible
+// with the state of the SDK implementation of the resource.
+type vrfResourceModel struct {
+	ID           types.String `tfsdk:"id"`
+	VRFID        types.Int64  `tfsdk:"vrf_id"`
+	Name         types.String `tfsdk:"name"`
+	RD           types.String `tfsdk:"rd"`
+	Description  types.String `tfsdk:"description"`
+	Sections     types.String `tfsdk:"sections"`
+	EditDate     types.String `tfsdk:"edit_date"`
+	CustomFields types.Map    `tfsdk:"custom_fields"`
+}
+
+// newVRFResource returns a new phpipam_vrf resource.
+func newVRFResource() resource.Resource {
+	return &vrfResource{}
+}
+
+func (r *vrfResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_vrf"
+}
+
+func (r *vrfResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a VRF in PHPIPAM.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+			},
+			"vrf_id": schema.Int64Attribute{
+				Description:   "The ID of the VRF.",
+				Computed:      true,
+				PlanModifiers: []planmodifier.Int64{int64planmodifier.UseStateForUnknown()},
+			},
+			"name": schema.StringAttribute{
+				Description: "The name of the VRF.",
+				Required:    true,
+			},
+			"rd": schema.StringAttribute{
+				Description: "The route distinguisher of the VRF.",
+				Optional:    true,
+			},
+			"description": schema.StringAttribute{
+				Description: "The description of the VRF.",
+				Optional:    true,
+			},
+			"sections": schema.StringAttribute{
+				Description: "The IDs of the sections the VRF is available in, separated by semicolons. The VRF is available in all sections if this is not set.",
+				Optional:    true,
+			},
+			"edit_date": schema.StringAttribute{
+				Description: "The date of the last edit to the VRF.",
+				Computed:    true,
+			},
+			"custom_fields": schema.MapAttribute{
+				Description: "The custom fields of the VRF, by name.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		},
+	}
+}
+
+func (r *vrfResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData != nil {
+		r.client = req.ProviderData.(*ProviderPHPIPAMClient)
+	}
+}
+
+func (r *vrfResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan vrfResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	c := r.client.vrfsController
+
+	if _, err := c.CreateVRF(expandVRFModel(plan)); err != nil {
+		resp.Diagnostics.AddError("Could not create VRF", err.Error())
+		return
+	}
+
+	// The API does not return the ID of the new VRF, so look it up by name.
+	out, err := c.GetVRFsByName(plan.Name.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Could not read VRF after creating", err.Error())
+		return
+	}
+	if len(out) != 1 {
+		resp.Diagnostics.AddError("Could not read VRF after creating", "VRF either missing or multiple results returned by reading VRF after creation")
+		return
+	}
+	plan.ID = types.StringValue(strconv.Itoa(out[0].ID))
+	plan.VRFID = types.Int64Value(int64(out[0].ID))
+	// Save the ID right away, so that the VRF is not lost if setting the
+	// custom fields fails.
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), plan.ID)...)
+
+	if len(plan.CustomFields.Elements()) > 0 {
+		fields, diags := frameworkCustomFieldsUpdate(ctx, plan.CustomFields, nil)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if _, err := c.UpdateVRFCustomFields(out[0].ID, fields); err != nil {
+			resp.Diagnostics.AddError("Could not set custom fields of VRF", err.Error())
+			return
+		}
+	}
+
+	if found := r.read(ctx, &plan, &resp.Diagnostics); !found && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError("Could not read VRF after creating", fmt.Sprintf("VRF %d not found", out[0].ID))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vrfResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state vrfResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if found := r.read(ctx, &state, &resp.Diagnostics); !found {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *vrfResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan vrfResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	c := r.client.vrfsController
+	id := int(plan.VRFID.ValueInt64())
+
+	in := expandVRFModel(plan)
+	in.ID = id
+	if _, err := c.UpdateVRF(in); err != nil {
+		resp.Diagnostics.AddError("Could not update VRF", err.Error())
+		return
+	}
+
+	var state vrfResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if !plan.CustomFields.Equal(state.CustomFields) {
+		old, err := c.GetVRFCustomFields(id)
+		if err != nil {
+			resp.Diagnostics.AddError("Could not read custom fields of VRF", err.Error())
+			return
+		}
+		fields, diags := frameworkCustomFieldsUpdate(ctx, plan.CustomFields, old)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(fields) > 0 {
+			if _, err := c.UpdateVRFCustomFields(id, fields); err != nil {
+				resp.Diagnostics.AddError("Could not update custom fields of VRF", err.Error())
+				return
+			}
+		}
+	}
+
+	if found := r.read(ctx, &plan, &resp.Diagnostics); !found && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError("Could not read VRF after updating", fmt.Sprintf("VRF %d not found", id))
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *vrfResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state vrfResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if _, err := r.client.vrfsController.DeleteVRF(int(state.VRFID.ValueInt64())); err != nil && apiErrorCode(err) != 404 {
+		resp.Diagnostics.AddError("Could not delete VRF", err.Error())
+	}
+}
+
+// ImportState imports a VRF by its ID.
+func (r *vrfResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, err := strconv.Atoi(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid VRF import ID", fmt.Sprintf("Invalid VRF import ID %q, expected a VRF ID", req.ID))
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// read reads the VRF with the ID in m into m. It returns false if the VRF
+// cannot be found, or if reading it fails, in which case an error is added to
+// diags.
+func (r *vrfResource) read(ctx context.Context, m *vrfResourceModel, diags *diag.Diagnostics) bool {
+	c := r.client.vrfsController
+	id, err := strconv.Atoi(m.ID.ValueString())
+	if err != nil {
+		diags.AddError("Invalid VRF ID", fmt.Sprintf("Invalid VRF ID %q", m.ID.ValueString()))
+		return false
+	}
+	out, err := c.GetVRFByID(id)
+	switch {
+	case err != nil && apiErrorCode(err) == 404:
+		return false
+	case err != nil:
+		diags.AddError("Could not read VRF", err.Error())
+		return false
+	}
+
+	fields := make(map[string]interface{})
+	if checkVRFsCustomFieldsExists(c) {
+		fields, err = c.GetVRFCustomFields(out.ID)
+		if err != nil {
+			diags.AddError("Could not read custom fields of VRF", err.Error())
+			return false
+		}
+	}
+	customFields, d := frameworkCustomFields(ctx, m.CustomFields, fields)
+	diags.Append(d...)
+
+	m.VRFID = types.Int64Value(int64(out.ID))
+	m.Name = types.StringValue(out.Name)
+	m.RD = frameworkString(m.RD, out.RD)
+	m.Description = frameworkString(m.Description, out.Description)
+	m.Sections = frameworkString(m.Sections, out.Sections)
+	m.EditDate = types.StringValue(out.EditDate)
+	m.CustomFields = customFields
+	return !diags.HasError()
+}
+
+// expandVRFModel returns the vrfs.VRF structure for a phpipam_vrf resource.
+// Fields that are not set are sent as empty values, which clears them.
+func expandVRFModel(m vrfResourceModel) vrfs.VRF {
+	return vrfs.VRF{
+		Name:        m.Name.ValueString(),
+		RD:          m.RD.ValueString(),
+		Description: m.Description.ValueString(),
+		Sections:    m.Sections.ValueString(),
+	}
+}
@@ -0,0 +1,162 @@
+package phpipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourcePHPIPAMVRFResourceName = "phpipam_vrf.vrf"
+const testAccResourcePHPIPAMVRFName = "tf-test-vrf"
+const testAccResourcePHPIPAMVRFConfig = `
+resource "phpipam_vrf" "vrf" {
+  name        = "tf-test-vrf"
+  rd          = "65000:100"
+  description = "Terraform test vrf"
+}
+`
+
+const testAccResourcePHPIPAMVRFUpdateConfig = `
+resource "phpipam_vrf" "vrf" {
+  name        = "tf-test-vrf"
+  rd          = "65000:200"
+  description = "Terraform test vrf, step 2"
+}
+`
+
+func TestAccResourcePHPIPAMVRF(t *testing.T) {
+	testAccTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			vrfSweep("tf-test-vrf", t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckResourcePHPIPAMVRFDeleted,
+		Steps: []resource.TestStep{
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMVRFConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMVRFCreated,
+					resource.TestCheckResourceAttr("phpipam_vrf.vrf", "name", "tf-test-vrf"),
+					resource.TestCheckResourceAttr("phpipam_vrf.vrf", "rd", "65000:100"),
+					resource.TestCheckResourceAttr("phpipam_vrf.vrf", "description", "Terraform test vrf"),
+				),
+			},
+			resource.TestStep{
+				Config: testAccResourcePHPIPAMVRFUpdateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckResourcePHPIPAMVRFCreated,
+					resource.TestCheckResourceAttr("phpipam_vrf.vrf", "rd", "65000:200"),
+					resource.TestCheckResourceAttr("phpipam_vrf.vrf", "description", "Terraform test vrf, step 2"),
+				),
+			},
+			resource.TestStep{
+				ResourceName:      "phpipam_vrf.vrf",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckResourcePHPIPAMVRFCreated(s *terraform.State) error {
+	r, ok := s.RootModule().Resources[testAccResourcePHPIPAMVRFResourceName]
+	if !ok {
+		return fmt.Errorf("Resource name %s could not be found", testAccResourcePHPIPAMVRFResourceName)
+	}
+	if r.Primary.ID == "" {
+		return errors.New("No ID is set")
+	}
+
+	id, _ := strconv.Atoi(r.Primary.ID)
+
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).vrfsController
+	if _, err := c.GetVRFByID(id); err != nil {
+		return err
+	}
+	return nil
+}
+
+func testAccCheckResourcePHPIPAMVRFDeleted(s *terraform.State) error {
+	c := testAccProvider.Meta().(*ProviderPHPIPAMClient).vrfsController
+	_, err := c.GetVRFsByName(testAccResourcePHPIPAMVRFName)
+	switch {
+	case err == nil:
+		return errors.New("Expected error, got none")
+	case err != nil && err.Error() != "Error from API (404): No results (filter applied)":
+		return fmt.Errorf("Expected 404, got %s", err)
+	}
+
+	return nil
+}
+
+func TestResourcePHPIPAMVRFClearFields(t *testing.T) {
+	server, meta, s := testProtoProvider(t)
+	s.AddCustomField("vrf", "custom_CustomTestVRFs", "varchar(255)")
+
+	config := map[string]interface{}{
+		"name":        testAccResourcePHPIPAMVRFName,
+		"rd":          "65000:100",
+		"description": "Terraform test vrf",
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestVRFs": "foo",
+		},
+	}
+	state := testProtoApply(t, server, "phpipam_vrf", testProtoNull(t, server, "phpipam_vrf"), config)
+	testProtoCheckAttrs(t, state, map[string]interface{}{
+		"name":        testAccResourcePHPIPAMVRFName,
+		"rd":          "65000:100",
+		"description": "Terraform test vrf",
+		"sections":    nil,
+		"custom_fields": map[string]interface{}{
+			"custom_CustomTestVRFs": "foo",
+		},
+	})
+
+	id := testProtoAttributes(t, state)["id"].(string)
+
+	// Removing optional fields from the configuration clears them.
+	delete(config, "description")
+	delete(config, "custom_fields")
+	config["rd"] = ""
+	state = testProtoApply(t, server, "phpipam_vrf", state, config)
+	testProtoCheckAttrs(t, state, map[string]interface{}{
+		"rd":            "",
+		"description":   nil,
+		"custom_fields": nil,
+	})
+	c := meta.(*ProviderPHPIPAMClient).vrfsController
+	vrfID, _ := strconv.Atoi(id)
+	out, err := c.GetVRFByID(vrfID)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if out.RD != "" || out.Description != "" {
+		t.Fatalf("expected rd and description to be cleared, got %q and %q", out.RD, out.Description)
+	}
+	fields, err := c.GetVRFCustomFields(vrfID)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if trimMap(fields); len(fields) != 0 {
+		t.Fatalf("expected custom fields to be cleared, got %v", fields)
+	}
+}
+
+func TestResourcePHPIPAMVRFImportInvalidID(t *testing.T) {
+	server, _, _ := testProtoProvider(t)
+	resp, err := server.ImportResourceState(context.Background(), &tfprotov5.ImportResourceStateRequest{TypeName: "phpipam_vrf", ID: "foo"})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if diags := testProtoDiagsError(resp.Diagnostics); !strings.Contains(diags, "expected a VRF ID") {
+		t.Fatalf("expected invalid ID error, got %q", diags)
+	}
+}
@@ -0,0 +1,194 @@
+package phpipam
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryConfig controls how requests that fail with transient errors are
+// retried.
+type RetryConfig struct {
+	// The maximum number of attempts made for a request, including the first
+	// one. A value of 1 or less disables retries.
+	MaxAttempts int
+
+	// The delay before the first retry. The delay doubles with every retry,
+	// up to MaxBackoff.
+	MinBackoff time.Duration
+
+	// The maximum delay between two attempts.
+	MaxBackoff time.Duration
+
+	// The HTTP status codes that are considered transient, such as the 502
+	// and 503 returned by a reverse proxy when PHPIPAM is unavailable.
+	RetryableStatusCodes []int
+}
+
+// apiErrorCodeRegexp matches the HTTP status code in the errors returned by
+// the SDK, both for API responses ("Error from API (500): ...") and for
+// responses that are not from the API, such as a proxy error page
+// ("Non-API error (502 Bad Gateway): ...").
+var apiErrorCodeRegexp = regexp.MustCompile(`(?:Error from API|Non-API error) \((\d{3})`)
+
+// retryClass describes whether an error is transient, and whether the failed
+// request could have been applied by PHPIPAM.
+type retryClass int
+
+const (
+	// The error is not transient, and the request should not be retried.
+	retryNever retryClass = iota
+
+	// The error is transient, and the request was not applied, so any request
+	// can safely be sent again.
+	retryUnapplied
+
+	// The error is transient, but the request may have been applied, so only
+	// idempotent requests can be sent again as is.
+	retryAmbiguous
+)
+
+// apiErrorCode returns the HTTP status code in an error returned by the SDK,
+// or 0 if there is none.
+func apiErrorCode(err error) int {
+	m := apiErrorCodeRegexp.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0
+	}
+	code, _ := strconv.Atoi(m[1])
+	return code
+}
+
+// classify returns the retryClass of err.
+func (c RetryConfig) classify(err error) retryClass {
+	msg := err.Error()
+	switch {
+	// Failing to log in or to refresh the session token means that the
+	// request itself was never sent.
+	case strings.HasPrefix(msg, "Error logging into PHPIPAM"),
+		strings.HasPrefix(msg, "Error refreshing expired PHPIPAM session token"):
+		if c.classify(errors.New(strings.SplitN(msg, ": ", 2)[1])) == retryNever {
+			return retryNever
+		}
+		return retryUnapplied
+	// MySQL rolls back the whole transaction of the deadlock victim.
+	case strings.Contains(msg, "Deadlock found when trying to get lock"):
+		return retryUnapplied
+	case strings.HasPrefix(msg, "HTTP protocol error"):
+		if strings.Contains(msg, "connection refused") {
+			return retryUnapplied
+		}
+		return retryAmbiguous
+	case strings.Contains(msg, "Lock wait timeout exceeded"):
+		return retryAmbiguous
+	}
+	code := apiErrorCode(err)
+	for _, v := range c.RetryableStatusCodes {
+		if code == v {
+			return retryAmbiguous
+		}
+	}
+	return retryNever
+}
+
+// backoff returns the delay before the supplied retry, starting at 1. The
+// delay is jittered, so that parallel requests that failed together do not
+// all retry at the same time.
+func (c RetryConfig) backoff(retry int) time.Duration {
+	d := c.MinBackoff
+	for i := 1; i < retry && d < c.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > c.MaxBackoff {
+		d = c.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retry calls f, which must make an idempotent request (GET, or a PATCH or
+// DELETE of a known object), and calls it again if it fails with a transient
+// error, until it succeeds or MaxAttempts is reached.
+func (c RetryConfig) retry(f func() error) error {
+	return c.retryRequest(f, true, nil)
+}
+
+// retryDelete is like retry, for DELETE requests. If an attempt fails in a
+// way that leaves it unclear whether the object was deleted, a "not found"
+// error on a later attempt means that it was, and is not returned.
+func (c RetryConfig) retryDelete(f func() error) error {
+	var ambiguous bool
+	return c.retryRequest(func() error {
+		err := f()
+		switch {
+		case err == nil:
+		case ambiguous && apiErrorCode(err) == 404:
+			log.Printf("[DEBUG] Object not found when retrying DELETE, treating it as deleted: %s", err)
+			return nil
+		case c.classify(err) == retryAmbiguous:
+			ambiguous = true
+		}
+		return err
+	}, true, nil)
+}
+
+// retryCreate calls f, which must make a single POST request that creates an
+// object. POST requests are not idempotent, so f is only called again as is
+// if PHPIPAM did not apply the failed request. Otherwise exists is called to
+// check whether the object was created after all, and f is only called again
+// if it was not. When exists is nil, or fails, such failures are not retried.
+func (c RetryConfig) retryCreate(f func() error, exists func() (bool, error)) error {
+	return c.retryRequest(f, false, exists)
+}
+
+// retryRequest implements retry and retryCreate.
+func (c RetryConfig) retryRequest(f func() error, idempotent bool, exists func() (bool, error)) error {
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= c.MaxAttempts {
+			return err
+		}
+		switch c.classify(err) {
+		case retryNever:
+			return err
+		case retryAmbiguous:
+			if idempotent {
+				break
+			}
+			if exists == nil {
+				log.Printf("[DEBUG] Not retrying request that may have been applied: %s", err)
+				return err
+			}
+			created, cerr := exists()
+			if cerr != nil {
+				log.Printf("[DEBUG] Could not check whether failed request was applied: %s", cerr)
+				return err
+			}
+			if created {
+				log.Printf("[DEBUG] Request failed, but the object was created: %s", err)
+				return nil
+			}
+		}
+		d := c.backoff(attempt)
+		log.Printf("[DEBUG] Retrying request in %s after attempt %d of %d failed: %s", d, attempt, c.MaxAttempts, err)
+		time.Sleep(d)
+	}
+}
+
+// found returns whether a lookup done to check if an object exists found n
+// objects, treating "not found" errors as no objects.
+func found(n int, err error) (bool, error) {
+	switch {
+	case err != nil && apiErrorCode(err) == 404:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return n > 0, nil
+}
@@ -0,0 +1,989 @@
+package phpipam
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/devices"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/locations"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/nameservers"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/tags"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/vrfs"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/l2domains"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/sections"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/vlans"
+	"github.com/pavel-z1/phpipam-sdk-go/phpipam"
+)
+
+// The controllers in this file wrap the API controllers used by the provider,
+// so that every request made through ProviderPHPIPAMClient is retried on
+// transient errors. Only the wrapped methods can be called, which ensures that
+// no request bypasses the retry settings: a new controller method needs a
+// wrapper here before the provider can use it.
+//
+// GET requests, and PATCH and DELETE requests of a known object, are
+// idempotent and are retried as is. POST requests that create an object are
+// only replayed when PHPIPAM did not apply the failed request, or when a
+// lookup shows that the object was not created after all.
+
+// addressesController wraps addresses.Controller, retrying requests to the IP
+// addresses controller according to the provider's retry settings.
+type addressesController struct {
+	controller *addresses.Controller
+	retry      RetryConfig
+}
+
+func (c *addressesController) CreateAddress(in addresses.Address) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateAddress(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetAddressesByIpInSubnet(in.IPAddress, in.SubnetID)
+		return found(out.ID, err)
+	})
+	return
+}
+
+func (c *addressesController) CreateFirstFreeAddress(id int, in addresses.Address) (out string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		out, err = c.controller.CreateFirstFreeAddress(id, in)
+		return
+	}, nil)
+	return
+}
+
+func (c *addressesController) GetAddressByID(id int) (out addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressByID(id)
+		return
+	})
+	return
+}
+
+func (c *addressesController) GetAddressesByIP(ipaddr string) (out []addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressesByIP(ipaddr)
+		return
+	})
+	return
+}
+
+func (c *addressesController) GetAddressesByIpInSubnet(ipaddr string, subnetID int) (out addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressesByIpInSubnet(ipaddr, subnetID)
+		return
+	})
+	return
+}
+
+// SearchAddressesByHostname GETs the addresses with the host name hostname,
+// in all subnets. This method is not part of the SDK's addresses controller, so
+// the request is sent directly.
+func (c *addressesController) SearchAddressesByHostname(hostname string) (out []addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", fmt.Sprintf("/addresses/search_hostname/%s/", url.PathEscape(hostname)), &struct{}{}, &out)
+	})
+	return
+}
+
+// SearchAddressesByMAC GETs the addresses with the MAC address mac, in all
+// subnets. This method is not part of the SDK's addresses controller, so the
+// request is sent directly.
+func (c *addressesController) SearchAddressesByMAC(mac string) (out []addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", fmt.Sprintf("/addresses/search_mac/%s/", url.PathEscape(mac)), &struct{}{}, &out)
+	})
+	return
+}
+
+func (c *addressesController) GetAddressCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressCustomFieldsSchema()
+		return
+	})
+	return
+}
+
+func (c *addressesController) GetAddressCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressCustomFields(id)
+		return
+	})
+	return
+}
+
+func (c *addressesController) GetCustomFieldsSchema(controller string) (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetCustomFieldsSchema(controller)
+		return
+	})
+	return
+}
+
+func (c *addressesController) UpdateAddress(in addresses.Address) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateAddress(in)
+		return
+	})
+	return
+}
+
+// UpdateAddressFields sets the fields in in, by their names in the API, on the
+// address with the supplied ID. Unlike UpdateAddress, this can also set fields
+// to empty values, which clears them.
+func (c *addressesController) UpdateAddressFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/addresses/%d/", id), &in, &message)
+	})
+	return
+}
+
+func (c *addressesController) UpdateAddressCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateAddressCustomFields(id, in)
+		return
+	})
+	return
+}
+
+func (c *addressesController) DeleteAddress(id int, removeDNS phpipam.BoolIntString) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteAddress(id, removeDNS)
+		return
+	})
+	return
+}
+
+// sectionsController wraps sections.Controller, retrying requests to the
+// sections controller according to the provider's retry settings.
+type sectionsController struct {
+	controller *sections.Controller
+	retry      RetryConfig
+}
+
+func (c *sectionsController) ListSections() (out []sections.Section, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListSections()
+		return
+	})
+	return
+}
+
+func (c *sectionsController) CreateSection(in sections.Section) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateSection(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetSectionByName(in.Name)
+		return found(out.ID, err)
+	})
+	return
+}
+
+func (c *sectionsController) GetSectionByID(id int) (out sections.Section, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSectionByID(id)
+		return
+	})
+	return
+}
+
+func (c *sectionsController) GetSectionByName(name string) (out sections.Section, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSectionByName(name)
+		return
+	})
+	return
+}
+
+func (c *sectionsController) GetSubnetsInSection(id int) (out []subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetsInSection(id)
+		return
+	})
+	return
+}
+
+// GetSectionCustomFields GETs the custom fields of a section. This method is
+// not part of the SDK's sections controller, so the generic client method is
+// used directly.
+func (c *sectionsController) GetSectionCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetCustomFields(id, "sections")
+		return
+	})
+	return
+}
+
+func (c *sectionsController) UpdateSection(in sections.Section) (err error) {
+	err = c.retry.retry(func() (err error) {
+		err = c.controller.UpdateSection(in)
+		return
+	})
+	return
+}
+
+func (c *sectionsController) DeleteSection(id int) (err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		err = c.controller.DeleteSection(id)
+		return
+	})
+	return
+}
+
+// l2domainsController wraps l2domains.Controller, retrying requests to the L2
+// domains controller according to the provider's retry settings.
+type l2domainsController struct {
+	controller *l2domains.Controller
+	retry      RetryConfig
+}
+
+func (c *l2domainsController) ListL2Domains() (out []l2domains.L2Domain, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListL2Domains()
+		return
+	})
+	return
+}
+
+func (c *l2domainsController) CreateL2Domain(in l2domains.L2Domain) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateL2Domain(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetL2DomainByName(in.Name)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *l2domainsController) GetL2DomainByID(id int) (out l2domains.L2Domain, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetL2DomainByID(id)
+		return
+	})
+	return
+}
+
+func (c *l2domainsController) GetL2DomainByName(name string) (out []l2domains.L2Domain, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetL2DomainByName(name)
+		return
+	})
+	return
+}
+
+func (c *l2domainsController) GetVlansInl2Domain(id int) (out []vlans.VLAN, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVlansInl2Domain(id)
+		return
+	})
+	return
+}
+
+// GetL2DomainCustomFields GETs the custom fields of an L2 domain. This method
+// is not part of the SDK's L2 domains controller, so the generic client
+// method is used directly.
+func (c *l2domainsController) GetL2DomainCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetCustomFields(id, "l2domains")
+		return
+	})
+	return
+}
+
+func (c *l2domainsController) UpdateL2Domain(in l2domains.L2Domain) (err error) {
+	err = c.retry.retry(func() (err error) {
+		err = c.controller.UpdateL2Domain(in)
+		return
+	})
+	return
+}
+
+func (c *l2domainsController) DeleteL2Domain(id int) (err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		err = c.controller.DeleteL2Domain(id)
+		return
+	})
+	return
+}
+
+// subnetsController wraps subnets.Controller, retrying requests to the subnets
+// controller according to the provider's retry settings.
+type subnetsController struct {
+	controller *subnets.Controller
+	retry      RetryConfig
+}
+
+func (c *subnetsController) CreateSubnet(in subnets.Subnet) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateSubnet(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetSubnetsByCIDRAndSection(fmt.Sprintf("%s/%d", in.SubnetAddress, in.Mask), in.SectionID)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *subnetsController) CreateFirstFreeSubnet(id int, mask int, in subnets.Subnet) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateFirstFreeSubnet(id, mask, in)
+		return
+	}, nil)
+	return
+}
+
+func (c *subnetsController) GetSubnetByID(id int) (out subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetByID(id)
+		return
+	})
+	return
+}
+
+func (c *subnetsController) GetSubnetsByCIDR(cidr string) (out []subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetsByCIDR(cidr)
+		return
+	})
+	return
+}
+
+func (c *subnetsController) GetSubnetsByCIDRAndSection(cidr string, sectionID int) (out []subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetsByCIDRAndSection(cidr, sectionID)
+		return
+	})
+	return
+}
+
+func (c *subnetsController) GetFirstFreeSubnet(id int, mask int) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.GetFirstFreeSubnet(id, mask)
+		return
+	})
+	return
+}
+
+func (c *subnetsController) GetFirstFreeAddress(id int) (out string, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetFirstFreeAddress(id)
+		return
+	})
+	return
+}
+
+func (c *subnetsController) GetAddressesInSubnet(id int) (out []addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressesInSubnet(id)
+		return
+	})
+	return
+}
+
+// GetAddressesInSubnetByField GETs the addresses in a subnet whose field, by
+// its name in the API, has the supplied value. The filter is applied by
+// PHPIPAM, which also filters on custom fields unless they are nested. This
+// method is not part of the SDK's subnets controller, so the request is sent
+// directly.
+func (c *subnetsController) GetAddressesInSubnetByField(id int, field, value string) (out []addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", fmt.Sprintf("/subnets/%d/addresses/?filter_by=%s&filter_value=%s", id, url.QueryEscape(field), url.QueryEscape(value)), &struct{}{}, &out)
+	})
+	return
+}
+
+// GetSubnetsInSubnet GETs the direct child subnets of a subnet. This method is
+// not part of the SDK's subnets controller, so the request is sent directly.
+func (c *subnetsController) GetSubnetsInSubnet(id int) (out []subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", fmt.Sprintf("/subnets/%d/slaves/", id), &struct{}{}, &out)
+	})
+	return
+}
+
+// GetSubnetsInSubnetRecursive GETs all subnets nested in a subnet, at any
+// depth. This method is not part of the SDK's subnets controller, so the
+// request is sent directly.
+func (c *subnetsController) GetSubnetsInSubnetRecursive(id int) (out []subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", fmt.Sprintf("/subnets/%d/slaves_recursive/", id), &struct{}{}, &out)
+	})
+	return
+}
+
+// GetSubnetUsage GETs the usage of a subnet. This method is not part of the
+// SDK's subnets controller, so the request is sent directly.
+func (c *subnetsController) GetSubnetUsage(id int) (out SubnetUsage, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", fmt.Sprintf("/subnets/%d/usage/", id), &struct{}{}, &out)
+	})
+	return
+}
+
+func (c *subnetsController) GetSubnetCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetCustomFieldsSchema()
+		return
+	})
+	return
+}
+
+func (c *subnetsController) GetSubnetCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetCustomFields(id)
+		return
+	})
+	return
+}
+
+func (c *subnetsController) UpdateSubnet(in subnets.Subnet) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateSubnet(in)
+		return
+	})
+	return
+}
+
+// UpdateSubnetFields sets the fields in in, by their names in the API, on the
+// subnet with the supplied ID. Unlike UpdateSubnet, this can also set fields to
+// empty values, which clears them.
+func (c *subnetsController) UpdateSubnetFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/subnets/%d/", id), &in, &message)
+	})
+	return
+}
+
+func (c *subnetsController) UpdateSubnetCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateSubnetCustomFields(id, in)
+		return
+	})
+	return
+}
+
+// ResizeSubnet changes the mask of a subnet, keeping its address. This method
+// is not part of the SDK's subnets controller, so the request is sent
+// directly.
+func (c *subnetsController) ResizeSubnet(id int, mask int) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/subnets/%d/resize/", id), &map[string]int{"mask": mask}, &message)
+	})
+	return
+}
+
+// SplitSubnet splits a subnet into number subnets of equal size. This method
+// is not part of the SDK's subnets controller, so the request is sent
+// directly. A split replaces the subnet, so a failed split was applied if the
+// subnet is gone.
+func (c *subnetsController) SplitSubnet(id int, number int) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/subnets/%d/split/", id), &map[string]int{"number": number}, &message)
+	}, func() (bool, error) {
+		_, err := c.controller.GetSubnetByID(id)
+		if err != nil && apiErrorCode(err) == 404 {
+			return true, nil
+		}
+		return false, err
+	})
+	return
+}
+
+// MoveSubnet sets the section and master subnet fields of a subnet, from in.
+// Unlike UpdateSubnet, this can also set the master subnet to 0, which moves a
+// subnet to the top level of its section.
+func (c *subnetsController) MoveSubnet(id int, in map[string]int) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("PATCH", fmt.Sprintf("/subnets/%d/", id), &in, &message)
+	})
+	return
+}
+
+func (c *subnetsController) DeleteSubnet(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteSubnet(id)
+		return
+	})
+	return
+}
+
+// vlansController wraps vlans.Controller, retrying requests to the VLANs
+// controller according to the provider's retry settings.
+type vlansController struct {
+	controller *vlans.Controller
+	retry      RetryConfig
+}
+
+// ListVLANs GETs all VLANs. This method is not part of the SDK's VLANs
+// controller, so the request is sent directly.
+func (c *vlansController) ListVLANs() (out []vlans.VLAN, err error) {
+	err = c.retry.retry(func() (err error) {
+		return c.controller.SendRequest("GET", "/vlans/", &struct{}{}, &out)
+	})
+	return
+}
+
+func (c *vlansController) CreateVLAN(in vlans.VLAN) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateVLAN(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetVLANsByNumberAndDomainID(in.Number, in.DomainID)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *vlansController) GetVLANByID(id int) (out vlans.VLAN, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVLANByID(id)
+		return
+	})
+	return
+}
+
+func (c *vlansController) GetVLANsByNumber(number int) (out []vlans.VLAN, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVLANsByNumber(number)
+		return
+	})
+	return
+}
+
+func (c *vlansController) GetVLANsByNumberAndDomainID(number int, domainID int) (out []vlans.VLAN, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVLANsByNumberAndDomainID(number, domainID)
+		return
+	})
+	return
+}
+
+func (c *vlansController) GetVLANCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVLANCustomFieldsSchema()
+		return
+	})
+	return
+}
+
+func (c *vlansController) GetVLANCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVLANCustomFields(id)
+		return
+	})
+	return
+}
+
+func (c *vlansController) UpdateVLAN(in vlans.VLAN) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateVLAN(in)
+		return
+	})
+	return
+}
+
+func (c *vlansController) UpdateVLANCustomFields(id int, name string, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateVLANCustomFields(id, name, in)
+		return
+	})
+	return
+}
+
+func (c *vlansController) DeleteVLAN(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteVLAN(id)
+		return
+	})
+	return
+}
+
+// vrfsController wraps vrfs.Controller, retrying requests to the VRFs
+// controller according to the provider's retry settings.
+type vrfsController struct {
+	controller *vrfs.Controller
+	retry      RetryConfig
+}
+
+func (c *vrfsController) ListVRFs() (out []vrfs.VRF, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListVRFs()
+		return
+	})
+	return
+}
+
+func (c *vrfsController) CreateVRF(in vrfs.VRF) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateVRF(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetVRFsByName(in.Name)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *vrfsController) GetVRFByID(id int) (out vrfs.VRF, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVRFByID(id)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) GetVRFsByName(name string) (out []vrfs.VRF, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVRFsByName(name)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) GetVRFsByRD(rd string) (out []vrfs.VRF, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVRFsByRD(rd)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) GetSubnetsInVRF(id int) (out []subnets.Subnet, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetSubnetsInVRF(id)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) GetVRFCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVRFCustomFieldsSchema()
+		return
+	})
+	return
+}
+
+func (c *vrfsController) GetVRFCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetVRFCustomFields(id)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) UpdateVRF(in vrfs.VRF) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateVRF(in)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) UpdateVRFCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateVRFCustomFields(id, in)
+		return
+	})
+	return
+}
+
+func (c *vrfsController) DeleteVRF(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteVRF(id)
+		return
+	})
+	return
+}
+
+// devicesController wraps devices.Controller, retrying requests to the devices
+// controller according to the provider's retry settings.
+type devicesController struct {
+	controller *devices.Controller
+	retry      RetryConfig
+}
+
+func (c *devicesController) ListDevices() (out []devices.Device, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListDevices()
+		return
+	})
+	return
+}
+
+func (c *devicesController) CreateDevice(in devices.Device) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateDevice(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetDevicesByHostname(in.Hostname)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *devicesController) GetDeviceByID(id int) (out devices.Device, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetDeviceByID(id)
+		return
+	})
+	return
+}
+
+func (c *devicesController) GetDevicesByHostname(hostname string) (out []devices.Device, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetDevicesByHostname(hostname)
+		return
+	})
+	return
+}
+
+func (c *devicesController) GetAddressesOnDevice(id int) (out []addresses.Address, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetAddressesOnDevice(id)
+		return
+	})
+	return
+}
+
+func (c *devicesController) GetDeviceCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetDeviceCustomFieldsSchema()
+		return
+	})
+	return
+}
+
+func (c *devicesController) GetDeviceCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetDeviceCustomFields(id)
+		return
+	})
+	return
+}
+
+func (c *devicesController) UpdateDevice(in devices.Device) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateDevice(in)
+		return
+	})
+	return
+}
+
+func (c *devicesController) UpdateDeviceCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateDeviceCustomFields(id, in)
+		return
+	})
+	return
+}
+
+func (c *devicesController) DeleteDevice(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteDevice(id)
+		return
+	})
+	return
+}
+
+// locationsController wraps locations.Controller, retrying requests to the
+// locations controller according to the provider's retry settings.
+type locationsController struct {
+	controller *locations.Controller
+	retry      RetryConfig
+}
+
+func (c *locationsController) ListLocations() (out []locations.Location, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListLocations()
+		return
+	})
+	return
+}
+
+func (c *locationsController) CreateLocation(in locations.Location) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateLocation(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetLocationsByName(in.Name)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *locationsController) GetLocationByID(id int) (out locations.Location, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetLocationByID(id)
+		return
+	})
+	return
+}
+
+func (c *locationsController) GetLocationsByName(name string) (out []locations.Location, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetLocationsByName(name)
+		return
+	})
+	return
+}
+
+func (c *locationsController) GetLocationCustomFieldsSchema() (out map[string]phpipam.CustomField, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetLocationCustomFieldsSchema()
+		return
+	})
+	return
+}
+
+func (c *locationsController) GetLocationCustomFields(id int) (out map[string]interface{}, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetLocationCustomFields(id)
+		return
+	})
+	return
+}
+
+func (c *locationsController) UpdateLocation(in locations.Location) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateLocation(in)
+		return
+	})
+	return
+}
+
+func (c *locationsController) UpdateLocationCustomFields(id int, in map[string]interface{}) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateLocationCustomFields(id, in)
+		return
+	})
+	return
+}
+
+func (c *locationsController) DeleteLocation(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteLocation(id)
+		return
+	})
+	return
+}
+
+// nameserversController wraps nameservers.Controller, retrying requests to the
+// nameservers controller according to the provider's retry settings.
+type nameserversController struct {
+	controller *nameservers.Controller
+	retry      RetryConfig
+}
+
+func (c *nameserversController) ListNameservers() (out []nameservers.Nameserver, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListNameservers()
+		return
+	})
+	return
+}
+
+func (c *nameserversController) CreateNameserver(in nameservers.Nameserver) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateNameserver(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetNameserversByName(in.Name)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *nameserversController) GetNameserverByID(id int) (out nameservers.Nameserver, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetNameserverByID(id)
+		return
+	})
+	return
+}
+
+func (c *nameserversController) GetNameserversByName(name string) (out []nameservers.Nameserver, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetNameserversByName(name)
+		return
+	})
+	return
+}
+
+func (c *nameserversController) UpdateNameserver(in nameservers.Nameserver) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateNameserver(in)
+		return
+	})
+	return
+}
+
+func (c *nameserversController) DeleteNameserver(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteNameserver(id)
+		return
+	})
+	return
+}
+
+// tagsController wraps tags.Controller, retrying requests to the IP address
+// tags controller according to the provider's retry settings.
+type tagsController struct {
+	controller *tags.Controller
+	retry      RetryConfig
+}
+
+func (c *tagsController) ListTags() (out []tags.Tag, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.ListTags()
+		return
+	})
+	return
+}
+
+func (c *tagsController) CreateTag(in tags.Tag) (message string, err error) {
+	err = c.retry.retryCreate(func() (err error) {
+		message, err = c.controller.CreateTag(in)
+		return
+	}, func() (bool, error) {
+		out, err := c.controller.GetTagsByName(in.Type)
+		return found(len(out), err)
+	})
+	return
+}
+
+func (c *tagsController) GetTagByID(id int) (out tags.Tag, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetTagByID(id)
+		return
+	})
+	return
+}
+
+func (c *tagsController) GetTagsByName(name string) (out []tags.Tag, err error) {
+	err = c.retry.retry(func() (err error) {
+		out, err = c.controller.GetTagsByName(name)
+		return
+	})
+	return
+}
+
+func (c *tagsController) UpdateTag(in tags.Tag) (message string, err error) {
+	err = c.retry.retry(func() (err error) {
+		message, err = c.controller.UpdateTag(in)
+		return
+	})
+	return
+}
+
+func (c *tagsController) DeleteTag(id int) (message string, err error) {
+	err = c.retry.retryDelete(func() (err error) {
+		message, err = c.controller.DeleteTag(id)
+		return
+	})
+	return
+}
@@ -0,0 +1,188 @@
+package phpipam
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/fakephpipam"
+)
+
+var testRetryConfig = RetryConfig{
+	MaxAttempts:          3,
+	MinBackoff:           time.Millisecond,
+	MaxBackoff:           4 * time.Millisecond,
+	RetryableStatusCodes: defaultRetryableStatusCodes,
+}
+
+func TestRetryConfigClassify(t *testing.T) {
+	cases := []struct {
+		err      string
+		expected retryClass
+	}{
+		{"Error from API (404): Not Found", retryNever},
+		{"Error from API (409): Subnet overlaps with 10.10.1.0/24", retryNever},
+		{"Error from API (503): Service unavailable", retryAmbiguous},
+		{"Non-API error (502 Bad Gateway): <html></html>", retryAmbiguous},
+		{"Non-API error (500 Internal Server Error): <html></html>", retryNever},
+		{"Error from API (500): SQLSTATE[40001]: Serialization failure: 1213 Deadlock found when trying to get lock; try restarting transaction", retryUnapplied},
+		{"Error from API (500): SQLSTATE[HY000]: General error: 1205 Lock wait timeout exceeded; try restarting transaction", retryAmbiguous},
+		{"HTTP protocol error: dial tcp 127.0.0.1:80: connect: connection refused", retryUnapplied},
+		{"HTTP protocol error: read tcp 127.0.0.1:80: connection reset by peer", retryAmbiguous},
+		{"Error logging into PHPIPAM: Non-API error (502 Bad Gateway): <html></html>", retryUnapplied},
+		{"Error logging into PHPIPAM: Error from API (500): Invalid username or password", retryNever},
+		{"Error refreshing expired PHPIPAM session token: HTTP protocol error: EOF", retryUnapplied},
+	}
+	for _, tc := range cases {
+		if actual := testRetryConfig.classify(errors.New(tc.err)); actual != tc.expected {
+			t.Errorf("%q: expected %d, got %d", tc.err, tc.expected, actual)
+		}
+	}
+}
+
+func TestRetryConfigBackoff(t *testing.T) {
+	c := RetryConfig{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	for retry, max := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second} {
+		d := c.backoff(retry + 1)
+		if d < max/2 || d > max {
+			t.Errorf("retry %d: expected a backoff between %s and %s, got %s", retry+1, max/2, max, d)
+		}
+	}
+}
+
+func TestRetryConfigRetry(t *testing.T) {
+	var attempts int
+	err := testRetryConfig.retry(func() error {
+		attempts++
+		return errors.New("Error from API (503): Service unavailable")
+	})
+	if err == nil || attempts != 3 {
+		t.Fatalf("expected error after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	attempts = 0
+	err = testRetryConfig.retry(func() error {
+		attempts++
+		return errors.New("Error from API (404): Not Found")
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected error after 1 attempt, got %v after %d", err, attempts)
+	}
+
+	attempts = 0
+	err = RetryConfig{MaxAttempts: 1}.retry(func() error {
+		attempts++
+		return errors.New("Error from API (503): Service unavailable")
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected error after 1 attempt with retries disabled, got %v after %d", err, attempts)
+	}
+}
+
+func TestRetryConfigRetryCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      string
+		exists   func() (bool, error)
+		attempts int
+		success  bool
+	}{
+		{"unapplied", "Error from API (500): Deadlock found when trying to get lock", nil, 2, true},
+		{"ambiguous, no check", "Error from API (503): Service unavailable", nil, 1, false},
+		{"ambiguous, created", "Error from API (503): Service unavailable", func() (bool, error) { return true, nil }, 1, true},
+		{"ambiguous, not created", "Error from API (503): Service unavailable", func() (bool, error) { return false, nil }, 2, true},
+		{"ambiguous, check failed", "Error from API (503): Service unavailable", func() (bool, error) { return false, errors.New("boom") }, 1, false},
+	}
+	for _, tc := range cases {
+		var attempts int
+		err := testRetryConfig.retryCreate(func() error {
+			attempts++
+			if attempts == 1 {
+				return errors.New(tc.err)
+			}
+			return nil
+		}, tc.exists)
+		if (err == nil) != tc.success || attempts != tc.attempts {
+			t.Errorf("%s: expected success %t after %d attempts, got %v after %d", tc.name, tc.success, tc.attempts, err, attempts)
+		}
+	}
+}
+
+func TestRetryConfigRetryDelete(t *testing.T) {
+	var attempts int
+	err := testRetryConfig.retryDelete(func() error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("Error from API (504): Gateway Timeout")
+		}
+		return errors.New("Error from API (404): Not Found")
+	})
+	if err != nil || attempts != 2 {
+		t.Fatalf("expected success after 2 attempts, got %v after %d", err, attempts)
+	}
+
+	err = testRetryConfig.retryDelete(func() error {
+		return errors.New("Error from API (404): Not Found")
+	})
+	if err == nil {
+		t.Fatal("expected not found error when deleting a missing object")
+	}
+}
+
+func TestProviderRetry(t *testing.T) {
+	meta, s := testFakeProviderMetaRaw(t, map[string]interface{}{
+		"retry": []interface{}{
+			map[string]interface{}{
+				"max_attempts": 3,
+				"min_backoff":  "1ms",
+				"max_backoff":  "4ms",
+			},
+		},
+	})
+	c := meta.(*ProviderPHPIPAMClient).sectionsController
+
+	// A proxy error on a read is retried.
+	s.FailNext(fakephpipam.Failure{Code: 502})
+	if _, err := c.GetSectionByName("Customers"); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+
+	// A create that timed out after being applied is not sent again.
+	s.FailNext(fakephpipam.Failure{Code: 504, Applied: true})
+	state := testFakeApply(t, meta, "phpipam_section", nil, map[string]interface{}{
+		"name": "tf-test",
+	})
+	sections, err := c.ListSections()
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if len(sections) != 3 {
+		t.Fatalf("expected 3 sections, got %d", len(sections))
+	}
+
+	// A deadlock is retried, even for a create.
+	s.FailNext(fakephpipam.Failure{Code: 500, Message: "SQLSTATE[40001]: Serialization failure: 1213 Deadlock found when trying to get lock; try restarting transaction"})
+	testFakeApply(t, meta, "phpipam_l2domain", nil, map[string]interface{}{
+		"name": "tf-test",
+	})
+
+	// A delete that timed out after being applied succeeds.
+	s.FailNext(fakephpipam.Failure{Code: 504, Applied: true})
+	testFakeDestroy(t, meta, "phpipam_section", state)
+
+	// Other errors are not retried.
+	s.FailNext(fakephpipam.Failure{Code: 500, Message: "Internal error"}, fakephpipam.Failure{Code: 502})
+	if _, err := c.ListSections(); err == nil || err.Error() != "Error from API (500): Internal error" {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+}
+
+func TestProviderRetryDisabled(t *testing.T) {
+	meta, s := testFakeProviderMeta(t)
+	c := meta.(*ProviderPHPIPAMClient).sectionsController
+
+	s.FailNext(fakephpipam.Failure{Code: 502})
+	if _, err := c.ListSections(); err == nil {
+		t.Fatal("expected error without retry block")
+	}
+}
@@ -0,0 +1,401 @@
+package phpipam
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+// searchObjectTypes are the object types that the phpipam_search data source
+// can search, in the order in which results are returned.
+var searchObjectTypes = []string{"addresses", "subnets", "vlans", "vrfs", "devices"}
+
+// searchIDFields maps the object types of the phpipam_search data source to
+// the attributes that hold the IDs of their matches.
+var searchIDFields = map[string]string{
+	"addresses": "address_ids",
+	"subnets":   "subnet_ids",
+	"vlans":     "vlan_ids",
+	"vrfs":      "vrf_ids",
+	"devices":   "device_ids",
+}
+
+// dataSourceSearchSchema returns the schema for the phpipam_search data
+// source.
+func dataSourceSearchSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"query": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"regex": &schema.Schema{
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"object_types": &schema.Schema{
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(searchObjectTypes, false),
+			},
+		},
+		"section_id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"subnet_id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+		},
+		"results": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"object_type":   &schema.Schema{Type: schema.TypeString, Computed: true},
+					"id":            &schema.Schema{Type: schema.TypeInt, Computed: true},
+					"label":         &schema.Schema{Type: schema.TypeString, Computed: true},
+					"matched_field": &schema.Schema{Type: schema.TypeString, Computed: true},
+					"matched_value": &schema.Schema{Type: schema.TypeString, Computed: true},
+					"subnet_id":     &schema.Schema{Type: schema.TypeInt, Computed: true},
+					"section_id":    &schema.Schema{Type: schema.TypeInt, Computed: true},
+				},
+			},
+		},
+	}
+	for _, v := range searchIDFields {
+		s[v] = &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeInt},
+		}
+	}
+	return s
+}
+
+// searchResult is a match of the phpipam_search data source.
+type searchResult struct {
+	ObjectType   string
+	ID           int
+	Label        string
+	MatchedField string
+	MatchedValue string
+	SubnetID     int
+	SectionID    int
+}
+
+// searchField is a field of an object that is searched, by its attribute
+// name.
+type searchField struct {
+	Name  string
+	Value string
+}
+
+// searchMatcher matches the fields of objects against the query of the
+// phpipam_search data source. Without regex, the query matches fields that
+// contain it, ignoring case, like the search box of the PHPIPAM UI.
+type searchMatcher struct {
+	raw   string
+	query string
+	re    *regexp.Regexp
+	ip    netip.Addr
+}
+
+// newSearchMatcher returns a searchMatcher for query.
+func newSearchMatcher(query string, regex bool) (*searchMatcher, error) {
+	m := &searchMatcher{raw: query, query: strings.ToLower(query)}
+	if regex {
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid search query %q: %s", query, err)
+		}
+		m.re = re
+	} else if ip, err := netip.ParseAddr(query); err == nil {
+		m.ip = ip
+	}
+	return m, nil
+}
+
+// match returns the first of fields that matches the query.
+func (m *searchMatcher) match(fields ...searchField) (searchField, bool) {
+	for _, f := range fields {
+		switch {
+		case f.Value == "":
+		case m.re != nil && m.re.MatchString(f.Value):
+			return f, true
+		case m.re == nil && strings.Contains(strings.ToLower(f.Value), m.query):
+			return f, true
+		}
+	}
+	return searchField{}, false
+}
+
+// containedIn returns true if the query is an IP address within the subnet s.
+// This allows a search for an IP address to find the subnets that it belongs
+// to.
+func (m *searchMatcher) containedIn(s subnets.Subnet) bool {
+	if !m.ip.IsValid() || bool(s.IsFolder) {
+		return false
+	}
+	p, err := netip.ParsePrefix(fmt.Sprintf("%s/%d", s.SubnetAddress, s.Mask))
+	return err == nil && p.Contains(m.ip)
+}
+
+// searchSubnetsInScope returns the subnets that the phpipam_search data source
+// searches: the subnet subnetID and the subnets nested within it if subnetID
+// is not 0, otherwise the subnets in the section sectionID, or the subnets in
+// all sections if sectionID is 0. This reads the subnets of each section.
+func searchSubnetsInScope(meta interface{}, sectionID, subnetID int) ([]subnets.Subnet, error) {
+	if subnetID != 0 {
+		c := meta.(*ProviderPHPIPAMClient).subnetsController
+		s, err := c.GetSubnetByID(subnetID)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read subnet ID %d: %s", subnetID, err)
+		}
+		if sectionID != 0 && s.SectionID != sectionID {
+			return nil, nil
+		}
+		children, err := c.GetSubnetsInSubnetRecursive(subnetID)
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, fmt.Errorf("Could not read the subnets nested in subnet ID %d: %s", subnetID, err)
+		}
+		return append([]subnets.Subnet{s}, children...), nil
+	}
+	c := meta.(*ProviderPHPIPAMClient).sectionsController
+	ids := []int{sectionID}
+	if sectionID == 0 {
+		list, err := c.ListSections()
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, err
+		}
+		ids = nil
+		for _, v := range list {
+			ids = append(ids, v.ID)
+		}
+	}
+	var out []subnets.Subnet
+	for _, id := range ids {
+		list, err := c.GetSubnetsInSection(id)
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, fmt.Errorf("Could not read subnets of section ID %d: %s", id, err)
+		}
+		out = append(out, list...)
+	}
+	return out, nil
+}
+
+// searchAddresses returns the addresses that the phpipam_search data source
+// matches against the query.
+//
+// Within a subnet or a section, all addresses are read and matched, which
+// reads the addresses of every subnet of the section. Otherwise, the
+// addresses are looked up by the API: a query that is an IP address, a MAC
+// address or a host name matches the addresses with that exact value, and
+// regular expressions are not supported.
+func searchAddresses(meta interface{}, m *searchMatcher, sectionID, subnetID int) ([]addresses.Address, error) {
+	c := meta.(*ProviderPHPIPAMClient)
+	var list []addresses.Address
+	var err error
+	switch {
+	case subnetID != 0:
+		list, err = c.subnetsController.GetAddressesInSubnet(subnetID)
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, fmt.Errorf("Could not read addresses of subnet ID %d: %s", subnetID, err)
+		}
+		return list, nil
+	case sectionID != 0:
+		scope, err := searchSubnetsInScope(meta, sectionID, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range scope {
+			if s.IsFolder {
+				continue
+			}
+			out, err := c.subnetsController.GetAddressesInSubnet(s.ID)
+			if err != nil && apiErrorCode(err) != 404 {
+				return nil, fmt.Errorf("Could not read addresses of subnet ID %d: %s", s.ID, err)
+			}
+			list = append(list, out...)
+		}
+		return list, nil
+	case m.re != nil:
+		return nil, errors.New("Searching addresses with a regular expression requires section_id or subnet_id")
+	case m.ip.IsValid():
+		list, err = c.addressesController.GetAddressesByIP(m.ip.String())
+	default:
+		if _, merr := net.ParseMAC(m.raw); merr == nil {
+			list, err = c.addressesController.SearchAddressesByMAC(m.raw)
+		} else {
+			list, err = c.addressesController.SearchAddressesByHostname(m.raw)
+		}
+	}
+	if err != nil && apiErrorCode(err) != 404 {
+		return nil, err
+	}
+	return list, nil
+}
+
+// search runs the search of the phpipam_search data source for the object
+// types in types, and returns the matches in the order of searchObjectTypes.
+func search(meta interface{}, m *searchMatcher, types map[string]bool, sectionID, subnetID int) ([]searchResult, error) {
+	client := meta.(*ProviderPHPIPAMClient)
+	var results []searchResult
+
+	var scope []subnets.Subnet
+	if types["subnets"] {
+		var err error
+		if scope, err = searchSubnetsInScope(meta, sectionID, subnetID); err != nil {
+			return nil, err
+		}
+	}
+
+	if types["addresses"] {
+		list, err := searchAddresses(meta, m, sectionID, subnetID)
+		if err != nil {
+			return nil, err
+		}
+		// The section of an address is the section of its subnet, which is
+		// read once per subnet.
+		sections := make(map[int]int)
+		for _, v := range list {
+			f, ok := m.match(
+				searchField{"ip_address", v.IPAddress},
+				searchField{"hostname", v.Hostname},
+				searchField{"description", v.Description},
+				searchField{"mac_address", v.MACAddress},
+				searchField{"owner", v.Owner},
+				searchField{"note", v.Note},
+			)
+			if !ok {
+				continue
+			}
+			if _, ok := sections[v.SubnetID]; !ok {
+				s, err := client.subnetsController.GetSubnetByID(v.SubnetID)
+				if err != nil {
+					return nil, fmt.Errorf("Could not read subnet ID %d of address ID %d: %s", v.SubnetID, v.ID, err)
+				}
+				sections[v.SubnetID] = s.SectionID
+			}
+			if sectionID != 0 && sections[v.SubnetID] != sectionID {
+				continue
+			}
+			results = append(results, searchResult{"addresses", v.ID, v.IPAddress, f.Name, f.Value, v.SubnetID, sections[v.SubnetID]})
+		}
+	}
+
+	if types["subnets"] {
+		for _, v := range scope {
+			label := subnetCIDR(v)
+			if label == "" {
+				label = v.Description
+			}
+			f, ok := m.match(
+				searchField{"cidr", subnetCIDR(v)},
+				searchField{"description", v.Description},
+			)
+			if !ok && m.containedIn(v) {
+				f, ok = searchField{"cidr", subnetCIDR(v)}, true
+			}
+			if ok {
+				results = append(results, searchResult{"subnets", v.ID, label, f.Name, f.Value, 0, v.SectionID})
+			}
+		}
+	}
+
+	if types["vlans"] {
+		list, err := client.vlansController.ListVLANs()
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, err
+		}
+		for _, v := range list {
+			f, ok := m.match(
+				searchField{"name", v.Name},
+				searchField{"number", strconv.Itoa(v.Number)},
+				searchField{"description", v.Description},
+			)
+			if ok {
+				results = append(results, searchResult{"vlans", v.ID, v.Name, f.Name, f.Value, 0, 0})
+			}
+		}
+	}
+
+	if types["vrfs"] {
+		list, err := client.vrfsController.ListVRFs()
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, err
+		}
+		for _, v := range list {
+			f, ok := m.match(
+				searchField{"name", v.Name},
+				searchField{"rd", v.RD},
+				searchField{"description", v.Description},
+			)
+			if ok {
+				results = append(results, searchResult{"vrfs", v.ID, v.Name, f.Name, f.Value, 0, 0})
+			}
+		}
+	}
+
+	if types["devices"] {
+		list, err := client.devicesController.ListDevices()
+		if err != nil && apiErrorCode(err) != 404 {
+			return nil, err
+		}
+		for _, v := range list {
+			f, ok := m.match(
+				searchField{"hostname", v.Hostname},
+				searchField{"ip_address", v.IPAddress},
+				searchField{"description", v.Description},
+			)
+			if ok {
+				results = append(results, searchResult{"devices", v.ID, v.Hostname, f.Name, f.Value, 0, 0})
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// flattenSearchResults sets the results and ID list fields of the
+// phpipam_search data source.
+func flattenSearchResults(results []searchResult, d *schema.ResourceData) error {
+	list := make([]interface{}, 0)
+	ids := make(map[string][]int)
+	for _, t := range searchObjectTypes {
+		ids[t] = make([]int, 0)
+	}
+	for _, r := range results {
+		list = append(list, map[string]interface{}{
+			"object_type":   r.ObjectType,
+			"id":            r.ID,
+			"label":         r.Label,
+			"matched_field": r.MatchedField,
+			"matched_value": r.MatchedValue,
+			"subnet_id":     r.SubnetID,
+			"section_id":    r.SectionID,
+		})
+		ids[r.ObjectType] = append(ids[r.ObjectType], r.ID)
+	}
+	if err := d.Set("results", list); err != nil {
+		return err
+	}
+	for t, field := range searchIDFields {
+		if err := d.Set(field, ids[t]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
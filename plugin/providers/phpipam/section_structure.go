@@ -1,6 +1,7 @@
 package phpipam
 
 import (
+	"regexp"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -91,6 +92,7 @@ func resourceSectionSchema() map[string]*schema.Schema {
 			v.Computed = true
 		}
 	}
+	schema["adopt_existing"] = adoptExistingSchema()
 	return schema
 }
 
@@ -120,6 +122,17 @@ func dataSourceSectionSchema() map[string]*schema.Schema {
 // expandSection returns the sections.Section structure for a
 // phpiapm_section resource or data source. Depending on if we are dealing with
 // the resource or data source, extra considerations may need to be taken.
+// dataSourceSectionsSchema returns the sub-schema for the sections list of the
+// phpipam_sections data source. All this function does is set all fields as
+// computed.
+func dataSourceSectionsSchema() map[string]*schema.Schema {
+	schema := bareSectionSchema()
+	for _, v := range schema {
+		v.Computed = true
+	}
+	return schema
+}
+
 func expandSection(d *schema.ResourceData) sections.Section {
 	s := sections.Section{
 		ID:               d.Get("section_id").(int),
@@ -158,3 +171,66 @@ func flattenSection(s sections.Section, d *schema.ResourceData) {
 	d.Set("show_supernet_only", s.ShowSupernetOnly)
 	d.Set("dns_resolver_id", s.DNS)
 }
+
+// flattenSectionMap returns the fields supplied by the input sections.Section
+// as a map, in the form used by the sections list of the phpipam_sections data
+// source.
+func flattenSectionMap(s sections.Section) map[string]interface{} {
+	return map[string]interface{}{
+		"section_id":                  s.ID,
+		"name":                        s.Name,
+		"description":                 s.Description,
+		"master_section_id":           s.MasterSection,
+		"permissions":                 s.Permissions,
+		"strict_mode":                 bool(s.StrictMode),
+		"subnet_ordering":             s.SubnetOrdering,
+		"display_order":               s.Order,
+		"edit_date":                   s.EditDate,
+		"show_vlan_in_subnet_listing": bool(s.ShowVLAN),
+		"show_vrf_in_subnet_listing":  bool(s.ShowVRF),
+		"show_supernet_only":          bool(s.ShowSupernetOnly),
+		"dns_resolver_id":             s.DNS,
+	}
+}
+
+// sectionSearch provides the section search functionality for the
+// phpipam_sections data source, returning the sections that match the name,
+// description and custom field filters.
+func sectionSearch(d *schema.ResourceData, meta interface{}) ([]sections.Section, error) {
+	c := meta.(*ProviderPHPIPAMClient).sectionsController
+	result := make([]sections.Section, 0)
+
+	v, err := c.ListSections()
+	if err != nil && apiErrorCode(err) != 404 {
+		return result, err
+	}
+	for _, r := range v {
+		// Don't trap regex errors here because we should have already validated
+		// the expressions via the ValidateFunc.
+		if expr := d.Get("name_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Name); !matched {
+				continue
+			}
+		}
+		if expr := d.Get("description_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Description); !matched {
+				continue
+			}
+		}
+		if search := d.Get("custom_field_filter").(map[string]interface{}); len(search) > 0 {
+			fields, err := c.GetSectionCustomFields(r.ID)
+			if err != nil {
+				return result, err
+			}
+			matched, err := customFieldFilter(fields, search)
+			if err != nil {
+				return result, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
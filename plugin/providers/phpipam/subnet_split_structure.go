@@ -0,0 +1,105 @@
+package phpipam
+
+import (
+	"fmt"
+	"net/netip"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+// resourceSubnetSplitSchema returns the schema for the phpipam_subnet_split
+// resource. The subnet to split is found by its section and CIDR, as its ID
+// does not survive the split. These and the number of subnets are required,
+// and force a new split. Everything else is computed.
+func resourceSubnetSplitSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"section_id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Required: true,
+			ForceNew: true,
+		},
+		"subnet_address": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"subnet_mask": &schema.Schema{
+			Type:     schema.TypeInt,
+			Required: true,
+			ForceNew: true,
+		},
+		"number": &schema.Schema{
+			Type:         schema.TypeInt,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validateSplitNumber,
+		},
+		"subnet_id": &schema.Schema{
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"subnet_ids": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeInt},
+		},
+		"subnet_cidrs": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+// validateSplitNumber validates that a subnet is split into a power of two
+// subnets, which is what PHPIPAM supports.
+func validateSplitNumber(v interface{}, k string) (ws []string, errors []error) {
+	n := v.(int)
+	if n < 2 || n&(n-1) != 0 {
+		errors = append(errors, fmt.Errorf("%q must be a power of two of at least 2, got %d", k, n))
+	}
+	return
+}
+
+// splitSubnetCIDRs returns the CIDRs of the subnets that the subnet
+// address/mask is split into, in order.
+func splitSubnetCIDRs(address string, mask, number int) ([]string, error) {
+	prefix, err := netip.ParsePrefix(fmt.Sprintf("%s/%d", address, mask))
+	if err != nil {
+		return nil, err
+	}
+	bits := mask
+	for i := number; i > 1; i >>= 1 {
+		bits++
+	}
+	if bits > prefix.Addr().BitLen() {
+		return nil, fmt.Errorf("Subnet %s is too small to be split into %d subnets", prefix, number)
+	}
+
+	var out []string
+	ip := prefix.Masked().Addr()
+	for i := 0; i < number; i++ {
+		child := netip.PrefixFrom(ip, bits)
+		out = append(out, child.String())
+		ip = child.Addr()
+		for j := bits; j < ip.BitLen(); j++ {
+			ip = setBit(ip, j)
+		}
+		ip = ip.Next()
+	}
+	return out, nil
+}
+
+// flattenSubnetSplit sets the computed fields of a phpipam_subnet_split
+// resource from the subnets that the split created.
+func flattenSubnetSplit(children []subnets.Subnet, d *schema.ResourceData) {
+	var ids []int
+	var cidrs []string
+	for _, v := range children {
+		ids = append(ids, v.ID)
+		cidrs = append(cidrs, fmt.Sprintf("%s/%d", v.SubnetAddress, v.Mask))
+	}
+	d.Set("subnet_ids", ids)
+	d.Set("subnet_cidrs", cidrs)
+}
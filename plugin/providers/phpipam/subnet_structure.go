@@ -1,6 +1,7 @@
 package phpipam
 
 import (
+	"encoding/json"
 	"errors"
 	"regexp"
 	"strconv"
@@ -37,6 +38,27 @@ var resourceSubnetOptionalFields = linearSearchSlice{
 	"resolve_dns",
 }
 
+// resourceSubnetClearableFields are the optional fields of the phpipam_subnet
+// resource that are cleared when removed from the configuration, by their
+// names in the API. See clearableFields.
+var resourceSubnetClearableFields = clearableFields{
+	"description":            "description",
+	"linked_subnet_id":       "linked_subnet",
+	"vlan_id":                "vlanId",
+	"vrf_id":                 "vrfId",
+	"nameserver_id":          "nameserverId",
+	"show_name":              "showName",
+	"create_ptr_records":     "DNSrecursive",
+	"display_hostnames":      "DNSrecords",
+	"allow_ip_requests":      "allowRequests",
+	"include_in_ping":        "pingSubnet",
+	"host_discovery_enabled": "discoverSubnet",
+	"is_full":                "isFull",
+	"utilization_threshold":  "threshold",
+	"location_id":            "location",
+	"resolve_dns":            "resolveDNS",
+}
+
 // bareSubnetSchema returns a map[string]*schema.Schema with the schema used
 // to represent a PHPIPAM subnet resource. This output should then be modified
 // so that required and computed fields are set properly for both the data
@@ -169,10 +191,13 @@ func resourceSubnetSchema() map[string]*schema.Schema {
 	schema := bareSubnetSchema()
 	for k, v := range schema {
 		switch {
-		// Subnet Address and Mask are currently ForceNew
-		case k == "subnet_address" || k == "subnet_mask":
+		// Subnet Address is ForceNew. Mask changes force a new subnet unless
+		// the subnet can be resized, see resourcePHPIPAMSubnetCustomizeDiff.
+		case k == "subnet_address":
 			v.Required = true
 			v.ForceNew = true
+		case k == "subnet_mask":
+			v.Required = true
 		case k == "section_id":
 			v.Required = true
 		case k == "custom_fields":
@@ -184,6 +209,8 @@ func resourceSubnetSchema() map[string]*schema.Schema {
 			v.Computed = true
 		}
 	}
+	schema["adopt_existing"] = adoptExistingSchema()
+	schema["configured_fields"] = configuredFieldsSchema()
 	return schema
 }
 
@@ -322,6 +349,46 @@ func flattenSubnet(s subnets.Subnet, d *schema.ResourceData) {
 	}
 }
 
+// flattenSubnetMap returns the fields supplied by the input subnets.Subnet as
+// a map, in the form used by the subnets list of the phpipam_subnets data
+// source.
+func flattenSubnetMap(s subnets.Subnet) map[string]interface{} {
+	m := map[string]interface{}{
+		"subnet_id":              s.ID,
+		"subnet_address":         s.SubnetAddress,
+		"subnet_mask":            int(s.Mask),
+		"description":            s.Description,
+		"section_id":             s.SectionID,
+		"linked_subnet_id":       s.LinkedSubnet,
+		"vlan_id":                s.VLANID,
+		"vrf_id":                 s.VRFID,
+		"master_subnet_id":       s.MasterSubnetID,
+		"parent_subnet_id":       s.MasterSubnetID,
+		"nameserver_id":          s.NameserverID,
+		"nameservers":            s.Nameservers,
+		"show_name":              bool(s.ShowName),
+		"permissions":            s.Permissions,
+		"create_ptr_records":     bool(s.DNSRecursive),
+		"display_hostnames":      bool(s.DNSRecords),
+		"resolve_dns":            bool(s.ResolveDNS),
+		"allow_ip_requests":      bool(s.AllowRequests),
+		"scan_agent_id":          s.ScanAgent,
+		"include_in_ping":        bool(s.PingSubnet),
+		"host_discovery_enabled": bool(s.DiscoverSubnet),
+		"is_folder":              bool(s.IsFolder),
+		"is_full":                bool(s.IsFull),
+		"utilization_threshold":  s.Threshold,
+		"location_id":            s.Location,
+		"edit_date":              s.EditDate,
+		"gateway":                s.Gateway,
+		"gateway_id":             s.GatewayID,
+	}
+	if s.CustomFields != nil {
+		m["custom_fields"] = s.CustomFields
+	}
+	return m
+}
+
 // subnetDescriptionMatchSchema returns a *schema.Schema for description
 // matching for subnet-related resources. The conflicting keys are populated by
 // the passed in string slice.
@@ -393,3 +460,56 @@ func subnetSearchInSection(d *schema.ResourceData, meta interface{}) ([]subnets.
 	}
 	return result, nil
 }
+
+// SubnetUsage represents the usage of a subnet, as returned by the
+// /subnets/{id}/usage/ API method, which the SDK does not support. The
+// percentages per address tag in the response are not decoded, as the data
+// source counts the addresses with each tag itself, see subnetTagCounts.
+type SubnetUsage struct {
+	// The number of addresses in the subnet.
+	Used jsonNumber `json:"used"`
+
+	// The number of usable host addresses in the subnet.
+	MaxHosts jsonNumber `json:"maxhosts"`
+
+	// The number of free host addresses in the subnet.
+	FreeHosts jsonNumber `json:"freehosts"`
+
+	// The percentage of host addresses that are free.
+	FreeHostsPercent jsonNumber `json:"freehosts_percent"`
+}
+
+// jsonNumber is a number that PHPIPAM returns either as a JSON number or as a
+// string. The decimal representation is kept as is, as host counts of IPv6
+// subnets exceed the range of an int.
+type jsonNumber string
+
+// UnmarshalJSON implements json.Unmarshaler for the jsonNumber type.
+func (n *jsonNumber) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*n = jsonNumber(s)
+		return nil
+	}
+	var v json.Number
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*n = jsonNumber(v)
+	return nil
+}
+
+// String returns the decimal representation of the number, or "0" if the
+// number was not set.
+func (n jsonNumber) String() string {
+	if n == "" {
+		return "0"
+	}
+	return string(n)
+}
+
+// Float64 returns the number as a float64, or 0 if it is not a valid number.
+func (n jsonNumber) Float64() float64 {
+	f, _ := strconv.ParseFloat(string(n), 64)
+	return f
+}
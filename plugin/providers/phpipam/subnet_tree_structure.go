@@ -0,0 +1,208 @@
+package phpipam
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+// dataSourceSubnetTreeSchema returns the schema for the phpipam_subnet_tree
+// data source. The tree is rooted at either a subnet or a section. As
+// Terraform schemas cannot be recursive, the tree is returned both as a flat
+// list of subnets in tree order, and as a nested JSON document.
+func dataSourceSubnetTreeSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"subnet_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ExactlyOneOf: []string{"subnet_id", "section_id"},
+		},
+		"section_id": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ExactlyOneOf: []string{"subnet_id", "section_id"},
+		},
+		"subnets": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: subnetTreeNodeSchema(),
+			},
+		},
+		"tree_json": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+// subnetTreeNodeSchema returns the schema of a subnet in the subnets list of
+// the phpipam_subnet_tree data source.
+func subnetTreeNodeSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"subnet_id":           &schema.Schema{Type: schema.TypeInt},
+		"master_subnet_id":    &schema.Schema{Type: schema.TypeInt},
+		"section_id":          &schema.Schema{Type: schema.TypeInt},
+		"subnet_address":      &schema.Schema{Type: schema.TypeString},
+		"subnet_mask":         &schema.Schema{Type: schema.TypeInt},
+		"cidr":                &schema.Schema{Type: schema.TypeString},
+		"description":         &schema.Schema{Type: schema.TypeString},
+		"vlan_id":             &schema.Schema{Type: schema.TypeInt},
+		"vrf_id":              &schema.Schema{Type: schema.TypeInt},
+		"is_folder":           &schema.Schema{Type: schema.TypeBool},
+		"depth":               &schema.Schema{Type: schema.TypeInt},
+		"used_hosts":          &schema.Schema{Type: schema.TypeInt},
+		"max_hosts":           &schema.Schema{Type: schema.TypeString},
+		"free_hosts":          &schema.Schema{Type: schema.TypeString},
+		"utilization_percent": &schema.Schema{Type: schema.TypeFloat},
+		"child_subnet_ids": &schema.Schema{
+			Type: schema.TypeList,
+			Elem: &schema.Schema{Type: schema.TypeInt},
+		},
+	}
+	for _, v := range s {
+		v.Computed = true
+	}
+	return s
+}
+
+// subnetTreeNode is a subnet in a subnet tree.
+type subnetTreeNode struct {
+	Subnet   subnets.Subnet
+	Depth    int
+	Usage    *SubnetUsage
+	Children []*subnetTreeNode
+}
+
+// subnetCIDR returns the CIDR of a subnet, or an empty string for folders,
+// which have no address.
+func subnetCIDR(s subnets.Subnet) string {
+	if s.SubnetAddress == "" || bool(s.IsFolder) {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", s.SubnetAddress, s.Mask)
+}
+
+// buildSubnetTree arranges list into trees, using the master subnet of each
+// subnet. Subnets whose master subnet is not in list are the roots of the
+// trees. Siblings are sorted by address, with folders first.
+func buildSubnetTree(list []subnets.Subnet) []*subnetTreeNode {
+	nodes := make(map[int]*subnetTreeNode)
+	for _, v := range list {
+		nodes[v.ID] = &subnetTreeNode{Subnet: v}
+	}
+	var roots []*subnetTreeNode
+	for _, v := range list {
+		if parent, ok := nodes[v.MasterSubnetID]; ok && v.MasterSubnetID != v.ID {
+			parent.Children = append(parent.Children, nodes[v.ID])
+		} else {
+			roots = append(roots, nodes[v.ID])
+		}
+	}
+	sortSubnetTreeNodes(roots)
+	walkSubnetTree(roots, func(n *subnetTreeNode) {
+		for _, c := range n.Children {
+			c.Depth = n.Depth + 1
+		}
+		sortSubnetTreeNodes(n.Children)
+	})
+	return roots
+}
+
+// sortSubnetTreeNodes sorts nodes by address, with folders and other subnets
+// without an address first, by ID.
+func sortSubnetTreeNodes(nodes []*subnetTreeNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, aerr := netip.ParsePrefix(subnetCIDR(nodes[i].Subnet))
+		b, berr := netip.ParsePrefix(subnetCIDR(nodes[j].Subnet))
+		switch {
+		case aerr != nil && berr != nil:
+			return nodes[i].Subnet.ID < nodes[j].Subnet.ID
+		case aerr != nil || berr != nil:
+			return aerr != nil
+		case a.Addr() != b.Addr():
+			return a.Addr().Less(b.Addr())
+		}
+		return a.Bits() < b.Bits()
+	})
+}
+
+// walkSubnetTree calls f for every node in the trees rooted at roots, in
+// depth-first order, parents before their children.
+func walkSubnetTree(roots []*subnetTreeNode, f func(*subnetTreeNode)) {
+	for _, n := range roots {
+		f(n)
+		walkSubnetTree(n.Children, f)
+	}
+}
+
+// subnetTreeNodeFields returns the fields of a subnet tree node that are
+// common to the subnets list and the JSON document of the
+// phpipam_subnet_tree data source.
+func subnetTreeNodeFields(n *subnetTreeNode) map[string]interface{} {
+	s := n.Subnet
+	m := map[string]interface{}{
+		"subnet_id":           s.ID,
+		"master_subnet_id":    s.MasterSubnetID,
+		"section_id":          s.SectionID,
+		"subnet_address":      s.SubnetAddress,
+		"subnet_mask":         int(s.Mask),
+		"cidr":                subnetCIDR(s),
+		"description":         s.Description,
+		"vlan_id":             s.VLANID,
+		"vrf_id":              s.VRFID,
+		"is_folder":           bool(s.IsFolder),
+		"depth":               n.Depth,
+		"used_hosts":          0,
+		"max_hosts":           "0",
+		"free_hosts":          "0",
+		"utilization_percent": 0.0,
+	}
+	if u := n.Usage; u != nil {
+		m["used_hosts"] = int(u.Used.Float64())
+		m["max_hosts"] = u.MaxHosts.String()
+		m["free_hosts"] = u.FreeHosts.String()
+		m["utilization_percent"] = subnetUtilization(*u)
+	}
+	return m
+}
+
+// flattenSubnetTree sets the subnets and tree_json fields of the
+// phpipam_subnet_tree data source from the trees rooted at roots.
+func flattenSubnetTree(roots []*subnetTreeNode, d *schema.ResourceData) error {
+	list := make([]interface{}, 0)
+	walkSubnetTree(roots, func(n *subnetTreeNode) {
+		m := subnetTreeNodeFields(n)
+		ids := make([]int, 0)
+		for _, c := range n.Children {
+			ids = append(ids, c.Subnet.ID)
+		}
+		m["child_subnet_ids"] = ids
+		list = append(list, m)
+	})
+	if err := d.Set("subnets", list); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(subnetTreeJSON(roots))
+	if err != nil {
+		return err
+	}
+	return d.Set("tree_json", string(b))
+}
+
+// subnetTreeJSON returns the trees rooted at roots as a nested structure, with
+// the child subnets of each subnet in its children field.
+func subnetTreeJSON(roots []*subnetTreeNode) []interface{} {
+	out := make([]interface{}, 0)
+	for _, n := range roots {
+		m := subnetTreeNodeFields(n)
+		m["children"] = subnetTreeJSON(n.Children)
+		out = append(out, m)
+	}
+	return out
+}
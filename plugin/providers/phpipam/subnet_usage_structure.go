@@ -0,0 +1,73 @@
+package phpipam
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/tags"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/addresses"
+	"github.com/pavel-z1/phpipam-sdk-go/controllers/subnets"
+)
+
+// dataSourceSubnetUsageSchema returns the schema for the phpipam_subnet_usage
+// data source. Only the subnet ID is supplied, everything else is computed.
+func dataSourceSubnetUsageSchema() map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"cidr":                  &schema.Schema{Type: schema.TypeString},
+		"used_hosts":            &schema.Schema{Type: schema.TypeInt},
+		"max_hosts":             &schema.Schema{Type: schema.TypeString},
+		"free_hosts":            &schema.Schema{Type: schema.TypeString},
+		"free_hosts_percent":    &schema.Schema{Type: schema.TypeFloat},
+		"utilization_percent":   &schema.Schema{Type: schema.TypeFloat},
+		"utilization_threshold": &schema.Schema{Type: schema.TypeInt},
+		"above_threshold":       &schema.Schema{Type: schema.TypeBool},
+		"tag_counts": &schema.Schema{
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{Type: schema.TypeInt},
+		},
+	}
+	for _, v := range s {
+		v.Computed = true
+	}
+	s["subnet_id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Required: true,
+	}
+	return s
+}
+
+// subnetUtilization returns the percentage of host addresses in use in a
+// subnet.
+func subnetUtilization(u SubnetUsage) float64 {
+	return 100 - u.FreeHostsPercent.Float64()
+}
+
+// subnetTagCounts returns the number of addresses with each address tag,
+// keyed by tag name. Tags without addresses are included with a count of 0.
+func subnetTagCounts(list []addresses.Address, tagList []tags.Tag) map[string]int {
+	names := make(map[int]string)
+	out := make(map[string]int)
+	for _, v := range tagList {
+		names[v.ID] = v.Type
+		out[v.Type] = 0
+	}
+	for _, v := range list {
+		if name, ok := names[v.Tag]; ok {
+			out[name]++
+		}
+	}
+	return out
+}
+
+// flattenSubnetUsage sets fields in a *schema.ResourceData with the usage of
+// the subnet s. This is used in read operations.
+func flattenSubnetUsage(s subnets.Subnet, u SubnetUsage, tagCounts map[string]int, d *schema.ResourceData) {
+	utilization := subnetUtilization(u)
+	d.Set("cidr", subnetCIDR(s))
+	d.Set("used_hosts", int(u.Used.Float64()))
+	d.Set("max_hosts", u.MaxHosts.String())
+	d.Set("free_hosts", u.FreeHosts.String())
+	d.Set("free_hosts_percent", u.FreeHostsPercent.Float64())
+	d.Set("utilization_percent", utilization)
+	d.Set("utilization_threshold", s.Threshold)
+	d.Set("above_threshold", s.Threshold > 0 && utilization > float64(s.Threshold))
+	d.Set("tag_counts", tagCounts)
+}
@@ -1,12 +1,17 @@
 package phpipam
 
 import (
+	"regexp"
 	"strconv"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/pavel-z1/phpipam-sdk-go/controllers/vlans"
 )
 
+// defaultL2DomainID is the ID of the default L2 domain, which is built into
+// PHPIPAM, and holds the VLANs that are created without an L2 domain.
+const defaultL2DomainID = 1
+
 // resourceVLANOptionalFields represents all the fields that are optional in
 // the phpipam_vlan resource. These fields get flagged as Optional, with zero
 // value defaults (the field is not set), in addition to being marked as
@@ -67,6 +72,7 @@ func resourceVLANSchema() map[string]*schema.Schema {
 			v.Computed = true
 		}
 	}
+	schema["adopt_existing"] = adoptExistingSchema()
 	return schema
 }
 
@@ -100,6 +106,17 @@ func dataSourceVLANSchema() map[string]*schema.Schema {
 // expandVLAN returns the vlans.VLAN structure for a
 // phpiapm_vlan resource or data source. Depending on if we are dealing with
 // the resource or data source, extra considerations may need to be taken.
+// dataSourceVLANsSchema returns the sub-schema for the vlans list of the
+// phpipam_vlans data source. All this function does is set all fields as
+// computed.
+func dataSourceVLANsSchema() map[string]*schema.Schema {
+	schema := bareVLANSchema()
+	for _, v := range schema {
+		v.Computed = true
+	}
+	return schema
+}
+
 func expandVLAN(d *schema.ResourceData) vlans.VLAN {
 	v := vlans.VLAN{
 		ID:          d.Get("vlan_id").(int),
@@ -123,3 +140,65 @@ func flattenVLAN(v vlans.VLAN, d *schema.ResourceData) {
 	d.Set("description", v.Description)
 	d.Set("edit_date", v.EditDate)
 }
+
+// flattenVLANMap returns the fields supplied by the input vlans.VLAN as a map,
+// in the form used by the vlans list of the phpipam_vlans data source. Custom
+// fields are not part of vlans.VLAN, and are left to the caller.
+func flattenVLANMap(v vlans.VLAN) map[string]interface{} {
+	return map[string]interface{}{
+		"vlan_id":      v.ID,
+		"l2_domain_id": v.DomainID,
+		"name":         v.Name,
+		"number":       v.Number,
+		"description":  v.Description,
+		"edit_date":    v.EditDate,
+	}
+}
+
+// vlanSearch provides the VLAN search functionality for the phpipam_vlans data
+// source, returning the VLANs, optionally in a single L2 domain, that match
+// the name, description and custom field filters.
+func vlanSearch(d *schema.ResourceData, meta interface{}) ([]vlans.VLAN, error) {
+	c := meta.(*ProviderPHPIPAMClient).vlansController
+	result := make([]vlans.VLAN, 0)
+
+	var v []vlans.VLAN
+	var err error
+	if id := d.Get("l2_domain_id").(int); id != 0 {
+		v, err = meta.(*ProviderPHPIPAMClient).l2domainsController.GetVlansInl2Domain(id)
+	} else {
+		v, err = c.ListVLANs()
+	}
+	if err != nil && apiErrorCode(err) != 404 {
+		return result, err
+	}
+	for _, r := range v {
+		// Don't trap regex errors here because we should have already validated
+		// the expressions via the ValidateFunc.
+		if expr := d.Get("name_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Name); !matched {
+				continue
+			}
+		}
+		if expr := d.Get("description_match").(string); expr != "" {
+			if matched, _ := regexp.MatchString(expr, r.Description); !matched {
+				continue
+			}
+		}
+		if search := d.Get("custom_field_filter").(map[string]interface{}); len(search) > 0 {
+			fields, err := c.GetVLANCustomFields(r.ID)
+			if err != nil {
+				return result, err
+			}
+			matched, err := customFieldFilter(fields, search)
+			if err != nil {
+				return result, err
+			}
+			if !matched {
+				continue
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package phpipam
+
+import (
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/lord-kyron/terraform-provider-phpipam/plugin/providers/phpipam/controllers/vrfs"
+)
+
+// bareVRFSchema returns a map[string]*schema.Schema with the schema used
+// to represent a PHPIPAM VRF. This output should then be modified so that
+// required and computed fields are set properly for the data source. The
+// phpipam_vrf resource is implemented with terraform-plugin-framework, see
+// vrfResource.
+func bareVRFSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"vrf_id": &schema.Schema{
+			Type: schema.TypeInt,
+		},
+		"name": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"rd": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"description": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"sections": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"edit_date": &schema.Schema{
+			Type: schema.TypeString,
+		},
+		"custom_fields": &schema.Schema{
+			Type: schema.TypeMap,
+		},
+	}
+}
+
+// dataSourceVRFSchema returns the schema for the phpipam_vrf data source. It
+// sets the searchable fields and sets up the attribute conflicts between VRF
+// entry ID, name and route distinguisher. It also ensures that all fields are
+// computed as well.
+func dataSourceVRFSchema() map[string]*schema.Schema {
+	schema := bareVRFSchema()
+	for k, v := range schema {
+		switch k {
+		case "vrf_id":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"name", "rd"}
+		case "name":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"vrf_id", "rd"}
+		case "rd":
+			v.Optional = true
+			v.Computed = true
+			v.ConflictsWith = []string{"vrf_id", "name"}
+		default:
+			v.Computed = true
+		}
+	}
+	return schema
+}
+
+// flattenVRF sets fields in a *schema.ResourceData with fields supplied by
+// the input vrfs.VRF. This is used in read operations.
+func flattenVRF(v vrfs.VRF, d *schema.ResourceData) {
+	d.SetId(strconv.Itoa(v.ID))
+	d.Set("vrf_id", v.ID)
+	d.Set("name", v.Name)
+	d.Set("rd", v.RD)
+	d.Set("description", v.Description)
+	d.Set("sections", v.Sections)
+	d.Set("edit_date", v.EditDate)
+}